@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/report"
+)
+
+// Exit codes. The most serious outcome of a run decides its code:
+// errors at or above the -fail-on severity first, then lines that
+// failed to decode, then codes missing from the tables. Invalid flags
+// exit with 2, as set by the flag package.
+const (
+	exitError       = 1 // usage or I/O error
+	exitParseError  = 3 // a line failed to decode
+	exitUnknownCode = 4 // a code is not in the tables
+	exitMinor       = 5 // a minor error was found
+	exitMajor       = 6 // a major error was found
+	exitUnrecovered = 7 // an unrecovered error was found
+	exitUncontained = 8 // an uncontained error was found
+)
+
+var severityExitCode = map[decoder.Severity]int{
+	decoder.SeverityMinor:       exitMinor,
+	decoder.SeverityMajor:       exitMajor,
+	decoder.SeverityUnrecovered: exitUnrecovered,
+	decoder.SeverityUncontained: exitUncontained,
+}
+
+// exitStatus is returned by a command to exit with a code other than
+// exitError, without printing an error
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// failOnFlag defines the -fail-on flag. SeverityNone disables failing
+// on errors.
+func failOnFlag(flags *flag.FlagSet) *decoder.Severity {
+	failOn := decoder.SeverityMinor
+	flags.Func("fail-on", "lowest error severity failing the run: minor, major, unrecovered, uncontained or none (default minor)", func(s string) error {
+		severity, ok := decoder.ParseSeverity(s)
+		if !ok {
+			return fmt.Errorf("unknown severity %q", s)
+		}
+		failOn = severity
+		return nil
+	})
+
+	return &failOn
+}
+
+// checkResult collects the outcome of decoding a log
+type checkResult struct {
+	records     int
+	parseErrors int
+	unknown     int
+	errors      map[decoder.Severity]int
+}
+
+func (c *checkResult) add(record decoder.Record, err error) {
+	if err != nil {
+		c.parseErrors++
+		return
+	}
+
+	c.records++
+	if record.IsUnknown() {
+		c.unknown++
+	}
+	if record.Kind == decoder.KindError {
+		if c.errors == nil {
+			c.errors = make(map[decoder.Severity]int)
+		}
+		c.errors[record.Severity]++
+	}
+}
+
+// exitCode returns the exit code of the run for the -fail-on severity
+func (c *checkResult) exitCode(failOn decoder.Severity) int {
+	if failOn != decoder.SeverityNone {
+		for severity := decoder.SeverityUncontained; severity >= failOn; severity-- {
+			if c.errors[severity] > 0 {
+				return severityExitCode[severity]
+			}
+		}
+	}
+
+	switch {
+	case c.parseErrors > 0:
+		return exitParseError
+	case c.unknown > 0:
+		return exitUnknownCode
+	default:
+		return 0
+	}
+}
+
+// err returns the exit code of the run as an error, or nil
+func (c *checkResult) err(failOn decoder.Severity) error {
+	if code := c.exitCode(failOn); code != 0 {
+		return exitStatus(code)
+	}
+
+	return nil
+}
+
+// writeReport writes a test report to the named file, or stdout for "-"
+func writeReport(name string, write func(io.Writer) error) error {
+	if name == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// runCheck decodes a boot log and sets the exit code from its errors,
+// for CI pipelines gating on boot tests
+func runCheck(args []string) error {
+	flags := newFlagSet("check")
+	failOn := failOnFlag(flags)
+	junit := flags.String("junit", "", "write the boot phases and errors as JUnit XML test cases to the file (- for stdout)")
+	tap := flags.String("tap", "", "write the boot phases and errors as TAP test cases to the file (- for stdout)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("check")
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	var result checkResult
+	var records []decoder.Record
+	var parseErrors []error
+	err = decoder.NewDefaultPipeline(0).Decode(input, func(record decoder.Record, err error) error {
+		result.add(record, err)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			parseErrors = append(parseErrors, err)
+			return nil
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *junit != "" || *tap != "" {
+		name := filepath.Base(flags.Arg(0))
+		if flags.Arg(0) == "-" {
+			name = "stdin"
+		}
+
+		analysis.AssignPhases(records)
+		suite := report.NewTestSuite(name, records, parseErrors, *failOn)
+
+		if *junit != "" {
+			err := writeReport(*junit, func(w io.Writer) error { return report.WriteJUnit(w, suite) })
+			if err != nil {
+				return err
+			}
+		}
+		if *tap != "" {
+			err := writeReport(*tap, func(w io.Writer) error { return report.WriteTAP(w, suite) })
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// keep stdout for the report
+	out := os.Stdout
+	if *junit == "-" || *tap == "-" {
+		out = os.Stderr
+	}
+
+	fmt.Fprintf(out, "%-17s: %d\n", "Records", result.records)
+	fmt.Fprintf(out, "%-17s: %d\n", "Parse errors", result.parseErrors)
+	fmt.Fprintf(out, "%-17s: %d\n", "Unknown codes", result.unknown)
+	for severity := decoder.SeverityMinor; severity <= decoder.SeverityUncontained; severity++ {
+		fmt.Fprintf(out, "%-17s: %d\n", severity, result.errors[severity])
+	}
+
+	code := result.exitCode(*failOn)
+	if code == 0 {
+		fmt.Fprintf(out, "%-17s: PASS\n", "Result")
+		return nil
+	}
+	fmt.Fprintf(out, "%-17s: FAIL (exit status %d)\n", "Result", code)
+
+	return exitStatus(code)
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCheck(t *testing.T) {
+	const (
+		progress    = "PROGRESS CODE: V03020003 I0"
+		parseError  = "PROGRESS CODE: VXYZ I0"
+		unknown     = "PROGRESS CODE: V7F7F7F7F I0"
+		minor       = "ERROR: C40000002:V010E0005 I0"
+		major       = "ERROR: C80000002:V010E0005 I0"
+		unrecovered = "ERROR: C90000002:V010E0005 I0"
+		uncontained = "ERROR: CA0000002:V010E0005 I0"
+	)
+
+	tests := []struct {
+		name   string
+		lines  []string
+		failOn string
+		want   int
+	}{
+		{"clean", []string{progress}, "", 0},
+		{"clean, none", []string{progress}, "none", 0},
+		{"minor", []string{progress, minor}, "", exitMinor},
+		{"minor, fail on major", []string{progress, minor}, "major", 0},
+		{"major", []string{minor, major, progress}, "minor", exitMajor},
+		{"major, fail on major", []string{minor, major}, "major", exitMajor},
+		{"major, fail on unrecovered", []string{minor, major}, "unrecovered", 0},
+		{"unrecovered", []string{unrecovered, major}, "", exitUnrecovered},
+		{"uncontained", []string{uncontained}, "uncontained", exitUncontained},
+		{"unrecovered, fail on uncontained", []string{unrecovered}, "uncontained", 0},
+		{"all", []string{progress, parseError, unknown, minor, major, unrecovered, uncontained}, "", exitUncontained},
+		{"all, fail on none", []string{progress, parseError, unknown, minor, major, unrecovered, uncontained}, "none", exitParseError},
+		// errors below -fail-on leave the decoding outcome
+		{"parse error, fail on major", []string{parseError, minor}, "major", exitParseError},
+		{"unknown code", []string{progress, unknown}, "", exitUnknownCode},
+		{"unknown code, fail on none", []string{unknown, uncontained}, "none", exitUnknownCode},
+		{"unknown code and minor", []string{unknown, minor}, "minor", exitMinor},
+		{"unknown code and minor, fail on major", []string{unknown, minor}, "major", exitUnknownCode},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := filepath.Join(dir, fmt.Sprintf("boot%d.log", i))
+			if err := os.WriteFile(log, []byte(strings.Join(tt.lines, "\n")+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			args := []string{log}
+			if tt.failOn != "" {
+				args = append([]string{"-fail-on", tt.failOn}, args...)
+			}
+
+			err := runCheck(args)
+
+			got := 0
+			var status exitStatus
+			if errors.As(err, &status) {
+				got = int(status)
+			} else if err != nil {
+				t.Fatalf("runCheck() error = %v, want an exit status", err)
+			}
+			if got != tt.want {
+				t.Errorf("runCheck(%v) exit status = %d, want %d", args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCheckUsage(t *testing.T) {
+	if err := runCheck(nil); err == nil || !strings.HasPrefix(err.Error(), "usage: bpd check") {
+		t.Errorf("runCheck() error = %v, want the usage", err)
+	}
+
+	var status exitStatus
+	if err := runCheck([]string{filepath.Join(t.TempDir(), "missing.log")}); err == nil || errors.As(err, &status) {
+		t.Errorf("runCheck() error = %v, want an I/O error", err)
+	}
+}
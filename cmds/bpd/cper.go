@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/cper"
+)
+
+// runCPER decodes the error records of a BERT region or CPER file
+func runCPER(args []string) error {
+	flags := newFlagSet("cper")
+	asJSON := flags.Bool("json", false, "write the report as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("cper")
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	records, err := cper.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("%s: %v", flags.Arg(0), err)
+	}
+
+	if *asJSON {
+		return cper.WriteJSON(os.Stdout, records)
+	}
+
+	return cper.WriteText(os.Stdout, records)
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// runDecode decodes every recognised line of a boot log
+func runDecode(args []string) error {
+	flags := newFlagSet("decode")
+	failOn := failOnFlag(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("decode")
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	first := true
+	var result checkResult
+	err = decoder.NewDefaultPipeline(0).Decode(input, func(record decoder.Record, err error) error {
+		result.add(record, err)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil
+		}
+
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		printRecord(record)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return result.err(*failOn)
+}
+
+// readRecords decodes every recognised line of a boot log. Lines that
+// fail to decode are reported on stderr.
+func readRecords(name string) ([]decoder.Record, error) {
+	input, err := openInput(name)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	return decodeRecords(input)
+}
+
+// decodeRecords decodes every recognised line read from r
+func decodeRecords(r io.Reader) ([]decoder.Record, error) {
+	var records []decoder.Record
+	err := decoder.NewDefaultPipeline(0).Decode(r, func(record decoder.Record, err error) error {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil
+		}
+
+		records = append(records, record)
+		return nil
+	})
+
+	return records, err
+}
+
+// bootFlag defines the -boot flag, selecting a boot of a log holding
+// several, such as a reboot cycle log
+func bootFlag(flags *flag.FlagSet) *int {
+	return flags.Int("boot", 0, "only use the given boot of a log holding several, counting from 1 (default all)")
+}
+
+// splitBoots splits the records of a log into boots, as found by
+// analysis.SplitBoots, and assigns the phases of each boot. With boot
+// set, only that boot is returned. The indexes of the boots returned
+// count from 1.
+func splitBoots(records []decoder.Record, boot int) ([][]decoder.Record, []int, error) {
+	boots := analysis.SplitBoots(records)
+	indexes := make([]int, len(boots))
+	for i := range boots {
+		analysis.AssignPhases(boots[i])
+		indexes[i] = i + 1
+	}
+
+	if boot == 0 {
+		return boots, indexes, nil
+	}
+	if boot < 1 || boot > len(boots) {
+		return nil, nil, fmt.Errorf("boot %d not found, the log holds %d boots", boot, len(boots))
+	}
+
+	return boots[boot-1 : boot], indexes[boot-1 : boot], nil
+}
+
+// selectBoot returns the records of the selected boot, or of all boots
+// if boot is 0, with the phases of each boot assigned
+func selectBoot(records []decoder.Record, boot int) ([]decoder.Record, error) {
+	boots, _, err := splitBoots(records, boot)
+	if err != nil || boot == 0 {
+		return records, err
+	}
+
+	return boots[0], nil
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+// Decoders register themselves with pkg/decoder when imported. To plug
+// in another line format, add its package here.
+import (
+	_ "github.com/nhivp/boot-progress-decoder/pkg/amd"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/coreboot"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/edk2"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/fsp"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/tfa"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/uboot"
+)
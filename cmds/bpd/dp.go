@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/dp"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// importDP joins the module timing of the EDK2 dp command output with
+// the status codes of a boot log
+func importDP(args []string) error {
+	flags := newFlagSet("import dp")
+	logName := flags.String("log", "", "boot log holding the error codes and DEBUG_LOAD lines of the same boot")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("import dp")
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	modules, err := dp.Parse(input)
+	if err != nil {
+		return err
+	}
+
+	var records []decoder.Record
+	names := make(map[edk2.GUID]string)
+	if *logName != "" {
+		log, err := openInput(*logName)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(log)
+		log.Close()
+		if err != nil {
+			return err
+		}
+
+		if records, err = decodeRecords(bytes.NewReader(data)); err != nil {
+			return err
+		}
+		if names, err = dp.ReadModuleNames(bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+
+	reports := dp.Correlate(modules, records, names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Module\tGUID\tDuration\tStatus Codes")
+	for _, report := range reports {
+		name, guid, duration := report.Name, "-", "-"
+		if name == "" {
+			name = "-"
+		}
+		if report.HasGUID {
+			guid = report.GUID.String()
+		}
+		if report.HasDuration {
+			duration = report.Duration.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", name, guid, duration, len(report.Codes))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		if len(report.Codes) == 0 {
+			continue
+		}
+
+		name := report.Name
+		if name == "" {
+			name = report.GUID.String()
+		}
+		fmt.Printf("\n%s:\n", name)
+		for _, code := range report.Codes {
+			desc := []string{code.Class, code.Subclass, code.Operation}
+			if code.Kind == decoder.KindError {
+				desc = append([]string{code.Severity.String()}, desc...)
+			}
+			fmt.Printf("  %s\n    %s\n", strings.TrimSpace(code.Line), strings.Join(desc, " / "))
+		}
+	}
+
+	return nil
+}
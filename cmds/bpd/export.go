@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/report"
+)
+
+// bootStart returns the time of the processor reset of a boot log: the
+// -start flag, or else the modification time of the log less its last
+// time stamp, as the log was last written at the end of the boot
+func bootStart(name, start string, end time.Duration) (time.Time, error) {
+	if start != "" {
+		return time.Parse(time.RFC3339Nano, start)
+	}
+
+	if name != "-" {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return info.ModTime().Add(-end), nil
+	}
+
+	return time.Now().Add(-end), nil
+}
+
+// runExport converts a decoded boot log for trace viewers and
+// telemetry collectors
+func runExport(args []string) error {
+	flags := newFlagSet("export")
+	format := flags.String("format", "chrome-trace", "output format: chrome-trace or otlp")
+	output := flags.String("o", "-", "output file (- for stdout)")
+	boot := bootFlag(flags)
+	start := flags.String("start", "", "RFC 3339 time of the processor reset, for otlp (default the log modification time less the boot duration)")
+	endpoint := flags.String("otlp-endpoint", "", "push the otlp trace to the OTLP/HTTP collector URL instead of writing it")
+	headers := make(map[string]string)
+	flags.Func("otlp-header", "header `name=value` of the otlp push request, may be repeated", func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("header %q is not name=value", s)
+		}
+		headers[name] = value
+		return nil
+	})
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 1 {
+		return usageError("export")
+	}
+	if *endpoint != "" && *format != "otlp" {
+		return fmt.Errorf("-otlp-endpoint needs -format otlp")
+	}
+
+	records, err := readRecords(args[0])
+	if err != nil {
+		return err
+	}
+	if records, err = selectBoot(records, *boot); err != nil {
+		return err
+	}
+
+	name := filepath.Base(args[0])
+	if args[0] == "-" {
+		name = "stdin"
+	}
+	if *boot != 0 {
+		name += fmt.Sprintf(" boot %d", *boot)
+	}
+
+	switch *format {
+	case "chrome-trace":
+		return writeReport(*output, func(w io.Writer) error {
+			return report.WriteChromeTrace(w, name, records)
+		})
+
+	case "otlp":
+		var end time.Duration
+		for _, record := range records {
+			if record.HasTimestamp {
+				end = record.Timestamp
+			}
+		}
+		reset, err := bootStart(args[0], *start, end)
+		if err != nil {
+			return err
+		}
+
+		trace, err := report.NewOTLPTrace(name, reset, records)
+		if err != nil {
+			return err
+		}
+		if *endpoint != "" {
+			client := &http.Client{Timeout: 30 * time.Second}
+			return trace.Push(client, *endpoint, headers)
+		}
+		return writeReport(*output, trace.Write)
+
+	default:
+		return fmt.Errorf("unknown export format %q", *format)
+	}
+}
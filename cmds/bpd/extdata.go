@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// isHexDump reports whether the data is text, a hex dump, rather than
+// the binary structure itself
+func isHexDump(data []byte) bool {
+	return !bytes.ContainsFunc(data, func(c rune) bool {
+		return c == 0 || c == unicode.ReplacementChar || !unicode.IsPrint(c) && !unicode.IsSpace(c)
+	})
+}
+
+// cutStatusCodeLine returns the status code line of a log excerpt, and
+// the hex dump of its data that follows it
+func cutStatusCodeLine(text string) (string, string) {
+	var code string
+	var dump []string
+	for _, line := range strings.Split(text, "\n") {
+		if _, err := edk2.ParseStatusCodeLine(line); err == nil && code == "" {
+			code = line
+			continue
+		}
+		dump = append(dump, line)
+	}
+
+	return code, strings.Join(dump, "\n")
+}
+
+// runExtData decodes the extended data reported with a status code
+func runExtData(args []string) error {
+	flags := newFlagSet("extdata")
+	codeLine := flags.String("code", "", "status code line the data was reported with, which selects the format of specific data")
+	record := flags.Bool("record", false, "the input is a data hub status code record, holding the code before its data")
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 1 {
+		return usageError("extdata")
+	}
+
+	input, err := openInput(args[0])
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(input)
+	input.Close()
+	if err != nil {
+		return err
+	}
+
+	if isHexDump(data) {
+		line, dump := cutStatusCodeLine(string(data))
+		if *codeLine == "" {
+			*codeLine = line
+		}
+		if data, err = edk2.ParseHexDump(dump); err != nil {
+			return fmt.Errorf("%s: %v", args[0], err)
+		}
+	}
+
+	var code edk2.StatusCode
+	var extData edk2.StatusCodeData
+	if *record {
+		code, extData, err = edk2.ParseStatusCodeRecord(data)
+	} else {
+		extData, err = edk2.ParseStatusCodeData(data)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", args[0], err)
+	}
+	if *codeLine != "" {
+		if code, err = edk2.ParseStatusCodeLine(*codeLine); err != nil {
+			return err
+		}
+	}
+
+	if *record || *codeLine != "" {
+		desc := edk2.DescribeStatusCode(code)
+		fmt.Println(code)
+		fmt.Printf("%-16s:  %s, %s, %s\n", "Status Code", desc.Class, desc.Subclass, desc.Operation)
+	}
+	for _, field := range extData.Fields(code) {
+		fmt.Printf("%-16s:  %s\n", field.Name, field.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/fpdt"
+)
+
+// readPerformanceRecords reads the FBPT records of a comma separated
+// list of table dump files, shifted by offset
+func readPerformanceRecords(paths string, offset time.Duration) ([]decoder.Record, error) {
+	dump, err := fpdt.ReadDump(strings.Split(paths, ",")...)
+	if err != nil {
+		return nil, err
+	}
+
+	perf, err := dump.Performance()
+	if err != nil {
+		return nil, err
+	}
+	if perf.FBPT == nil {
+		return nil, fmt.Errorf("no FBPT in the table dump")
+	}
+
+	records := perf.FBPT.Records()
+	for i := range records {
+		records[i].Timestamp += offset
+	}
+
+	return records, nil
+}
+
+// runFPDT prints the firmware performance records of an ACPI table dump
+func runFPDT(args []string) error {
+	flags := newFlagSet("fpdt")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		return usageError("fpdt")
+	}
+
+	dump, err := fpdt.ReadDump(flags.Args()...)
+	if err != nil {
+		return err
+	}
+
+	perf, err := dump.Performance()
+	if err != nil {
+		return err
+	}
+
+	if perf.FPDT.FBPTAddress != 0 || perf.FPDT.S3PTAddress != 0 {
+		fmt.Printf("FBPT @ 0x%016X\n", perf.FPDT.FBPTAddress)
+		fmt.Printf("S3PT @ 0x%016X\n\n", perf.FPDT.S3PTAddress)
+	}
+
+	if s3pt := perf.S3PT; s3pt != nil {
+		if r := s3pt.Resume; r != nil {
+			fmt.Printf("S3 Resume : count %d, last %s, average %s\n",
+				r.ResumeCount, time.Duration(r.FullResume), time.Duration(r.AverageResume))
+		}
+		if s := s3pt.Suspend; s != nil {
+			fmt.Printf("S3 Suspend: %s\n", time.Duration(s.SuspendEnd-s.SuspendStart))
+		}
+		fmt.Println()
+	}
+
+	if perf.FBPT == nil {
+		return nil
+	}
+
+	records := perf.FBPT.Records()
+	analysis.AssignPhases(records)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Time\tPhase\tEvent\tName\tModule")
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			formatDuration(record.Timestamp, true),
+			record.Phase,
+			record.Subclass,
+			record.Operation,
+			record.Module)
+	}
+
+	return w.Flush()
+}
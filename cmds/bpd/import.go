@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/bmc"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// openInput opens the named file, or stdin for "-"
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(name)
+}
+
+func runImport(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: bpd import <dp | ipmi | postcode | redfish> [options] <file>")
+	}
+
+	switch args[0] {
+	case "dp":
+		return importDP(args[1:])
+	case "ipmi", "postcode":
+		return importPostCodes(args[0], args[1:])
+	case "redfish":
+		return importRedfish(args[1:])
+	default:
+		return fmt.Errorf("unknown import source %q", args[0])
+	}
+}
+
+func importPostCodes(source string, args []string) error {
+	flags := newFlagSet("import " + source)
+	width := flags.Int("width", bmc.DefaultWidth, "number of bytes per status code value")
+	skip := flags.Int("skip", 0, "number of leading bytes of a byte stream to skip (e.g. IPMI response header)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("import " + source)
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	var values []uint64
+	if source == "ipmi" {
+		values, err = bmc.ReadByteStream(input, *width, *skip)
+	} else {
+		values, err = bmc.ReadPostCodes(input, *width, *skip)
+	}
+	if err != nil {
+		return err
+	}
+
+	printStatusCodes(bmc.StatusCodes(values))
+
+	return nil
+}
+
+func importRedfish(args []string) error {
+	flags := newFlagSet("import redfish")
+	boot := flags.Int("boot", 0, "only decode the given boot index (1 is the most recent boot)")
+	user := flags.String("user", "", "Redfish user name")
+	password := flags.String("password", "", "Redfish password")
+	insecure := flags.Bool("insecure", false, "skip TLS certificate verification")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return usageError("import redfish")
+	}
+
+	var entries []bmc.PostCodeEntry
+	source := flags.Arg(0)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &bmc.RedfishClient{
+			Client: &http.Client{
+				Timeout: 30 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: *insecure},
+				},
+			},
+			User:     *user,
+			Password: *password,
+		}
+
+		var err error
+		entries, err = client.FetchRedfishEntries(source)
+		if err != nil {
+			return err
+		}
+	} else {
+		input, err := openInput(source)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+
+		entries, _, err = bmc.ParseRedfishEntries(input)
+		if err != nil {
+			return err
+		}
+	}
+
+	found := false
+	for _, b := range bmc.GroupByBoot(entries) {
+		if *boot != 0 && b.Index != *boot {
+			continue
+		}
+		found = true
+
+		fmt.Printf("=== Boot %d ===\n", b.Index)
+		printStatusCodes(b.StatusCodes())
+		fmt.Println()
+	}
+	if *boot != 0 && !found {
+		return fmt.Errorf("boot %d not found in the POST code entries", *boot)
+	}
+
+	return nil
+}
+
+func printStatusCodes(codes []edk2.StatusCode) {
+	for i, code := range codes {
+		if i > 0 {
+			fmt.Println()
+		}
+		printRecord(code.Record())
+	}
+}
@@ -1,86 +1,53 @@
 // SPDX-License-Identifier: BSD-3-Clause
 // Copyright (c) 2024 Nhi Pham
 
-// This program decodes UEFI boot progress and error codes from EDK2.
-// It supports the following formats:
+// This program decodes firmware boot progress and error codes. It
+// supports the following formats:
 //
-// Progress Code: PROGRESS CODE: V03020003 I0
-// Error Code:    ERROR: C000000002:V03058002 I0 6D33944A-EC75-4855-A54D-809C75241F6C
+// EDK2 Progress Code:  PROGRESS CODE: V03020003 I0
+// EDK2 Error Code:     ERROR: C000000002:V03058002 I0 6D33944A-EC75-4855-A54D-809C75241F6C
+// FSP/AMD Post Code:   POST CODE: 0xD800
+// coreboot Console:    [INFO ]  BS: BS_DEV_INIT run times (exec / console): 12 / 3 ms
+// U-Boot Console:      U-Boot SPL 2024.01 (Jan 01 2024 - 00:00:00 +0000), bootstage report
+// TF-A Console:        NOTICE:  BL31: v2.10.0(release):v2.10.0, crash reports
 //
 // The decoder extracts and interprets the hexadecimal codes, providing
 // human-readable descriptions for class, subclass, and operation.
+//
+// It also decodes the POST code history captured by a BMC (IPMI, BMC
+// post code files and Redfish log entries), the CPER records of the
+// BERT, the ACPI FPDT performance records, the extended data of status
+// codes and the consoles forwarded by BMCs over syslog.
+//
+// Each line format is handled by a decoder registered with pkg/decoder.
+// The decoders linked into the program are listed in decoders.go. The
+// commands and their usage are listed in usage.go.
 
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
-	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
 )
 
-func handleProgressCode(progressCode string) {
-	progressCode = strings.TrimSpace(progressCode)
-	statusCodeValue := strings.TrimPrefix(progressCode, "PROGRESS CODE:")
-	statusCodeValue = strings.TrimSuffix(statusCodeValue, "I0")
-
-	classDesc, subclassDesc, operationDesc, _ := edk2.DecodeStatusValue(statusCodeValue, false)
-
-	fmt.Println(progressCode)
-	fmt.Println("Class     : ", classDesc)
-	fmt.Println("Subclass  : ", subclassDesc)
-	fmt.Println("Operation : ", operationDesc)
-}
-
-func handleErrorCode(statusCode string) {
-	statusCode = strings.TrimSpace(statusCode)
-	errorCode := strings.TrimPrefix(statusCode, "ERROR: ")
-
-	parts := strings.Split(errorCode, " ")
-
-	if len(parts) != 3 {
-		fmt.Println("invalid error code format")
-		return
+func printRecord(record decoder.Record) {
+	fmt.Println(strings.TrimSpace(record.Line))
+	if record.Kind == decoder.KindError {
+		fmt.Println("Severity  : ", record.Severity)
 	}
-
-	callerID := parts[len(parts)-1]
-	parts = strings.Split(parts[0], ":")
-	if len(parts) != 2 {
-		fmt.Println("invalid error code format")
-		return
+	fmt.Println("Class     : ", record.Class)
+	fmt.Println("Subclass  : ", record.Subclass)
+	fmt.Println("Operation : ", record.Operation)
+	if record.Module != "" {
+		fmt.Println("Module    : ", record.Module)
+	}
+	for _, field := range record.Fields {
+		fmt.Printf("%-10s:  %s\n", field.Name, field.Value)
 	}
-
-	statusCodeType := parts[0]
-	statusCodeValue := parts[1]
-
-	classDesc, subclassDesc, operationDesc, _ := edk2.DecodeStatusValue(statusCodeValue, true)
-
-	_, severityDesc, _ := edk2.DecodeStatusType(statusCodeType)
-
-	fmt.Println(errorCode)
-	fmt.Println("Severity  : ", severityDesc)
-	fmt.Println("Class     : ", classDesc)
-	fmt.Println("Subclass  : ", subclassDesc)
-	fmt.Println("Operation : ", operationDesc)
-	fmt.Println("Module    : ", callerID)
-}
-
-func helpString() string {
-	return `Usage: boot-progress-decoder <PROGRESS CODE line | ERROR line>
-
-Decodes a single UEFI boot progress or error code line.
-
-The input should be a single line in one of the following formats:
-  - Progress codes: PROGRESS CODE: V<hex_code> ...
-  - Error codes: ERROR: C<status_code_type>:V<hex_code> ...
-
-Examples:
-  boot-progress-decoder "PROGRESS CODE: V03020003 I0"
-  boot-progress-decoder "ERROR: C40000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E"
-
-Output:
-  The decoded information will be displayed in a formatted table for the input line.`
 }
 
 func main() {
@@ -90,16 +57,54 @@ func main() {
 		fmt.Println(helpString())
 		return
 	}
+	switch os.Args[1] {
+	case "-h", "-help", "--help", "help":
+		fmt.Println(helpString())
+		return
+	}
 
-	// Get the status code from the first command-line argument
-	statusCode := os.Args[1]
+	handlers := map[string]func([]string) error{
+		"check":    runCheck,
+		"cper":     runCPER,
+		"decode":   runDecode,
+		"export":   runExport,
+		"extdata":  runExtData,
+		"fpdt":     runFPDT,
+		"import":   runImport,
+		"postcode": runPostCode,
+		"qemu":     runQEMU,
+		"report":   runReport,
+		"serial":   runSerial,
+		"stats":    runStats,
+		"syslog":   runSyslog,
+		"timeline": runTimeline,
+		"watch":    runWatch,
+	}
+	if run, ok := handlers[os.Args[1]]; ok {
+		if err := run(os.Args[2:]); err != nil {
+			var status exitStatus
+			if errors.As(err, &status) {
+				os.Exit(int(status))
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		return
+	}
 
-	// Check if it's a progress code or error code based on the prefix
-	if strings.HasPrefix(statusCode, "PROGRESS CODE:") {
-		handleProgressCode(statusCode)
-	} else if strings.HasPrefix(statusCode, "ERROR:") {
-		handleErrorCode(statusCode)
+	// Decode the line with the first registered decoder that
+	// recognises its format
+	record, ok, err := decoder.NewDefaultScanner().DecodeLine(os.Args[1])
+	if !ok {
+		fmt.Println("Invalid input line. No decoder recognises its format.")
+		os.Exit(exitParseError)
+	} else if err != nil {
+		fmt.Println(err)
 	} else {
-		fmt.Println("Invalid input line. Must start with 'PROGRESS CODE:' or 'ERROR:'.")
+		printRecord(record)
 	}
+
+	var result checkResult
+	result.add(record, err)
+	os.Exit(result.exitCode(decoder.SeverityMinor))
 }
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+	"github.com/nhivp/boot-progress-decoder/pkg/postcode"
+)
+
+// runPostCode maps between 8-bit checkpoint bytes and status codes
+// using a platform table
+func runPostCode(args []string) error {
+	flags := newFlagSet("postcode")
+	tablePath := flags.String("table", "", "platform status code to checkpoint table file")
+	flags.Parse(args)
+
+	if *tablePath == "" || flags.NArg() != 1 {
+		return usageError("postcode")
+	}
+
+	table, err := postcode.LoadTable(*tablePath)
+	if err != nil {
+		return err
+	}
+
+	input := strings.TrimSpace(flags.Arg(0))
+	if strings.HasPrefix(input, "PROGRESS CODE:") || strings.HasPrefix(input, "ERROR:") {
+		code, err := edk2.ParseStatusCodeLine(input)
+		if err != nil {
+			return err
+		}
+
+		postCode, ok := table.PostCode(code)
+		if !ok {
+			return fmt.Errorf("%s: no checkpoint in %s", code, *tablePath)
+		}
+
+		fmt.Printf("%s\nPost Code :  0x%02X\n", code, postCode)
+		return nil
+	}
+
+	value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(input), "0x"), 16, 8)
+	if err != nil {
+		return fmt.Errorf("invalid post code %q", input)
+	}
+
+	codes := table.Lookup(uint8(value))
+	if len(codes) == 0 {
+		return fmt.Errorf("post code 0x%02X: no status code in %s", value, *tablePath)
+	}
+
+	printStatusCodes(codes)
+
+	return nil
+}
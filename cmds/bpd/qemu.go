@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/metrics"
+)
+
+// chardevAddress returns the network and address of a QEMU chardev
+// socket: "unix:<path>", "tcp:<host>:<port>", a path or host:port
+func chardevAddress(arg string) (string, string) {
+	if path, ok := strings.CutPrefix(arg, "unix:"); ok {
+		return "unix", path
+	}
+	if addr, ok := strings.CutPrefix(arg, "tcp:"); ok {
+		return "tcp", addr
+	}
+	if strings.Contains(arg, "/") {
+		return "unix", arg
+	}
+
+	return "tcp", arg
+}
+
+// qemuConsole decodes the debug console of a VM, across connections,
+// and saves the lines of each boot to its own file
+type qemuConsole struct {
+	*consoleDecoder
+	dir  string
+	boot int
+	log  *os.File
+}
+
+// startBoot starts the next boot, and its log file if a directory is set
+func (q *qemuConsole) startBoot() {
+	q.boot++
+	q.printer.mu.Lock()
+	fmt.Fprintf(q.printer.out, "%s: === boot %d ===\n", q.name, q.boot)
+	q.printer.mu.Unlock()
+
+	if q.dir == "" {
+		return
+	}
+	if q.log != nil {
+		q.log.Close()
+	}
+
+	var err error
+	name := filepath.Join(q.dir, fmt.Sprintf("%s-boot-%03d.log", q.name, q.boot))
+	if q.log, err = os.Create(name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// read decodes a connection until it closes. The first line of a
+// connection starts a new boot, as QEMU connects when the VM starts.
+func (q *qemuConsole) read(conn io.Reader) error {
+	q.boots.Reset()
+
+	lines := bufio.NewScanner(conn)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for first := true; lines.Scan(); first = false {
+		if first {
+			q.startBoot()
+		}
+
+		line := lines.Text()
+		q.decodeLine(line)
+
+		if q.log != nil {
+			fmt.Fprintln(q.log, strings.TrimRight(line, "\r"))
+		}
+	}
+
+	return lines.Err()
+}
+
+func (q *qemuConsole) close() {
+	if q.log != nil {
+		q.log.Close()
+	}
+}
+
+// dialLoop connects to the chardev socket served by QEMU, connecting
+// again when the VM restarts
+func (q *qemuConsole) dialLoop(network, addr string, retry time.Duration) error {
+	waiting := false
+	for {
+		conn, err := net.Dial(network, addr)
+		if err != nil {
+			if !waiting {
+				fmt.Fprintf(os.Stderr, "%s: waiting for %s\n", q.name, addr)
+				waiting = true
+			}
+			time.Sleep(retry)
+			continue
+		}
+		waiting = false
+
+		fmt.Fprintf(os.Stderr, "%s: connected to %s\n", q.name, addr)
+		err = q.read(conn)
+		conn.Close()
+		fmt.Fprintf(os.Stderr, "%s: disconnected from %s", q.name, addr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, ": %v", err)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// listenLoop accepts the connections of QEMU, one at a time
+func (q *qemuConsole) listenLoop(network, addr string) error {
+	if network == "unix" {
+		// remove the socket left by an earlier run
+		if info, err := os.Stat(addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(addr)
+		}
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	fmt.Fprintf(os.Stderr, "%s: listening on %s\n", q.name, l.Addr())
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: connection from %s\n", q.name, conn.RemoteAddr())
+		err = q.read(conn)
+		conn.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", q.name, err)
+		}
+	}
+}
+
+// runQEMU decodes the debug console of a QEMU VM from a chardev socket
+func runQEMU(args []string) error {
+	flags := newFlagSet("qemu")
+	listen := flags.Bool("listen", false, "listen for QEMU to connect, rather than connecting to QEMU")
+	retry := flags.Duration("retry", time.Second, "interval between connection attempts")
+	dir := flags.String("o", "", "directory to save the log of each boot to")
+	name := flags.String("name", "qemu", "console name")
+	addr := flags.String("metrics", "", "serve Prometheus metrics on /metrics at the address, e.g. :9100")
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 1 {
+		return usageError("qemu")
+	}
+
+	var m *metrics.Metrics
+	if *addr != "" {
+		m = metrics.New()
+		serveMetrics(*addr, m)
+	}
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	q := &qemuConsole{
+		consoleDecoder: newConsoleDecoder(*name, m, &consolePrinter{out: os.Stdout}),
+		dir:            *dir,
+	}
+	q.newBoot = q.startBoot
+	defer q.close()
+
+	network, address := chardevAddress(args[0])
+	if *listen {
+		return q.listenLoop(network, address)
+	}
+
+	return q.dialLoop(network, address, *retry)
+}
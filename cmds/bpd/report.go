@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/report"
+)
+
+// parseInterspersed parses flags placed before or after the positional
+// arguments, e.g. "report boot.log -o report.html", and returns the
+// positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// runReport writes a self-contained HTML report of a boot log
+func runReport(args []string) error {
+	flags := newFlagSet("report")
+	output := flags.String("o", "report.html", "output file (- for stdout)")
+	title := flags.String("title", "", "report title (default the log file name)")
+	boot := bootFlag(flags)
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 1 {
+		return usageError("report")
+	}
+
+	records, err := readRecords(args[0])
+	if err != nil {
+		return err
+	}
+	if records, err = selectBoot(records, *boot); err != nil {
+		return err
+	}
+
+	if *title == "" {
+		*title = filepath.Base(args[0])
+		if *boot != 0 {
+			*title += fmt.Sprintf(" boot %d", *boot)
+		}
+	}
+
+	return writeReport(*output, func(w io.Writer) error {
+		return report.WriteHTML(w, *title, records)
+	})
+}
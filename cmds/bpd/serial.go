@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/metrics"
+	"github.com/nhivp/boot-progress-decoder/pkg/serial"
+)
+
+// runSerial captures a firmware console from a serial port, saving the
+// raw log and decoding its lines as they arrive
+func runSerial(args []string) error {
+	flags := newFlagSet("serial")
+	baud := flags.Int("baud", serial.DefaultBaud, "line speed")
+	output := flags.String("o", "", "raw log file, appended to (default <device>-<date>.log)")
+	name := flags.String("name", "", "console name (default the device name)")
+	addr := flags.String("metrics", "", "serve Prometheus metrics on /metrics at the address, e.g. :9100")
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 1 {
+		return usageError("serial")
+	}
+
+	device := filepath.Base(args[0])
+	if *name == "" {
+		*name = device
+	}
+	if *output == "" {
+		*output = fmt.Sprintf("%s-%s.log", device, time.Now().Format("20060102-150405"))
+	}
+
+	port, err := serial.Open(args[0], *baud)
+	if err != nil {
+		return err
+	}
+	defer port.Close()
+
+	raw, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer raw.Close()
+	fmt.Fprintf(os.Stderr, "%s: %d baud, saving to %s\n", args[0], *baud, *output)
+
+	var m *metrics.Metrics
+	if *addr != "" {
+		m = metrics.New()
+		serveMetrics(*addr, m)
+	}
+
+	// the raw log keeps every byte, including the lines not decoded
+	err = newConsoleDecoder(*name, m, &consolePrinter{out: os.Stdout}).decode(io.TeeReader(port, raw))
+	if err != nil {
+		return fmt.Errorf("%s: %v", args[0], err)
+	}
+
+	return nil
+}
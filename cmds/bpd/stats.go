@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// expandPatterns expands glob patterns the shell left alone
+func expandPatterns(patterns []string) ([]string, error) {
+	var names []string
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			names = append(names, pattern)
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, matches...)
+	}
+
+	return names, nil
+}
+
+// summarizeLogs decodes the logs in parallel. The summaries are in the
+// order of the names. A log holding several boots is summarized per
+// boot, named "<log>#<boot>", unless boot selects one of them.
+func summarizeLogs(names []string, jobs, boot int) ([]analysis.BootSummary, error) {
+	logs := make([][]analysis.BootSummary, len(names))
+	errs := make([]error, len(names))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				records, err := readRecords(names[index])
+				if err != nil {
+					errs[index] = fmt.Errorf("%s: %v", names[index], err)
+					continue
+				}
+
+				boots, bootIndexes, err := splitBoots(records, boot)
+				if err != nil {
+					errs[index] = fmt.Errorf("%s: %v", names[index], err)
+					continue
+				}
+				if len(boots) == 0 {
+					boots, bootIndexes = [][]decoder.Record{nil}, []int{1}
+				}
+
+				for i, records := range boots {
+					name := names[index]
+					if len(boots) > 1 || boot != 0 {
+						name = fmt.Sprintf("%s#%d", name, bootIndexes[i])
+					}
+					logs[index] = append(logs[index], analysis.Summarize(name, records))
+				}
+			}
+		}()
+	}
+
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var summaries []analysis.BootSummary
+	for _, log := range logs {
+		summaries = append(summaries, log...)
+	}
+
+	return summaries, nil
+}
+
+// runStats prints aggregate statistics of many boot logs
+func runStats(args []string) error {
+	flags := newFlagSet("stats")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	table := flags.String("table", "errors", "table written as CSV: errors, last-codes or phases")
+	jobs := flags.Int("jobs", runtime.NumCPU(), "number of logs decoded in parallel")
+	boot := bootFlag(flags)
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		return usageError("stats")
+	}
+	if *jobs < 1 {
+		*jobs = 1
+	}
+
+	names, err := expandPatterns(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no boot logs match %s", strings.Join(flags.Args(), " "))
+	}
+
+	summaries, err := summarizeLogs(names, *jobs, *boot)
+	if err != nil {
+		return err
+	}
+	stats := analysis.Aggregate(summaries)
+
+	switch *format {
+	case "table":
+		return writeStatsTable(os.Stdout, stats)
+	case "csv":
+		return writeStatsCSV(os.Stdout, stats, *table)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+func describeCode(class, subclass, operation string) string {
+	return strings.Join([]string{class, subclass, operation}, " / ")
+}
+
+// represents a table of the statistics, with its header row
+type statsTable struct {
+	name string
+	rows [][]string
+}
+
+// statsTables returns the error, last code and phase tables
+func statsTables(stats analysis.Stats) []statsTable {
+	errors := [][]string{{"Code", "Severity", "Module", "Description", "Count", "Boots"}}
+	for _, e := range stats.Errors {
+		errors = append(errors, []string{
+			fmt.Sprintf("0x%08X", e.Code), e.Severity, e.Module,
+			describeCode(e.Class, e.Subclass, e.Operation),
+			strconv.Itoa(e.Count), strings.Join(e.Boots, " "),
+		})
+	}
+
+	lastCodes := [][]string{{"Last Code", "Description", "Failed Boots"}}
+	for _, l := range stats.LastCodes {
+		lastCodes = append(lastCodes, []string{
+			fmt.Sprintf("0x%08X", l.Code),
+			describeCode(l.Class, l.Subclass, l.Operation),
+			strconv.Itoa(l.Count),
+		})
+	}
+
+	phases := [][]string{{"Phase", "Boots", "Min", "P50", "P90", "P99", "Max"}}
+	for _, p := range stats.Phases {
+		phases = append(phases, []string{
+			p.Phase, strconv.Itoa(p.Boots),
+			formatDuration(p.Min, true), formatDuration(p.P50, true),
+			formatDuration(p.P90, true), formatDuration(p.P99, true),
+			formatDuration(p.Max, true),
+		})
+	}
+
+	return []statsTable{
+		{name: "errors", rows: errors},
+		{name: "last-codes", rows: lastCodes},
+		{name: "phases", rows: phases},
+	}
+}
+
+func writeStatsTable(out io.Writer, stats analysis.Stats) error {
+	fmt.Fprintf(out, "Boots: %d, failed: %d\n", stats.Boots, stats.Failed)
+
+	for _, table := range statsTables(stats) {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, row := range table.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeStatsCSV writes one of the tables, so the output is a single
+// CSV table
+func writeStatsCSV(out io.Writer, stats analysis.Stats, name string) error {
+	for _, table := range statsTables(stats) {
+		if table.name != name {
+			continue
+		}
+
+		w := csv.NewWriter(out)
+		if err := w.WriteAll(table.rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+
+	return fmt.Errorf("unknown table %q", name)
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/metrics"
+	"github.com/nhivp/boot-progress-decoder/pkg/syslog"
+)
+
+// represents a syslog message and the address it was received from
+type syslogMessage struct {
+	message syslog.Message
+	from    net.Addr
+}
+
+// syslogStream decodes the console lines of a host and application,
+// saving them and their decoded records if a directory is set
+type syslogStream struct {
+	*consoleDecoder
+	raw     *os.File
+	decoded *os.File
+}
+
+// syslogReceiver keys the messages received by hostname and app name
+// and decodes each stream on its own. The messages are handled one at a
+// time, in the order they are received.
+type syslogReceiver struct {
+	dir     string
+	metrics *metrics.Metrics
+	streams map[string]*syslogStream
+}
+
+// fileName returns a name usable as a file name
+func fileName(name string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' {
+			return c
+		}
+		return '_'
+	}, name)
+}
+
+// stream returns the stream of a message, started at its first message
+func (s *syslogReceiver) stream(m syslogMessage) (*syslogStream, error) {
+	host, app := m.message.Hostname, m.message.AppName
+	if host == syslog.Nil {
+		host, _, _ = net.SplitHostPort(m.from.String())
+	}
+	if app == syslog.Nil {
+		app = "console"
+	}
+
+	name := host + "/" + app
+	if stream, ok := s.streams[name]; ok {
+		return stream, nil
+	}
+
+	stream := &syslogStream{}
+	var out io.Writer = os.Stdout
+	if s.dir != "" {
+		base := filepath.Join(s.dir, fileName(host)+"_"+fileName(app))
+
+		var err error
+		flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		if stream.raw, err = os.OpenFile(base+".log", flags, 0o644); err != nil {
+			return nil, err
+		}
+		if stream.decoded, err = os.OpenFile(base+".decoded.log", flags, 0o644); err != nil {
+			stream.raw.Close()
+			return nil, err
+		}
+		out = io.MultiWriter(os.Stdout, stream.decoded)
+	}
+	stream.consoleDecoder = newConsoleDecoder(name, s.metrics, &consolePrinter{out: out})
+
+	s.streams[name] = stream
+	fmt.Fprintf(os.Stderr, "%s: new stream from %s\n", name, m.from)
+
+	return stream, nil
+}
+
+// handle decodes the console lines of a message. The lines take the
+// time stamp of the message, also in the saved log, so the log can be
+// decoded again with the time stamps of the stream.
+func (s *syslogReceiver) handle(m syslogMessage) error {
+	stream, err := s.stream(m)
+	if err != nil {
+		return err
+	}
+
+	stamp := ""
+	if m.message.HasTimestamp() {
+		stamp = m.message.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00") + " "
+	}
+
+	for _, line := range strings.Split(m.message.Text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if stream.raw != nil {
+			fmt.Fprintln(stream.raw, stamp+line)
+		}
+		stream.decodeLine(stamp + embeddedStatusCode(line))
+	}
+
+	return nil
+}
+
+// embeddedStatusCode cuts the text before an EDK2 status code off a
+// console line, as BMCs may prefix the console text, e.g. "SOL: "
+func embeddedStatusCode(line string) string {
+	for _, marker := range []string{"PROGRESS CODE: V", "ERROR: C"} {
+		if i := strings.Index(line, marker); i > 0 {
+			return line[i:]
+		}
+	}
+
+	return line
+}
+
+func (s *syslogReceiver) close() {
+	for _, stream := range s.streams {
+		if stream.raw != nil {
+			stream.raw.Close()
+			stream.decoded.Close()
+		}
+	}
+}
+
+// receiveUDP receives a message per datagram
+func receiveUDP(conn net.PacketConn, messages chan<- syslogMessage) error {
+	buf := make([]byte, 64*1024)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		message, err := syslog.Parse(buf[:n])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", from, err)
+			continue
+		}
+		messages <- syslogMessage{message, from}
+	}
+}
+
+// receiveTCP receives the messages of each connection
+func receiveTCP(l net.Listener, messages chan<- syslogMessage) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			from := conn.RemoteAddr()
+			r := syslog.NewReader(conn)
+			for {
+				data, err := r.Read()
+				if err != nil {
+					if err != io.EOF {
+						fmt.Fprintf(os.Stderr, "%s: %v\n", from, err)
+					}
+					return
+				}
+
+				message, err := syslog.Parse(data)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", from, err)
+					continue
+				}
+				messages <- syslogMessage{message, from}
+			}
+		}(conn)
+	}
+}
+
+// runSyslog receives the consoles that BMCs forward as RFC 5424 syslog
+// messages, over UDP and TCP, and decodes them per host
+func runSyslog(args []string) error {
+	flags := newFlagSet("syslog")
+	listen := flags.String("listen", ":5514", "UDP and TCP address to receive messages on")
+	dir := flags.String("o", "syslog", "directory to save the console and decoded log of each host to, empty to not save them")
+	addr := flags.String("metrics", "", "serve Prometheus metrics on /metrics at the address, e.g. :9100")
+	args = parseInterspersed(flags, args)
+
+	if len(args) != 0 {
+		return usageError("syslog")
+	}
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	udp, err := net.ListenPacket("udp", *listen)
+	if err != nil {
+		return err
+	}
+	defer udp.Close()
+	tcp, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	defer tcp.Close()
+	fmt.Fprintf(os.Stderr, "syslog: listening on udp %s and tcp %s\n", udp.LocalAddr(), tcp.Addr())
+
+	receiver := &syslogReceiver{dir: *dir, streams: make(map[string]*syslogStream)}
+	if *addr != "" {
+		receiver.metrics = metrics.New()
+		serveMetrics(*addr, receiver.metrics)
+	}
+	defer receiver.close()
+
+	messages := make(chan syslogMessage, 256)
+	errs := make(chan error, 2)
+	go func() { errs <- receiveUDP(udp, messages) }()
+	go func() { errs <- receiveTCP(tcp, messages) }()
+
+	for {
+		select {
+		case m := <-messages:
+			if err := receiver.handle(m); err != nil {
+				return err
+			}
+		case err := <-errs:
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+	}
+}
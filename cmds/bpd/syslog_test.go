@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/syslog"
+)
+
+func TestEmbeddedStatusCode(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"PROGRESS CODE: V03020003 I0", "PROGRESS CODE: V03020003 I0"},
+		{"SOL: PROGRESS CODE: V03020003 I0", "PROGRESS CODE: V03020003 I0"},
+		{"\x1b[0mERROR: C40000002:V02070002 I0 93B80004-9FB3-11D4-9A3A-0090273FC14D",
+			"ERROR: C40000002:V02070002 I0 93B80004-9FB3-11D4-9A3A-0090273FC14D"},
+		{"[sol@node1 ttyS0] ERROR: C80000002:V010E0005 I0",
+			"ERROR: C80000002:V010E0005 I0"},
+		// lines without a status code are left to the other decoders
+		{"SOL: Loading driver at 0x0007E5A5000 EntryPoint=0x0007E5A6BE0 PciBusDxe.efi",
+			"SOL: Loading driver at 0x0007E5A5000 EntryPoint=0x0007E5A6BE0 PciBusDxe.efi"},
+		{"SOL: PROGRESS CODE: X03020003", "SOL: PROGRESS CODE: X03020003"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := embeddedStatusCode(tt.line); got != tt.want {
+			t.Errorf("embeddedStatusCode(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReceiveTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan syslogMessage, 16)
+	errs := make(chan error, 1)
+	go func() { errs <- receiveTCP(l, messages) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := "<134>1 2024-05-01T12:00:00.123Z bmc-node1 sol - - - PROGRESS CODE: V03020003 I0"
+	second := "<134>1 2024-05-01T12:00:00.456Z bmc-node1 sol - - - PROGRESS CODE: V03040003 I0\nPROGRESS CODE: V03051001 I0"
+	stream := fmt.Sprintf("%d %s%d %s", len(first), first, len(second), second) +
+		// a message that fails to parse is skipped
+		"5 <134>" +
+		"<134>1 - bmc-node1 sol - - - ERROR: C40000002:V02070002 I0\n"
+
+	// split the frames in the length, the header and the message
+	for _, chunk := range []string{stream[:1], stream[1:30], stream[30 : len(first)+50], stream[len(first)+50:]} {
+		if _, err := conn.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	conn.Close()
+
+	want := []string{
+		"PROGRESS CODE: V03020003 I0",
+		"PROGRESS CODE: V03040003 I0\nPROGRESS CODE: V03051001 I0",
+		"ERROR: C40000002:V02070002 I0",
+	}
+	for i, text := range want {
+		select {
+		case m := <-messages:
+			if m.message.Text != text || m.message.Hostname != "bmc-node1" {
+				t.Errorf("message %d = %s %q, want bmc-node1 %q", i, m.message.Hostname, m.message.Text, text)
+			}
+			if m.from.String() != conn.LocalAddr().String() {
+				t.Errorf("message %d from %s, want %s", i, m.from, conn.LocalAddr())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %d not received", i)
+		}
+	}
+
+	l.Close()
+	if err := <-errs; !errors.Is(err, net.ErrClosed) {
+		t.Errorf("receiveTCP() error = %v, want the listener closed", err)
+	}
+}
+
+func TestSyslogHandle(t *testing.T) {
+	dir := t.TempDir()
+	receiver := &syslogReceiver{dir: dir, streams: make(map[string]*syslogStream)}
+	from := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 514}
+
+	for _, data := range []string{
+		"<134>1 2024-05-01T12:00:00.5Z bmc-node1 sol - - - SOL: PROGRESS CODE: V03020003 I0\r\n\r\nPROGRESS CODE: V03040003 I0",
+		// the stream of a host without a hostname and app name is
+		// named after its address
+		"<134>1 - - - - - - PROGRESS CODE: V03051001 I0",
+	} {
+		message, err := syslog.Parse([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := receiver.handle(syslogMessage{message, from}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receiver.close()
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		// the saved log keeps the console text and the time stamp of
+		// the message
+		{"bmc-node1_sol.log", "2024-05-01T12:00:00.500000Z SOL: PROGRESS CODE: V03020003 I0\n" +
+			"2024-05-01T12:00:00.500000Z PROGRESS CODE: V03040003 I0\n"},
+		{"bmc-node1_sol.decoded.log", "bmc-node1/sol: [PEI] Progress Code: Software / PEI Core / Init End\n" +
+			"bmc-node1/sol: [DXE] Progress Code: Software / DXE Core / Init End\n"},
+		{"10.0.0.1_console.log", "PROGRESS CODE: V03051001 I0\n"},
+	}
+	for _, tt := range tests {
+		got, err := os.ReadFile(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s =\n%s\nwant\n%s", tt.file, got, tt.want)
+		}
+	}
+}
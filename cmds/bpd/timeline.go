@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+func formatDuration(d time.Duration, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.6fs", d.Seconds())
+}
+
+// runTimeline prints the boot phases of a boot log
+func runTimeline(args []string) error {
+	flags := newFlagSet("timeline")
+	tables := flags.String("fpdt", "", "comma separated ACPI table dump files holding the FPDT and FBPT")
+	offset := flags.Duration("fpdt-offset", 0, "log time stamp of the processor reset, added to the FPDT time stamps")
+	boot := bootFlag(flags)
+	flags.Parse(args)
+
+	if flags.NArg() > 1 || flags.NArg() == 0 && *tables == "" {
+		return usageError("timeline")
+	}
+
+	var records []decoder.Record
+	if flags.NArg() == 1 {
+		var err error
+		if records, err = readRecords(flags.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	boots, indexes, err := splitBoots(records, *boot)
+	if err != nil {
+		return err
+	}
+	if len(boots) == 0 {
+		boots, indexes = [][]decoder.Record{nil}, []int{1}
+	}
+
+	if *tables != "" {
+		// the tables describe a single boot
+		if len(boots) > 1 {
+			return fmt.Errorf("the log holds %d boots, select the boot of the FPDT with -boot", len(boots))
+		}
+		perf, err := readPerformanceRecords(*tables, *offset)
+		if err != nil {
+			return err
+		}
+		boots[0] = analysis.Merge(boots[0], perf)
+		analysis.AssignPhases(boots[0])
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, records := range boots {
+		if len(boots) > 1 || *boot != 0 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "Boot %d\n", indexes[i])
+		}
+		writeTimeline(w, records)
+	}
+
+	return w.Flush()
+}
+
+// writeTimeline writes the phase table of the records of a boot
+func writeTimeline(w io.Writer, records []decoder.Record) {
+	fmt.Fprintln(w, "Phase\tStart\tEnd\tDuration\tLines\tRecords\tErrors")
+	for _, span := range analysis.Timeline(records) {
+		lines := "-"
+		if span.FirstLine != 0 {
+			lines = fmt.Sprintf("%d-%d", span.FirstLine, span.LastLine)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			span.Phase,
+			formatDuration(span.Start, span.HasTimestamp),
+			formatDuration(span.End, span.HasTimestamp),
+			formatDuration(span.Duration(), span.HasTimestamp),
+			lines,
+			span.Records, span.Errors)
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+)
+
+// the FPDT and FBPT of an OVMF boot, see pkg/fpdt/fpdt_test.go
+const fpdtTables = "../../pkg/fpdt/testdata/acpidump.txt,../../pkg/fpdt/testdata/fbpt.bin"
+
+// the console log of the boot, the last code without a time stamp
+const timelineLog = `[    0.015000] PROGRESS CODE: V03020003 I0
+[    0.950000] PROGRESS CODE: V03040003 I0
+[    1.005000] PROGRESS CODE: V03051001 I0
+[    3.000000] PROGRESS CODE: V03058000 I0
+PROGRESS CODE: V03058001 I0
+`
+
+func TestMergePerformanceRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   []string
+	}{
+		{
+			name: "no offset",
+			want: []string{
+				"fpdt 1.25ms ResetEnd",
+				"edk2 15ms V03020003",
+				"fpdt 20ms PEI",
+				"fpdt 900ms DXE",
+				"edk2 950ms V03040003",
+				"fpdt 1s 5AE3F37E-4EAE-41AE-8240-35465B5E81EB",
+				"edk2 1.005s V03051001",
+				"fpdt 1.01s 5AE3F37E-4EAE-41AE-8240-35465B5E81EB",
+				"fpdt 2.5s BDS",
+				"fpdt 2.9s OsLoaderLoadImageStart",
+				"edk2 3s V03058000",
+				"edk2 - V03058001",
+				"fpdt 3.05s OsLoaderStartImageStart",
+				"fpdt 4.1s ExitBootServicesEntry",
+				"fpdt 4.12s ExitBootServicesExit",
+			},
+		},
+		{
+			// the processor reset logged 1s after the capture started
+			name:   "offset",
+			offset: time.Second,
+			want: []string{
+				"edk2 15ms V03020003",
+				"edk2 950ms V03040003",
+				"fpdt 1.00125s ResetEnd",
+				"edk2 1.005s V03051001",
+				"fpdt 1.02s PEI",
+				"fpdt 1.9s DXE",
+				"fpdt 2s 5AE3F37E-4EAE-41AE-8240-35465B5E81EB",
+				"fpdt 2.01s 5AE3F37E-4EAE-41AE-8240-35465B5E81EB",
+				"edk2 3s V03058000",
+				"edk2 - V03058001",
+				"fpdt 3.5s BDS",
+				"fpdt 3.9s OsLoaderLoadImageStart",
+				"fpdt 4.05s OsLoaderStartImageStart",
+				"fpdt 5.1s ExitBootServicesEntry",
+				"fpdt 5.12s ExitBootServicesExit",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := decodeRecords(strings.NewReader(timelineLog))
+			if err != nil {
+				t.Fatal(err)
+			}
+			perf, err := readPerformanceRecords(fpdtTables, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, r := range analysis.Merge(records, perf) {
+				// log time stamps are parsed as floating point seconds
+				stamp := "-"
+				if r.HasTimestamp {
+					stamp = r.Timestamp.Round(time.Microsecond).String()
+				}
+
+				name := r.Operation
+				if r.Decoder != "fpdt" {
+					fields := strings.Fields(r.Line)
+					name = fields[len(fields)-2]
+				}
+				got = append(got, fmt.Sprintf("%s %s %s", r.Decoder, stamp, name))
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("merged records =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestReadPerformanceRecordsNoFBPT(t *testing.T) {
+	_, err := readPerformanceRecords("../../pkg/fpdt/testdata/acpidump.txt", 0)
+	if err == nil || err.Error() != "no FBPT or S3PT in the table dump" {
+		t.Errorf("readPerformanceRecords() error = %v, want no FBPT", err)
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// represents the help of a command
+type commandHelp struct {
+	name        string
+	usage       string
+	description string
+}
+
+// Help of the commands, in the order of the help message
+var commands = []commandHelp{
+	{"decode", "[-fail-on <severity>] <boot.log | ->",
+		"Decode every status and post code line of a boot log."},
+	{"check", "[-fail-on <severity>] [-junit <out.xml>] [-tap <out.tap>] <boot.log | ->",
+		"Decode a boot log and write its phases and errors as JUnit or TAP test cases."},
+	{"timeline", "[-fpdt <dump>[,<dump>...]] [-fpdt-offset <duration>] [-boot N] <boot.log | ->",
+		"Print the SEC/PEI/DXE/BDS/OS phases of each boot, merged with the FPDT records."},
+	{"report", "[-o <report.html>] [-title <title>] [-boot N] <boot.log | ->",
+		"Write a self-contained HTML report: phase chart, exceptions and record table."},
+	{"export", "[-format chrome-trace|otlp] [-o <file> | -otlp-endpoint <URL>] [-start <time>] [-boot N] <boot.log | ->",
+		"Write the phases and codes as a Chrome trace or an OpenTelemetry trace."},
+	{"stats", "[-format table|csv|json] [-table errors|last-codes|phases] [-jobs N] [-boot N] <boot.log>...",
+		"Report the errors, last codes and phase durations of many boot logs."},
+	{"watch", "[-metrics <addr>] [-from-start] [-poll <interval>] <[name=]console.log | ->...",
+		"Decode growing console logs as they are written, serving Prometheus metrics."},
+	{"serial", "[-baud <rate>] [-o <raw.log>] [-name <console>] [-metrics <addr>] <device>",
+		"Capture a console from a serial port, saving the raw log and decoding it live."},
+	{"qemu", "[-listen] [-retry <interval>] [-o <dir>] [-name <console>] [-metrics <addr>] <unix:<path> | tcp:<host>:<port>>",
+		"Decode the debugcon or serial chardev socket of a VM, splitting it into boots."},
+	{"syslog", "[-listen <addr>] [-o <dir>] [-metrics <addr>]",
+		"Receive the consoles BMCs forward as RFC 5424 syslog messages, per host."},
+	{"fpdt", "<acpidump.txt | FPDT> [FBPT] [S3PT]",
+		"Decode the boot performance records of the ACPI FPDT and its FBPT and S3PT."},
+	{"import dp", "[-log <boot.log>] <dp.txt | ->",
+		"Join the module timing of the EDK2 dp command with the codes of a boot log."},
+	{"import ipmi", "[-width N] [-skip N] <ipmitool output | ->",
+		"Decode the POST code bytes of an IPMI raw command output."},
+	{"import postcode", "[-width N] [-skip N] <post code file | ->",
+		"Decode a BMC POST code history file, e.g. of phosphor-post-code-manager."},
+	{"import redfish", "[-boot N] [-user <name>] [-password <password>] [-insecure] <entries.json | URL>",
+		"Decode the Redfish PostCodes log entries of a saved response or a service."},
+	{"cper", "[-json] </sys/firmware/acpi/tables/data/BERT | record file>",
+		"Decode the Common Platform Error Records of the BERT or of a record file."},
+	{"extdata", "[-code <PROGRESS CODE line | ERROR line>] [-record] <data file | hex dump | ->",
+		"Decode the extended data reported with a status code, e.g. a DumpHex output."},
+	{"postcode", "-table <file> <0xNN | PROGRESS CODE line | ERROR line>",
+		"Map checkpoint bytes to status codes and back using a platform table."},
+}
+
+func lookupCommand(name string) commandHelp {
+	for _, command := range commands {
+		if command.name == name {
+			return command
+		}
+	}
+
+	panic("no help for command " + name)
+}
+
+// newFlagSet returns the flag set of a command, printing the usage line,
+// the description and the flags of the command on -h
+func newFlagSet(name string) *flag.FlagSet {
+	command := lookupCommand(name)
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintf(out, "Usage: bpd %s %s\n\n%s\n", command.name, command.usage, command.description)
+
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(out, "\nOptions:\n")
+			flags.PrintDefaults()
+		}
+	}
+
+	return flags
+}
+
+// usageError returns the error of a command run with invalid arguments
+func usageError(name string) error {
+	command := lookupCommand(name)
+	return fmt.Errorf("usage: bpd %s %s", command.name, command.usage)
+}
+
+func helpString() string {
+	var b strings.Builder
+	b.WriteString(`Usage: bpd <PROGRESS CODE line | ERROR line | POST CODE line>
+       bpd <command> [options] <arguments>
+
+Decodes a single status or post code line, every status and post code
+line of a boot log (EDK2, Intel FSP, AMD PSP/ABL, coreboot, U-Boot and
+TF-A formats), or the status code history captured by a BMC. Use "-" to
+read from stdin.
+
+Commands:
+`)
+	for _, command := range commands {
+		fmt.Fprintf(&b, "  %s %s\n        %s\n", command.name, command.usage, command.description)
+	}
+
+	b.WriteString(`
+Run "bpd <command> -h" for the options of a command. A log holding
+several boots is split into boots; -boot N selects one, counting from 1.
+
+Examples:
+  bpd "PROGRESS CODE: V03020003 I0"
+  bpd "ERROR: C40000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E"
+  bpd decode boot.log
+  bpd check -fail-on major -junit boot.xml boot.log
+  bpd timeline -fpdt acpidump.txt,fbpt.bin boot.log
+  bpd timeline -boot 3 reboot-cycle.log
+  bpd report boot.log -o report.html
+  bpd export -format otlp -otlp-endpoint http://localhost:4318 boot.log
+  bpd stats -format csv -table phases logs/*.log
+  bpd watch -metrics :9100 rack1-node1=/var/log/consoles/node1.log
+  bpd serial /dev/ttyUSB0 -baud 115200 -o node1.log
+  bpd qemu -o boots unix:/tmp/ovmf-debug.sock
+  bpd syslog -listen :5514 -o consoles -metrics :9100
+  bpd import redfish -boot 1 entries.json
+  bpd postcode -table board.map 0x4F
+  bpd extdata -code "ERROR: C40000002:V00051003 I0" data.txt
+
+Exit status:
+  0  success
+  1  usage or I/O error
+  2  invalid command line flags
+  3  a line failed to decode
+  4  a code is not in the decoding tables
+  5  a minor error was found
+  6  a major error was found
+  7  an unrecovered error was found
+  8  an uncontained error was found
+
+  Only errors at or above the -fail-on severity (default minor) set the
+  exit status; -fail-on none ignores errors.`)
+
+	return b.String()
+}
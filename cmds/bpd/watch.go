@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/metrics"
+)
+
+// tailReader reads a growing console log, as "tail -F" does: at the
+// end of the file it waits for more data, and it reopens the file when
+// it is truncated or replaced by log rotation.
+type tailReader struct {
+	path string
+	poll time.Duration
+	f    *os.File
+	read int64
+}
+
+func openTail(path string, poll time.Duration, fromStart bool) (*tailReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tailReader{path: path, poll: poll, f: f}
+	if !fromStart {
+		if t.read, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.f.Read(p)
+		t.read += int64(n)
+		if n > 0 || err != nil && err != io.EOF {
+			return n, err
+		}
+
+		time.Sleep(t.poll)
+		if err := t.reopen(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// reopen starts over when the file was truncated or replaced
+func (t *tailReader) reopen() error {
+	current, err := t.f.Stat()
+	if err != nil {
+		return err
+	}
+	latest, err := os.Stat(t.path)
+	if err != nil {
+		// rotated and not created again yet
+		return nil
+	}
+
+	switch {
+	case !os.SameFile(current, latest):
+		f, err := os.Open(t.path)
+		if err != nil {
+			return nil
+		}
+		t.f.Close()
+		t.f = f
+		t.read = 0
+	case latest.Size() < t.read:
+		if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.read = 0
+	}
+
+	return nil
+}
+
+func (t *tailReader) Close() error {
+	return t.f.Close()
+}
+
+// consolePrinter prints the records of several consoles, one line per
+// record
+type consolePrinter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (p *consolePrinter) print(name string, record decoder.Record, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return
+	}
+
+	kind := record.Kind.String()
+	if record.Kind == decoder.KindError {
+		kind = record.Severity.String()
+	}
+	fmt.Fprintf(p.out, "%s: [%s] %s: %s / %s / %s\n", name, record.Phase, kind, record.Class, record.Subclass, record.Operation)
+}
+
+// consoleDecoder decodes the lines of a console as they are read.
+// Every record is counted in the metrics, if not nil, and printed.
+type consoleDecoder struct {
+	name    string
+	scanner *decoder.Scanner
+	phase   decoder.Phase
+	metrics *metrics.Metrics
+	printer *consolePrinter
+	boots   analysis.BootDetector
+
+	// newBoot, if not nil, is called before a record starting a new
+	// boot is counted and printed
+	newBoot func()
+}
+
+func newConsoleDecoder(name string, m *metrics.Metrics, p *consolePrinter) *consoleDecoder {
+	return &consoleDecoder{
+		name:    name,
+		scanner: decoder.NewDefaultScanner(),
+		metrics: m,
+		printer: p,
+	}
+}
+
+// decodeLine decodes, counts and prints a line. It returns false if
+// the line is not decoded.
+func (c *consoleDecoder) decodeLine(line string) (decoder.Record, bool) {
+	record, ok, err := c.scanner.DecodeLine(line)
+	if !ok {
+		return record, false
+	}
+
+	if c.boots.NewBoot(record) {
+		c.phase = decoder.PhaseUnknown
+		if c.newBoot != nil {
+			c.newBoot()
+		}
+	}
+
+	// as analysis.AssignPhases, for records as they come
+	if record.Phase == decoder.PhaseUnknown {
+		record.Phase = c.phase
+	} else {
+		c.phase = record.Phase
+	}
+
+	if c.metrics != nil {
+		if err != nil {
+			c.metrics.ObserveError(c.name)
+		} else {
+			c.metrics.Observe(c.name, record, time.Now())
+		}
+	}
+	c.printer.print(c.name, record, err)
+
+	return record, err == nil
+}
+
+// decode decodes the lines of r until it ends
+func (c *consoleDecoder) decode(r io.Reader) error {
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for lines.Scan() {
+		c.decodeLine(lines.Text())
+	}
+
+	return lines.Err()
+}
+
+// serveMetrics serves the metrics on /metrics in the background
+func serveMetrics(addr string, m *metrics.Metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Fprintln(os.Stderr, "metrics:", err)
+			os.Exit(exitError)
+		}
+	}()
+}
+
+// consoleName returns the name of a console for its log file, or the
+// name given as "name=path"
+func consoleName(arg string) (string, string) {
+	if name, path, ok := strings.Cut(arg, "="); ok {
+		return name, path
+	}
+	if arg == "-" {
+		return "stdin", arg
+	}
+
+	return strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg)), arg
+}
+
+// runWatch follows growing console logs and decodes their lines as
+// they are written
+func runWatch(args []string) error {
+	flags := newFlagSet("watch")
+	addr := flags.String("metrics", "", "serve Prometheus metrics on /metrics at the address, e.g. :9100")
+	fromStart := flags.Bool("from-start", false, "decode the logs from the start rather than only new lines")
+	poll := flags.Duration("poll", 500*time.Millisecond, "interval between checks for new lines")
+	args = parseInterspersed(flags, args)
+
+	if len(args) == 0 {
+		return usageError("watch")
+	}
+
+	var m *metrics.Metrics
+	if *addr != "" {
+		m = metrics.New()
+		serveMetrics(*addr, m)
+	}
+	printer := &consolePrinter{out: os.Stdout}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(args))
+	for i, arg := range args {
+		name, path := consoleName(arg)
+
+		var input io.ReadCloser = os.Stdin
+		if path != "-" {
+			tail, err := openTail(path, *poll, *fromStart)
+			if err != nil {
+				return err
+			}
+			input = tail
+		}
+
+		wg.Add(1)
+		go func(i int, name string, input io.ReadCloser) {
+			defer wg.Done()
+			defer input.Close()
+			if err := newConsoleDecoder(name, m, printer).decode(input); err != nil {
+				errs[i] = fmt.Errorf("%s: %v", name, err)
+			}
+		}(i, name, input)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package amd
+
+import (
+	"fmt"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Below are definitions of the AMD PSP and ABL post codes
+//
+// The PSP boot loader and the AGESA Boot Loader (ABL), which trains
+// memory on the PSP, write 32-bit post codes to port 0x80. The upper
+// byte identifies the emitting firmware, the lower 16 bits hold the
+// test point:
+//
+// 0                      16           24          32
+// ┌───────────────────────┬────────────┬───────────┐
+// │                       │            │           │
+// │      TEST POINT       │  RESERVED  │  SOURCE   │
+// │                       │            │           │
+// └───────────────────────┴────────────┴───────────┘
+//
+// Some capture tools only keep the upper 16 bits of the ABL codes,
+// e.g. 0xEAxx.
+//
+
+// Post Code Masks
+const (
+	AMD_POST_CODE_SOURCE_MASK     uint32 = 0xFF000000
+	AMD_POST_CODE_TEST_POINT_MASK uint32 = 0x0000FFFF
+)
+
+// Source mappings
+var sourceDesc = map[uint8]string{
+	0xEA: "AGESA Boot Loader (ABL)",
+	0xEE: "PSP Boot Loader",
+}
+
+// Source to boot phase mappings
+var sourcePhase = map[uint8]decoder.Phase{
+	0xEA: decoder.PhasePEI,
+	0xEE: decoder.PhaseSEC,
+}
+
+// Decoder decodes the post codes emitted by the AMD PSP and ABL.
+type Decoder struct{}
+
+func init() {
+	decoder.RegisterPriority(Decoder{}, decoder.PrioritySpecific)
+}
+
+func (Decoder) Name() string {
+	return "amd"
+}
+
+func (Decoder) Match(line string) bool {
+	value, ok := decoder.ParsePostCodeLine(line)
+	if !ok {
+		return false
+	}
+
+	_, ok = postCodeSource(value)
+	return ok
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	value, ok := decoder.ParsePostCodeLine(line)
+	if !ok {
+		return decoder.Record{}, fmt.Errorf("invalid AMD post code line: %q", line)
+	}
+
+	if _, ok := postCodeSource(value); !ok {
+		return decoder.Record{}, fmt.Errorf("not an AMD post code: 0x%X", value)
+	}
+
+	return DecodePostCode(value), nil
+}
+
+func postCodeSource(value uint64) (uint8, bool) {
+	var source uint8
+	switch {
+	case value <= 0xFFFF:
+		source = uint8(value >> 8)
+	case value <= 0xFFFFFFFF:
+		source = uint8((uint32(value) & AMD_POST_CODE_SOURCE_MASK) >> 24)
+	default:
+		return 0, false
+	}
+
+	_, ok := sourceDesc[source]
+	return source, ok
+}
+
+// DecodePostCode decodes an AMD post code into a record.
+func DecodePostCode(value uint64) decoder.Record {
+	source, _ := postCodeSource(value)
+
+	subclass, ok := sourceDesc[source]
+	if !ok {
+		subclass = "Unknown"
+	}
+
+	var operation string
+	if value <= 0xFFFF {
+		operation = fmt.Sprintf("Test Point 0x%02X", value&0xFF)
+	} else {
+		operation = fmt.Sprintf("Test Point 0x%04X", uint32(value)&AMD_POST_CODE_TEST_POINT_MASK)
+	}
+
+	return decoder.Record{
+		Decoder:   "amd",
+		Kind:      decoder.KindProgress,
+		Phase:     sourcePhase[source],
+		Code:      value,
+		Class:     "AMD",
+		Subclass:  subclass,
+		Operation: operation,
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package amd
+
+import (
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		line  string
+		match bool
+	}{
+		{"POST CODE: 0xEA00E0A1", true}, // ABL
+		{"POST CODE: 0xEE000010", true}, // PSP boot loader
+		{"POST CODE: 0xEA42", true},     // upper 16 bits of an ABL code
+		{"POST CODE: 0xEE01", true},
+		{"POST CODE: 0xD800", false}, // FSP
+		{"POST CODE: 0x4F", false},
+		{"POST CODE: 0xEA", false},
+		{"POST CODE: 0x00EA0001", false},
+		{"POST CODE: 0xB00000EA", false},  // other source
+		{"POST CODE: 0x1EA00E0A1", false}, // over 32 bits
+		{"POST CODE: 0xEA00E0A100000000", false},
+		{"PROGRESS CODE: V03020003 I0", false},
+	}
+	for _, tt := range tests {
+		if got := (Decoder{}).Match(tt.line); got != tt.match {
+			t.Errorf("Match(%q) = %v, want %v", tt.line, got, tt.match)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		line      string
+		subclass  string
+		operation string
+		phase     decoder.Phase
+	}{
+		{"POST CODE: 0xEA00E0A1", "AGESA Boot Loader (ABL)", "Test Point 0xE0A1", decoder.PhasePEI},
+		{"POST CODE: 0xEE000010", "PSP Boot Loader", "Test Point 0x0010", decoder.PhaseSEC},
+		{"POST CODE: 0xEA42", "AGESA Boot Loader (ABL)", "Test Point 0x42", decoder.PhasePEI},
+		{"POST CODE: 0xEE01", "PSP Boot Loader", "Test Point 0x01", decoder.PhaseSEC},
+	}
+	for _, tt := range tests {
+		record, err := Decoder{}.Decode(tt.line)
+		if err != nil {
+			t.Errorf("Decode(%q): %v", tt.line, err)
+			continue
+		}
+		if record.Class != "AMD" || record.Subclass != tt.subclass || record.Operation != tt.operation || record.Phase != tt.phase {
+			t.Errorf("Decode(%q) = %q, %q, %q, %v, want AMD, %q, %q, %v", tt.line,
+				record.Class, record.Subclass, record.Operation, record.Phase, tt.subclass, tt.operation, tt.phase)
+		}
+	}
+
+	for _, line := range []string{"POST CODE: 0xD800", "POST CODE: 0x1EA00E0A1", "ERROR: C40000002:V010E0005 I0"} {
+		if _, err := (Decoder{}).Decode(line); err == nil {
+			t.Errorf("Decode(%q) succeeded", line)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package analysis
+
+import (
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// identifies a record marking the start of a boot
+type bootStartKey struct {
+	decoder   string
+	code      uint64
+	subclass  string
+	operation string
+}
+
+// BootDetector finds the boot boundaries in a stream of records, such
+// as a reboot cycle log or a console captured across resets. It keeps
+// the state of the current boot, so a detector must be used for a
+// single stream.
+type BootDetector struct {
+	// last phase reported by each decoder
+	phases map[string]decoder.Phase
+	seen   bool
+	reset  bool
+	starts map[bootStartKey]bool
+}
+
+// NewBoot reports whether the record starts a new boot after the
+// records seen before. A new boot starts with:
+//
+//   - a SEC or PEI record after its decoder reported a later phase,
+//     e.g. a PEI Core code after DXE. Going back to a later phase does
+//     not start a boot, as DXE drivers keep reporting codes during BDS,
+//     and neither does the handoff to the SEC or PEI phase of the next
+//     firmware stack, e.g. EDK2 starting after TF-A BL31;
+//   - a boot start record, such as a SEC or PEI Core entry code or a
+//     first stage banner, seen before in the current boot;
+//   - the first record after the reset messages.
+//
+// Records reporting earlier events of the boot, such as the U-Boot
+// bootstage report, never start a boot.
+func (d *BootDetector) NewBoot(record decoder.Record) bool {
+	if record.Boundary == decoder.BoundaryHistory {
+		return false
+	}
+
+	key := bootStartKey{record.Decoder, record.Code, record.Subclass, record.Operation}
+	newBoot := d.seen && (d.reset && record.Boundary != decoder.BoundaryReset ||
+		record.Phase != decoder.PhaseUnknown && record.Phase <= decoder.PhasePEI && record.Phase < d.phases[record.Decoder] ||
+		record.Boundary == decoder.BoundaryBootStart && d.starts[key])
+	if newBoot {
+		d.Reset()
+	}
+
+	d.seen = true
+	if record.Phase != decoder.PhaseUnknown {
+		if d.phases == nil {
+			d.phases = make(map[string]decoder.Phase)
+		}
+		d.phases[record.Decoder] = record.Phase
+	}
+	switch record.Boundary {
+	case decoder.BoundaryBootStart:
+		if d.starts == nil {
+			d.starts = make(map[bootStartKey]bool)
+		}
+		d.starts[key] = true
+	case decoder.BoundaryReset:
+		d.reset = true
+	}
+
+	return newBoot
+}
+
+// Reset forgets the records seen, e.g. when the console reconnects.
+func (d *BootDetector) Reset() {
+	clear(d.phases)
+	d.seen = false
+	d.reset = false
+	clear(d.starts)
+}
+
+// SplitBoots splits the records of a log holding several boots, such
+// as a reboot cycle log, into the records of each boot. Phases should
+// be assigned to each boot with AssignPhases after splitting, so the
+// records before the first phase of a boot do not take the last phase
+// of the boot before.
+func SplitBoots(records []decoder.Record) [][]decoder.Record {
+	var boots [][]decoder.Record
+	var detector BootDetector
+
+	start := 0
+	for i, record := range records {
+		if detector.NewBoot(record) {
+			boots = append(boots, records[start:i])
+			start = i
+		}
+	}
+	if start < len(records) {
+		boots = append(boots, records[start:])
+	}
+
+	return boots
+}
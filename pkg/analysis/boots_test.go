@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+	"github.com/nhivp/boot-progress-decoder/pkg/tfa"
+)
+
+// an EDK2 boot, from the SEC entry to the BDS
+const edk2Boot = `PROGRESS CODE: V03011000 I0
+PROGRESS CODE: V03020003 I0
+PROGRESS CODE: V03020002 I0
+PROGRESS CODE: V03040003 I0
+PROGRESS CODE: V03040002 I0
+PROGRESS CODE: V03051001 I0
+PROGRESS CODE: V03058000 I0
+`
+
+// TF-A booting EDK2 as the normal world firmware
+const tfaBoot = `NOTICE:  Booting Trusted Firmware
+NOTICE:  BL1: v2.10.0(release):v2.10.0
+NOTICE:  BL1: Built : 10:00:00, Jan  1 2024
+NOTICE:  BL1: Booting BL2
+NOTICE:  Booting Trusted Firmware
+NOTICE:  BL2: v2.10.0(release):v2.10.0
+NOTICE:  BL2: Built : 10:00:00, Jan  1 2024
+NOTICE:  BL1: Booting BL31
+NOTICE:  BL31: v2.10.0(release):v2.10.0
+NOTICE:  BL31: Built : 10:00:00, Jan  1 2024
+INFO:    BL31: Preparing for EL3 exit to normal world
+INFO:    Entry point address = 0x60000000
+PROGRESS CODE: V03020003 I0
+PROGRESS CODE: V03020002 I0
+PROGRESS CODE: V03040003 I0
+PROGRESS CODE: V03040002 I0
+PROGRESS CODE: V03051001 I0
+`
+
+func TestSplitBoots(t *testing.T) {
+	// the boot without its SEC entry, as when SEC reports no code
+	_, peiBoot, _ := strings.Cut(edk2Boot, "\n")
+
+	tests := []struct {
+		name string
+		log  string
+		// first line of each boot
+		starts []int
+	}{
+		{"EDK2", edk2Boot, []int{1}},
+		{"EDK2 reboots", edk2Boot + edk2Boot + edk2Boot, []int{1, 8, 15}},
+		{"EDK2 without SEC codes", peiBoot + peiBoot, []int{1, 7}},
+		{"TF-A and EDK2", tfaBoot, []int{1}},
+		{"TF-A and EDK2 reboots", tfaBoot + tfaBoot, []int{1, 18}},
+	}
+
+	scanner := decoder.NewScanner(edk2.Decoder{}, tfa.Decoder{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []decoder.Record
+			err := scanner.Scan(strings.NewReader(tt.log), func(record decoder.Record, err error) error {
+				if err != nil {
+					return err
+				}
+				records = append(records, record)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var starts []int
+			for _, boot := range analysis.SplitBoots(records) {
+				starts = append(starts, boot[0].LineNo)
+			}
+			if len(starts) != len(tt.starts) {
+				t.Fatalf("boots start on lines %v, want %v", starts, tt.starts)
+			}
+			for i := range starts {
+				if starts[i] != tt.starts[i] {
+					t.Fatalf("boots start on lines %v, want %v", starts, tt.starts)
+				}
+			}
+		})
+	}
+}
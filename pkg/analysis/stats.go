@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// identifies an error code reported by a module
+type ErrorKey struct {
+	Code     uint64
+	Severity decoder.Severity
+	Module   string
+}
+
+// represents the outcome of a single boot
+type BootSummary struct {
+	Name      string
+	Records   int
+	Failed    bool
+	Last      decoder.Record
+	HasLast   bool
+	Errors    []decoder.Record
+	Durations map[decoder.Phase]time.Duration
+}
+
+// Summarize reduces the records of a boot to what the statistics need.
+// A boot fails if it never reaches the OS phase or reports an
+// unrecovered or uncontained error. Phases must have been assigned
+// with AssignPhases.
+func Summarize(name string, records []decoder.Record) BootSummary {
+	summary := BootSummary{
+		Name:      name,
+		Records:   len(records),
+		Failed:    true,
+		Durations: make(map[decoder.Phase]time.Duration),
+	}
+
+	fatal := false
+	for _, record := range records {
+		if record.Phase == decoder.PhaseOS {
+			summary.Failed = false
+		}
+		if record.Kind == decoder.KindError {
+			summary.Errors = append(summary.Errors, record)
+			if record.Severity >= decoder.SeverityUnrecovered {
+				fatal = true
+			}
+		}
+		if record.Kind == decoder.KindProgress || record.Kind == decoder.KindError {
+			summary.Last, summary.HasLast = record, true
+		}
+	}
+	summary.Failed = summary.Failed || fatal
+
+	for _, span := range Timeline(records) {
+		if span.HasTimestamp && span.Phase != decoder.PhaseUnknown {
+			summary.Durations[span.Phase] += span.Duration()
+		}
+	}
+
+	return summary
+}
+
+// represents how often an error code was reported across boots
+type ErrorStat struct {
+	Code      uint64   `json:"code"`
+	Severity  string   `json:"severity"`
+	Module    string   `json:"module,omitempty"`
+	Class     string   `json:"class"`
+	Subclass  string   `json:"subclass"`
+	Operation string   `json:"operation"`
+	Count     int      `json:"count"`
+	Boots     []string `json:"boots"`
+}
+
+// represents how many failing boots stopped at a status code
+type LastCodeStat struct {
+	Code      uint64 `json:"code"`
+	Class     string `json:"class"`
+	Subclass  string `json:"subclass"`
+	Operation string `json:"operation"`
+	Count     int    `json:"count"`
+}
+
+// represents the distribution of a phase duration across boots
+type PhaseStat struct {
+	Phase string        `json:"phase"`
+	Boots int           `json:"boots"`
+	Min   time.Duration `json:"min_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P90   time.Duration `json:"p90_ns"`
+	P99   time.Duration `json:"p99_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// represents the statistics of many boots
+type Stats struct {
+	Boots     int            `json:"boots"`
+	Failed    int            `json:"failed"`
+	Errors    []ErrorStat    `json:"errors"`
+	LastCodes []LastCodeStat `json:"last_codes"`
+	Phases    []PhaseStat    `json:"phases"`
+}
+
+// Aggregate computes the statistics of the boots. Errors and last codes
+// are sorted by decreasing count.
+func Aggregate(boots []BootSummary) Stats {
+	stats := Stats{Boots: len(boots)}
+
+	errorIndex := make(map[ErrorKey]int)
+	lastIndex := make(map[uint64]int)
+	durations := make(map[decoder.Phase][]time.Duration)
+
+	for _, boot := range boots {
+		seen := make(map[ErrorKey]bool)
+		for _, record := range boot.Errors {
+			key := ErrorKey{Code: record.Code, Severity: record.Severity, Module: record.Module}
+			index, ok := errorIndex[key]
+			if !ok {
+				stats.Errors = append(stats.Errors, ErrorStat{
+					Code:      record.Code,
+					Severity:  record.Severity.String(),
+					Module:    record.Module,
+					Class:     record.Class,
+					Subclass:  record.Subclass,
+					Operation: record.Operation,
+				})
+				index = len(stats.Errors) - 1
+				errorIndex[key] = index
+			}
+
+			stats.Errors[index].Count++
+			if !seen[key] {
+				stats.Errors[index].Boots = append(stats.Errors[index].Boots, boot.Name)
+				seen[key] = true
+			}
+		}
+
+		if boot.Failed {
+			stats.Failed++
+			if boot.HasLast {
+				index, ok := lastIndex[boot.Last.Code]
+				if !ok {
+					stats.LastCodes = append(stats.LastCodes, LastCodeStat{
+						Code:      boot.Last.Code,
+						Class:     boot.Last.Class,
+						Subclass:  boot.Last.Subclass,
+						Operation: boot.Last.Operation,
+					})
+					index = len(stats.LastCodes) - 1
+					lastIndex[boot.Last.Code] = index
+				}
+				stats.LastCodes[index].Count++
+			}
+		}
+
+		for phase, duration := range boot.Durations {
+			durations[phase] = append(durations[phase], duration)
+		}
+	}
+
+	sort.SliceStable(stats.Errors, func(i, j int) bool {
+		return stats.Errors[i].Count > stats.Errors[j].Count
+	})
+	sort.SliceStable(stats.LastCodes, func(i, j int) bool {
+		return stats.LastCodes[i].Count > stats.LastCodes[j].Count
+	})
+
+	for _, phase := range []decoder.Phase{decoder.PhaseSEC, decoder.PhasePEI, decoder.PhaseDXE, decoder.PhaseBDS, decoder.PhaseOS} {
+		values := durations[phase]
+		if len(values) == 0 {
+			continue
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		stats.Phases = append(stats.Phases, PhaseStat{
+			Phase: phase.String(),
+			Boots: len(values),
+			Min:   values[0],
+			P50:   Percentile(values, 50),
+			P90:   Percentile(values, 90),
+			P99:   Percentile(values, 99),
+			Max:   values[len(values)-1],
+		})
+	}
+
+	return stats
+}
+
+// Percentile returns the nearest-rank percentile p of the sorted
+// durations.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
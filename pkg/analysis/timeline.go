@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// represents a run of consecutive records in the same boot phase
+type PhaseSpan struct {
+	Phase        decoder.Phase
+	Start        time.Duration
+	End          time.Duration
+	HasTimestamp bool
+	FirstLine    int
+	LastLine     int
+	Records      int
+	Errors       int
+}
+
+func (s PhaseSpan) Duration() time.Duration {
+	return s.End - s.Start
+}
+
+// AssignPhases sets the phase of records that may be reported from any
+// phase (e.g. PCI or memory codes) to the phase of the record before
+// them. Records before the first known phase keep PhaseUnknown.
+func AssignPhases(records []decoder.Record) {
+	current := decoder.PhaseUnknown
+	for i := range records {
+		if records[i].Phase == decoder.PhaseUnknown {
+			records[i].Phase = current
+		} else {
+			current = records[i].Phase
+		}
+	}
+}
+
+// Timeline splits the records into phase spans. Phases must have been
+// assigned with AssignPhases. A span ends where the next one starts,
+// so its duration includes the time until the first record of the
+// next phase.
+func Timeline(records []decoder.Record) []PhaseSpan {
+	var spans []PhaseSpan
+
+	for _, record := range records {
+		if len(spans) == 0 || spans[len(spans)-1].Phase != record.Phase {
+			if len(spans) > 0 && record.HasTimestamp && spans[len(spans)-1].HasTimestamp {
+				spans[len(spans)-1].End = record.Timestamp
+			}
+
+			spans = append(spans, PhaseSpan{
+				Phase:        record.Phase,
+				Start:        record.Timestamp,
+				End:          record.Timestamp,
+				HasTimestamp: record.HasTimestamp,
+			})
+		}
+
+		// records read from tables rather than a log have no line
+		span := &spans[len(spans)-1]
+		if record.LineNo != 0 {
+			if span.FirstLine == 0 {
+				span.FirstLine = record.LineNo
+			}
+			span.LastLine = record.LineNo
+		}
+		span.Records++
+		if record.Kind == decoder.KindError {
+			span.Errors++
+		}
+		if record.HasTimestamp {
+			if !span.HasTimestamp {
+				span.Start = record.Timestamp
+				span.HasTimestamp = true
+			}
+			if record.Timestamp > span.End {
+				span.End = record.Timestamp
+			}
+		}
+	}
+
+	return spans
+}
+
+// Merge interleaves two record streams, each in time order, by time
+// stamp. Records without a time stamp stay after the record before
+// them in their own stream.
+func Merge(a, b []decoder.Record) []decoder.Record {
+	merged := make([]decoder.Record, 0, len(a)+len(b))
+
+	var lastA, lastB time.Duration
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].HasTimestamp {
+			lastA = a[i].Timestamp
+		}
+		if b[j].HasTimestamp {
+			lastB = b[j].Timestamp
+		}
+
+		if lastA <= lastB {
+			merged = append(merged, a[i])
+			i++
+		} else {
+			merged = append(merged, b[j])
+			j++
+		}
+	}
+	merged = append(merged, a[i:]...)
+	merged = append(merged, b[j:]...)
+
+	return merged
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+// Package bmc decodes the status codes captured by a BMC, as POST code
+// bytes or as Redfish log entries.
+//
+// The host writes an EFI_STATUS_CODE_VALUE to the POST code ports
+// (0x80-0x83) byte by byte, lowest port first. The BMC snoops each
+// write separately, so a 32-bit code shows up as four bytes in
+// little-endian order:
+//
+//	V03020003  ──►  03 00 02 03
+//
+// Some platforms send the full status code instead, with the
+// EFI_STATUS_CODE_TYPE in the upper 32 bits of a 64-bit value.
+package bmc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Default number of bytes per status code value
+const DefaultWidth = 4
+
+// ParseHexBytes parses a stream of hex bytes, such as the output of
+// `ipmitool raw` or a BMC postcode file. Bytes may be separated by
+// whitespace or commas and may carry a 0x prefix. Lines starting with
+// '#' are ignored.
+func ParseHexBytes(r io.Reader) ([]byte, error) {
+	var data []byte
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ' ' || c == '\t' || c == ','
+		})
+
+		for _, field := range fields {
+			field = strings.TrimPrefix(strings.ToLower(field), "0x")
+			value, err := strconv.ParseUint(field, 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid hex byte %q", lineNo, field)
+			}
+			data = append(data, byte(value))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reassemble joins the bytes of multi-byte POST code writes into
+// status code values. width is the number of bytes per value.
+func Reassemble(data []byte, width int) ([]uint64, error) {
+	if width < 1 || width > 8 {
+		return nil, fmt.Errorf("invalid width %d: must be between 1 and 8", width)
+	}
+	if len(data)%width != 0 {
+		return nil, fmt.Errorf("%d trailing bytes do not form a %d-byte value", len(data)%width, width)
+	}
+
+	values := make([]uint64, 0, len(data)/width)
+	for i := 0; i < len(data); i += width {
+		var buf [8]byte
+		copy(buf[:], data[i:i+width])
+		values = append(values, binary.LittleEndian.Uint64(buf[:]))
+	}
+
+	return values, nil
+}
+
+// ParseHexValues parses a postcode file holding one hex value per
+// line. Blank lines and lines starting with '#' are ignored.
+func ParseHexValues(r io.Reader) ([]uint64, error) {
+	var values []uint64
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		value, err := parseHexValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		values = append(values, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func parseHexValue(s string) (uint64, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+
+	value, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex value %q", s)
+	}
+
+	return value, nil
+}
+
+// StatusCode converts a raw value captured by the BMC into a status
+// code. Values without a type in the upper 32 bits are progress codes.
+func StatusCode(value uint64) edk2.StatusCode {
+	codeType := edk2.NewStatusCodeType(uint32(value >> 32))
+	if codeType.Type == 0 {
+		codeType.Type = edk2.EFI_PROGRESS_CODE
+	}
+
+	return edk2.StatusCode{
+		Type:  codeType,
+		Value: edk2.NewStatusCodeValue(uint32(value)),
+	}
+}
+
+func StatusCodes(values []uint64) []edk2.StatusCode {
+	codes := make([]edk2.StatusCode, 0, len(values))
+	for _, value := range values {
+		codes = append(codes, StatusCode(value))
+	}
+
+	return codes
+}
+
+// ReadByteStream reads a stream of hex bytes, drops the first skip
+// bytes, e.g. the completion code of an IPMI response, and reassembles
+// the others into values of width bytes.
+func ReadByteStream(r io.Reader, width, skip int) ([]uint64, error) {
+	data, err := ParseHexBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	if skip < 0 || skip > len(data) {
+		return nil, fmt.Errorf("cannot skip %d bytes of %d", skip, len(data))
+	}
+
+	return Reassemble(data[skip:], width)
+}
+
+// ReadPostCodes reads a BMC postcode file holding either a stream of
+// hex bytes or one hex value per line. Byte streams are read as
+// ReadByteStream does; skip must be 0 for files of values.
+func ReadPostCodes(r io.Reader, width, skip int) ([]uint64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isByteStream(string(data)) {
+		if skip != 0 {
+			return nil, fmt.Errorf("cannot skip bytes of a file holding one value per line")
+		}
+		return ParseHexValues(strings.NewReader(string(data)))
+	}
+
+	return ReadByteStream(strings.NewReader(string(data)), width, skip)
+}
+
+// isByteStream tells the two postcode file layouts apart: a byte stream
+// holds several bytes on a line, while a file of values holds one value
+// per line, however many bytes it takes. The value width does not tell
+// them apart, as the codes of 8-bit POST code ports fit in a byte.
+func isByteStream(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if len(strings.Fields(strings.ReplaceAll(line, ",", " "))) > 1 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package bmc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadByteStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		width  int
+		skip   int
+		values []uint64
+		err    string
+	}{
+		{
+			// ipmitool raw output, the completion code first
+			name:   "ipmitool raw",
+			input:  " 00 03 00 02 03 02 00 04 03\n",
+			width:  4,
+			skip:   1,
+			values: []uint64{0x03020003, 0x03040002},
+		},
+		{
+			name:   "ipmitool raw wrapped",
+			input:  " 00 03 00 02 03 02 00 04 03 01 10 05 03 03 10 05\n 03\n",
+			width:  4,
+			skip:   1,
+			values: []uint64{0x03020003, 0x03040002, 0x03051001, 0x03051003},
+		},
+		{
+			name:   "commas and prefixes",
+			input:  "# host0\n0x03,0x00,0x02,0x03\n",
+			width:  4,
+			values: []uint64{0x03020003},
+		},
+		{
+			name:   "2-byte values",
+			input:  "00 d8 01 d8",
+			width:  2,
+			values: []uint64{0xD800, 0xD801},
+		},
+		{
+			name:   "64-bit values",
+			input:  "05 00 01 01 02 00 00 80",
+			width:  8,
+			values: []uint64{0x8000000201010005},
+		},
+		{
+			name:  "trailing bytes",
+			input: "03 00 02 03 02 00",
+			width: 4,
+			err:   "2 trailing bytes do not form a 4-byte value",
+		},
+		{
+			name:  "skip past the end",
+			input: "00 01",
+			width: 1,
+			skip:  3,
+			err:   "cannot skip 3 bytes of 2",
+		},
+		{
+			name:  "invalid width",
+			input: "00 01",
+			width: 9,
+			err:   "invalid width 9",
+		},
+		{
+			name:  "invalid byte",
+			input: "03 00\n02 103",
+			width: 4,
+			err:   `line 2: invalid hex byte "103"`,
+		},
+	}
+	for _, tt := range tests {
+		values, err := ReadByteStream(strings.NewReader(tt.input), tt.width, tt.skip)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: ReadByteStream() error = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(values, tt.values) {
+			t.Errorf("%s: ReadByteStream() = %#x, %v, want %#x", tt.name, values, err, tt.values)
+		}
+	}
+}
+
+func TestReadPostCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		skip   int
+		values []uint64
+		err    string
+	}{
+		{
+			name:   "byte stream",
+			input:  "03 00 02 03\n02 00 04 03\n",
+			values: []uint64{0x03020003, 0x03040002},
+		},
+		{
+			name:   "byte stream with a header",
+			input:  "00 03 00 02 03",
+			skip:   1,
+			values: []uint64{0x03020003},
+		},
+		{
+			name:   "values",
+			input:  "0x03020003\n\n# reset\n0x8000000203040002\n",
+			values: []uint64{0x03020003, 0x8000000203040002},
+		},
+		{
+			// the codes of an 8-bit port 0x80 fit in a byte
+			name:   "single-byte values",
+			input:  "0x10\n0x4F\n0x03\n",
+			values: []uint64{0x10, 0x4F, 0x03},
+		},
+		{
+			name:   "single value",
+			input:  "4f",
+			values: []uint64{0x4F},
+		},
+		{
+			name:  "skip in values",
+			input: "0x10\n0x4F\n",
+			skip:  1,
+			err:   "cannot skip bytes of a file holding one value per line",
+		},
+		{
+			name:  "invalid value",
+			input: "0x10\nzz\n",
+			err:   `line 2: invalid hex value "zz"`,
+		},
+	}
+	for _, tt := range tests {
+		values, err := ReadPostCodes(strings.NewReader(tt.input), DefaultWidth, tt.skip)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: ReadPostCodes() error = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(values, tt.values) {
+			t.Errorf("%s: ReadPostCodes() = %#x, %v, want %#x", tt.name, values, err, tt.values)
+		}
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		value uint64
+		code  string
+	}{
+		{0x03020003, "PROGRESS CODE: V03020003 I0"},
+		{0x0000000103020003, "PROGRESS CODE: V03020003 I0"},
+		{0x8000000201010005, "ERROR: C80000002:V01010005 I0"},
+		{0x4000000203058002, "ERROR: C40000002:V03058002 I0"},
+	}
+	for _, tt := range tests {
+		if code := StatusCode(tt.value).String(); code != tt.code {
+			t.Errorf("StatusCode(%#x) = %s, want %s", tt.value, code, tt.code)
+		}
+	}
+
+	codes := StatusCodes([]uint64{0x10, 0x4F})
+	if len(codes) != 2 || codes[1].Value.Uint32() != 0x4F {
+		t.Errorf("StatusCodes() = %v", codes)
+	}
+}
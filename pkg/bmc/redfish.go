@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package bmc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// OpenBMC exposes the POST codes of the last boots as a Redfish LogService:
+//
+//	/redfish/v1/Systems/system/LogServices/PostCodes/Entries
+//
+// Each LogEntry carries MessageId "OpenBMC.0.x.BIOSPOSTCode" and the
+// MessageArgs [boot index, time stamp offset in seconds, hex code], e.g.
+//
+//	"MessageArgs": ["1", "0.0000", "0x03020003"]
+//
+// Boot index 1 is the most recent boot. RedfishPostCodesPath is the
+// default path of the POST code log entries.
+const RedfishPostCodesPath = "/redfish/v1/Systems/system/LogServices/PostCodes/Entries"
+
+// represents a single POST code captured by the BMC
+type PostCodeEntry struct {
+	BootIndex  int
+	TimeOffset time.Duration
+	Value      uint64
+}
+
+// represents the POST codes of a single boot
+type Boot struct {
+	Index   int
+	Entries []PostCodeEntry
+}
+
+type redfishLogEntry struct {
+	ID          string   `json:"Id"`
+	MessageID   string   `json:"MessageId"`
+	MessageArgs []string `json:"MessageArgs"`
+}
+
+type redfishCollection struct {
+	Members  []redfishLogEntry `json:"Members"`
+	NextLink string            `json:"Members@odata.nextLink"`
+}
+
+// ParseRedfishEntries parses a Redfish LogEntryCollection of POST code
+// entries. It returns the entries and the link to the next page of the
+// collection, if any.
+func ParseRedfishEntries(r io.Reader) ([]PostCodeEntry, string, error) {
+	var collection redfishCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, "", fmt.Errorf("invalid Redfish log entry collection: %v", err)
+	}
+
+	entries := make([]PostCodeEntry, 0, len(collection.Members))
+	for _, member := range collection.Members {
+		if !strings.Contains(member.MessageID, "BIOSPOSTCode") {
+			continue
+		}
+
+		entry, err := parseRedfishEntry(member)
+		if err != nil {
+			return nil, "", err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, collection.NextLink, nil
+}
+
+func parseRedfishEntry(member redfishLogEntry) (PostCodeEntry, error) {
+	if len(member.MessageArgs) < 3 {
+		return PostCodeEntry{}, fmt.Errorf("entry %s: expected 3 message args, got %d", member.ID, len(member.MessageArgs))
+	}
+
+	bootIndex, err := strconv.Atoi(strings.TrimSpace(member.MessageArgs[0]))
+	if err != nil {
+		return PostCodeEntry{}, fmt.Errorf("entry %s: invalid boot index: %v", member.ID, err)
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(member.MessageArgs[1]), 64)
+	if err != nil {
+		return PostCodeEntry{}, fmt.Errorf("entry %s: invalid time stamp offset: %v", member.ID, err)
+	}
+
+	value, err := parseHexValue(member.MessageArgs[2])
+	if err != nil {
+		return PostCodeEntry{}, fmt.Errorf("entry %s: %v", member.ID, err)
+	}
+
+	return PostCodeEntry{
+		BootIndex:  bootIndex,
+		TimeOffset: time.Duration(seconds * float64(time.Second)),
+		Value:      value,
+	}, nil
+}
+
+// represents the connection parameters of a Redfish service
+type RedfishClient struct {
+	Client   *http.Client
+	User     string
+	Password string
+}
+
+// FetchRedfishEntries reads all POST code entries from a Redfish
+// service, following the collection's next links. Paging stops at a
+// link already followed, as a page linking back would loop forever.
+func (c *RedfishClient) FetchRedfishEntries(entriesURL string) ([]PostCodeEntry, error) {
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	base, err := url.Parse(entriesURL)
+	if err != nil {
+		return nil, err
+	}
+	if base.Path == "" || base.Path == "/" {
+		base.Path = RedfishPostCodesPath
+	}
+
+	var entries []PostCodeEntry
+	seen := make(map[string]bool)
+	for next := base; next != nil && !seen[next.String()]; {
+		seen[next.String()] = true
+		page, nextLink, err := c.fetchPage(client, next.String())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+
+		next = nil
+		if nextLink != "" {
+			if next, err = base.Parse(nextLink); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (c *RedfishClient) fetchPage(client *http.Client, pageURL string) ([]PostCodeEntry, string, error) {
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.User != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GET %s: %s", pageURL, resp.Status)
+	}
+
+	return ParseRedfishEntries(resp.Body)
+}
+
+// GroupByBoot groups entries by their boot index. Boots are sorted by
+// index, entries of a boot by their time stamp offset.
+func GroupByBoot(entries []PostCodeEntry) []Boot {
+	byIndex := make(map[int][]PostCodeEntry)
+	for _, entry := range entries {
+		byIndex[entry.BootIndex] = append(byIndex[entry.BootIndex], entry)
+	}
+
+	boots := make([]Boot, 0, len(byIndex))
+	for index, bootEntries := range byIndex {
+		sort.SliceStable(bootEntries, func(i, j int) bool {
+			return bootEntries[i].TimeOffset < bootEntries[j].TimeOffset
+		})
+		boots = append(boots, Boot{Index: index, Entries: bootEntries})
+	}
+	sort.Slice(boots, func(i, j int) bool {
+		return boots[i].Index < boots[j].Index
+	})
+
+	return boots
+}
+
+func (b Boot) StatusCodes() []edk2.StatusCode {
+	codes := make([]edk2.StatusCode, 0, len(b.Entries))
+	for _, entry := range b.Entries {
+		codes = append(codes, StatusCode(entry.Value))
+	}
+
+	return codes
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package bmc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// redfishPage returns a page of a POST code LogEntryCollection
+func redfishPage(nextLink string, args ...[3]string) string {
+	var members []string
+	for i, arg := range args {
+		members = append(members, fmt.Sprintf(`{"Id": "B%s-%d", "MessageId": "OpenBMC.0.2.BIOSPOSTCode", "MessageArgs": ["%s", "%s", "%s"]}`,
+			arg[0], i+1, arg[0], arg[1], arg[2]))
+	}
+	page := `{"@odata.type": "#LogEntryCollection.LogEntryCollection", "Members": [` + strings.Join(members, ", ") + `]`
+	if nextLink != "" {
+		page += `, "Members@odata.nextLink": "` + nextLink + `"`
+	}
+
+	return page + "}"
+}
+
+func TestParseRedfishEntries(t *testing.T) {
+	page := `{"Members": [
+		{"Id": "B1-1", "MessageId": "OpenBMC.0.2.BIOSPOSTCode", "MessageArgs": ["1", "0.0000", "0x03020003"]},
+		{"Id": "E1", "MessageId": "OpenBMC.0.1.DCPowerOn", "MessageArgs": ["host0"]},
+		{"Id": "B1-2", "MessageId": "OpenBMC.0.2.BIOSPOSTCode", "MessageArgs": ["1", "1.2500", "0x8000000201010005"]}
+	], "Members@odata.nextLink": "/redfish/v1/Systems/system/LogServices/PostCodes/Entries?$skip=2"}`
+
+	entries, next, err := ParseRedfishEntries(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PostCodeEntry{
+		{BootIndex: 1, Value: 0x03020003},
+		{BootIndex: 1, TimeOffset: 1250 * time.Millisecond, Value: 0x8000000201010005},
+	}
+	if len(entries) != len(want) || entries[0] != want[0] || entries[1] != want[1] {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+	if next != RedfishPostCodesPath+"?$skip=2" {
+		t.Errorf("next link = %q", next)
+	}
+
+	for _, tt := range []struct{ page, err string }{
+		{`{"Members": [`, "invalid Redfish log entry collection"},
+		{redfishPage("", [3]string{"1", "0.0"}), "expected 3 message args"},
+		{redfishPage("", [3]string{"x", "0.0", "0x1"}), "invalid boot index"},
+		{redfishPage("", [3]string{"1", "now", "0x1"}), "invalid time stamp offset"},
+		{redfishPage("", [3]string{"1", "0.0", "code"}), "invalid hex value"},
+	} {
+		// the missing third argument is an empty string, drop it
+		page := strings.Replace(tt.page, `, ""]`, `]`, 1)
+		if _, _, err := ParseRedfishEntries(strings.NewReader(page)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseRedfishEntries(%s) error = %v, want %q", page, err, tt.err)
+		}
+	}
+}
+
+func TestFetchRedfishEntries(t *testing.T) {
+	pages := map[string]string{
+		RedfishPostCodesPath: redfishPage(RedfishPostCodesPath+"?$skip=3",
+			[3]string{"2", "0.0000", "0x03020003"},
+			[3]string{"1", "0.5000", "0x03040002"},
+			[3]string{"1", "0.0000", "0x03020003"}),
+		RedfishPostCodesPath + "?$skip=3": redfishPage("",
+			[3]string{"2", "0.2000", "0x8000000201010005"},
+			[3]string{"1", "0.9000", "0x03051001"}),
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if user, password, ok := r.BasicAuth(); !ok || user != "root" || password != "0penBmc" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		page, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	client := &RedfishClient{User: "root", Password: "0penBmc"}
+	entries, err := client.FetchRedfishEntries(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(entries) != 5 {
+		t.Fatalf("got %d entries in %d requests, want 5 in 2", len(entries), requests)
+	}
+
+	// boots by index, entries by time stamp offset
+	boots := GroupByBoot(entries)
+	var got []string
+	for _, boot := range boots {
+		for _, entry := range boot.Entries {
+			got = append(got, fmt.Sprintf("%d:%s:%X", boot.Index, entry.TimeOffset, entry.Value))
+		}
+	}
+	want := "1:0s:3020003 1:500ms:3040002 1:900ms:3051001 2:0s:3020003 2:200ms:8000000201010005"
+	if strings.Join(got, " ") != want {
+		t.Errorf("GroupByBoot() = %s, want %s", strings.Join(got, " "), want)
+	}
+	if codes := boots[1].StatusCodes(); codes[1].String() != "ERROR: C80000002:V01010005 I0" {
+		t.Errorf("boot 2 code 2 = %s", codes[1])
+	}
+
+	client.Password = "wrong"
+	if _, err := client.FetchRedfishEntries(server.URL); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("FetchRedfishEntries() with a wrong password error = %v, want 401", err)
+	}
+}
+
+func TestFetchRedfishEntriesLoop(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 10 {
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		// the second page links back to itself
+		next := RedfishPostCodesPath + "?$skip=1"
+		fmt.Fprint(w, redfishPage(next, [3]string{"1", "0.0000", "0x03020003"}))
+	}))
+	defer server.Close()
+
+	entries, err := (&RedfishClient{}).FetchRedfishEntries(server.URL + RedfishPostCodesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(entries) != 2 {
+		t.Errorf("got %d entries in %d requests, want 2 in 2", len(entries), requests)
+	}
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package coreboot
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// The coreboot console (cbmem -c or serial) reports the boot progress
+// with the following lines, optionally prefixed by the log level:
+//
+//	coreboot-4.19 Mon Jan 1 00:00:00 UTC 2024 romstage starting (log level: 7)...
+//	POST: 0x39
+//	BS: BS_DEV_INIT times (ms): entry 0 run 12 exit 0
+//	[INFO ]  BS: BS_DEV_INIT run times (exec / console): 12 / 3 ms
+//	[ERROR]  PCI: 00:1c.0: Link training failed
+//	Jumping to boot code at 0x00800000(0x99b55000)
+//
+// The lines holding only an error log level are decoded from a stage
+// banner to the jump to the payload, as other consoles print "[ERROR]"
+// lines too.
+//
+// Reference: https://github.com/coreboot/coreboot/blob/main/src/include/console/post_codes.h
+//            https://github.com/coreboot/coreboot/blob/main/src/include/bootstate.h
+//
+
+var (
+	logLevelRegex  = regexp.MustCompile(`^\[(EMERG|ALERT|CRIT|ERROR|WARN|NOTE|INFO|DEBUG|SPEW)\s*\]\s*`)
+	stageRegex     = regexp.MustCompile(`^coreboot-\S+.*\s(bootblock|verstage|romstage|postcar|ramstage)\s+starting`)
+	postCodeRegex  = regexp.MustCompile(`^POST:\s*0x([0-9a-fA-F]{1,4})\s*$`)
+	bootStateRegex = regexp.MustCompile(`^BS:\s*(BS_\w+)\s+(.*)$`)
+	timesRegex     = regexp.MustCompile(`times \(ms\):\s*entry\s+(\d+)\s+run\s+(\d+)\s+exit\s+(\d+)`)
+	execTimesRegex = regexp.MustCompile(`^(entry|run|exit) times \(exec / console\):\s*(\d+)\s*/\s*(\d+)\s*ms`)
+	jumpRegex      = regexp.MustCompile(`^Jumping to boot code at`)
+)
+
+// Log level to severity mappings
+var logLevelSeverity = map[string]decoder.Severity{
+	"EMERG": decoder.SeverityUnrecovered,
+	"ALERT": decoder.SeverityUnrecovered,
+	"CRIT":  decoder.SeverityUnrecovered,
+	"ERROR": decoder.SeverityMajor,
+}
+
+// Stage to boot phase mappings
+var stagePhase = map[string]decoder.Phase{
+	"bootblock": decoder.PhaseSEC,
+	"verstage":  decoder.PhaseSEC,
+	"romstage":  decoder.PhasePEI,
+	"postcar":   decoder.PhasePEI,
+	"ramstage":  decoder.PhaseDXE,
+}
+
+// Boot state to boot phase mappings
+var bootStatePhase = map[string]decoder.Phase{
+	"BS_PRE_DEVICE":      decoder.PhaseDXE,
+	"BS_DEV_INIT_CHIPS":  decoder.PhaseDXE,
+	"BS_DEV_ENUMERATE":   decoder.PhaseDXE,
+	"BS_DEV_RESOURCES":   decoder.PhaseDXE,
+	"BS_DEV_ENABLE":      decoder.PhaseDXE,
+	"BS_DEV_INIT":        decoder.PhaseDXE,
+	"BS_POST_DEVICE":     decoder.PhaseDXE,
+	"BS_OS_RESUME_CHECK": decoder.PhaseDXE,
+	"BS_OS_RESUME":       decoder.PhaseDXE,
+	"BS_WRITE_TABLES":    decoder.PhaseDXE,
+	"BS_PAYLOAD_LOAD":    decoder.PhaseBDS,
+	"BS_PAYLOAD_BOOT":    decoder.PhaseOS,
+}
+
+// Post code mappings
+var postCodeDesc = map[uint8]string{
+	0x01: "Reset Vector Correct",
+	0x10: "Enter Protected Mode",
+	0x11: "Prepare RAM Stage",
+	0x13: "Entry C Start",
+	0x39: "Console Ready",
+	0x40: "Console Boot Message",
+	0x70: "Boot State Pre Device",
+	0x71: "Boot State Device Init Chips",
+	0x72: "Boot State Device Enumerate",
+	0x73: "Boot State Device Resources",
+	0x74: "Boot State Device Enable",
+	0x75: "Boot State Device Init",
+	0x76: "Boot State Post Device",
+	0x77: "Boot State OS Resume Check",
+	0x78: "Boot State OS Resume",
+	0x79: "Boot State Write Tables",
+	0x7A: "Boot State Payload Load",
+	0x7B: "Boot State Payload Boot",
+	0xEE: "RAM Failure",
+	0xEF: "Resume Failure",
+	0xFF: "Die",
+}
+
+// Post code to boot phase mappings
+var postCodePhase = map[uint8]decoder.Phase{
+	0x70: decoder.PhaseDXE,
+	0x7A: decoder.PhaseBDS,
+	0x7B: decoder.PhaseOS,
+}
+
+// Post codes reporting a fatal error
+var postCodeSeverity = map[uint8]decoder.Severity{
+	0xEE: decoder.SeverityUnrecovered,
+	0xEF: decoder.SeverityUnrecovered,
+	0xFF: decoder.SeverityUnrecovered,
+}
+
+// Decoder decodes the coreboot console.
+type Decoder struct{}
+
+func init() {
+	decoder.Register(Decoder{})
+}
+
+func (Decoder) Name() string {
+	return "coreboot"
+}
+
+func splitLogLevel(line string) (string, string) {
+	if !strings.HasPrefix(line, "[") {
+		return "", line
+	}
+
+	if m := logLevelRegex.FindStringSubmatch(line); m != nil {
+		return m[1], line[len(m[0]):]
+	}
+
+	return "", line
+}
+
+func (Decoder) Match(line string) bool {
+	level, line := splitLogLevel(line)
+
+	// the prefix checks skip the regular expressions for most lines
+	return logLevelSeverity[level] != decoder.SeverityNone ||
+		strings.HasPrefix(line, "coreboot-") && stageRegex.MatchString(line) ||
+		strings.HasPrefix(line, "POST:") && postCodeRegex.MatchString(line) ||
+		strings.HasPrefix(line, "BS:") && bootStateRegex.MatchString(line) ||
+		jumpRegex.MatchString(line)
+}
+
+// InBlock follows the coreboot console, from a stage banner to the
+// jump to the payload
+func (Decoder) InBlock(line string, inBlock bool) bool {
+	_, line = splitLogLevel(line)
+	if strings.HasPrefix(line, "coreboot-") && stageRegex.MatchString(line) {
+		return true
+	}
+
+	return inBlock && !jumpRegex.MatchString(line)
+}
+
+// NeedsBlock reports whether the record is a console line matched by
+// its log level only
+func (Decoder) NeedsBlock(record decoder.Record) bool {
+	return record.Subclass == "Console"
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	level, line := splitLogLevel(line)
+
+	record := decoder.Record{
+		Decoder: "coreboot",
+		Kind:    decoder.KindInfo,
+		Class:   "coreboot",
+	}
+
+	if m := stageRegex.FindStringSubmatch(line); m != nil {
+		record.Subclass = "Stage"
+		record.Operation = m[1] + " starting"
+		record.Phase = stagePhase[m[1]]
+		if m[1] == "bootblock" {
+			record.Boundary = decoder.BoundaryBootStart
+		}
+	} else if m := postCodeRegex.FindStringSubmatch(line); m != nil {
+		value, err := strconv.ParseUint(m[1], 16, 8)
+		if err != nil {
+			return decoder.Record{}, fmt.Errorf("invalid post code: %v", err)
+		}
+
+		code := uint8(value)
+		operation, ok := postCodeDesc[code]
+		if !ok {
+			operation = fmt.Sprintf("Post Code 0x%02X", code)
+		}
+
+		record.Kind = decoder.KindProgress
+		record.Code = uint64(code)
+		record.Subclass = "Post Code"
+		record.Operation = operation
+		record.Phase = postCodePhase[code]
+		if severity, ok := postCodeSeverity[code]; ok {
+			record.Kind = decoder.KindError
+			record.Severity = severity
+		}
+	} else if m := bootStateRegex.FindStringSubmatch(line); m != nil {
+		record.Subclass = "Boot State"
+		record.Operation = m[1]
+		record.Phase = bootStatePhase[m[1]]
+		record.Fields = bootStateTimes(m[2])
+	} else if jumpRegex.MatchString(line) {
+		record.Subclass = "Payload"
+		record.Operation = "Jumping to boot code"
+		record.Phase = decoder.PhaseOS
+	} else {
+		record.Subclass = "Console"
+		record.Operation = strings.TrimSpace(line)
+	}
+
+	if severity := logLevelSeverity[level]; severity != decoder.SeverityNone {
+		record.Kind = decoder.KindError
+		record.Severity = severity
+	}
+
+	return record, nil
+}
+
+func bootStateTimes(s string) []decoder.Field {
+	if m := timesRegex.FindStringSubmatch(s); m != nil {
+		return []decoder.Field{
+			{Name: "Entry", Value: m[1] + " ms"},
+			{Name: "Run", Value: m[2] + " ms"},
+			{Name: "Exit", Value: m[3] + " ms"},
+		}
+	}
+
+	if m := execTimesRegex.FindStringSubmatch(s); m != nil {
+		name := strings.ToUpper(m[1][:1]) + m[1][1:]
+		return []decoder.Field{
+			{Name: name, Value: m[2] + " ms"},
+			{Name: "Console", Value: m[3] + " ms"},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package coreboot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+func TestConsoleBlock(t *testing.T) {
+	log := `[ERROR] some other tool failed
+coreboot-4.19 Mon Jan 1 00:00:00 UTC 2024 romstage starting (log level: 7)...
+[ERROR]  PCI: 00:1c.0: Link training failed
+POST: 0x39
+Jumping to boot code at 0x00800000(0x99b55000)
+[CRIT ]  payload message
+`
+	want := []string{
+		"Stage romstage starting",
+		"Console PCI: 00:1c.0: Link training failed",
+		"Post Code Console Ready",
+		"Payload Jumping to boot code",
+	}
+
+	var got []string
+	err := decoder.NewScanner(Decoder{}).Scan(strings.NewReader(log), func(record decoder.Record, err error) error {
+		if err != nil {
+			return err
+		}
+		got = append(got, record.Subclass+" "+record.Operation)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("records:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
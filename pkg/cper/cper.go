@@ -0,0 +1,340 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package cper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Below are definitions of the Common Platform Error Record (CPER)
+//
+// A CPER record, as stored in the ERST or reported in error logs:
+//
+// ┌─────────────────────────┐
+// │ Record Header (128)     │  "CPER", section count, severity, ...
+// ├─────────────────────────┤
+// │ Section Descriptor (72) │  offset, length, type GUID, severity
+// │ ...                     │
+// ├─────────────────────────┤
+// │ Section                 │  processor, memory, PCIe, ... error
+// │ ...                     │
+// └─────────────────────────┘
+//
+// The Boot Error Record Table (BERT) points to a Generic Error Status
+// Block instead, holding Generic Error Data Entries with the same
+// sections:
+//
+// ┌─────────────────────────┐
+// │ Error Status Block (20) │  block status, data length, severity
+// ├─────────────────────────┤
+// │ Error Data Entry (64)   │  type GUID, severity, FRU
+// │ Section                 │
+// │ ...                     │
+// └─────────────────────────┘
+//
+// Reference: UEFI Specification, Appendix N - Common Platform Error Record
+//            ACPI Specification, 18.3.2.7 Generic Hardware Error Source
+//
+
+// Error Severity mappings
+var severityDesc = map[uint32]string{
+	0: "Recoverable",
+	1: "Fatal",
+	2: "Corrected",
+	3: "Informational",
+}
+
+// represents the CPER record header
+type RecordHeader struct {
+	SignatureStart   [4]byte
+	Revision         uint16
+	SignatureEnd     uint32
+	SectionCount     uint16
+	ErrorSeverity    uint32
+	ValidationBits   uint32
+	RecordLength     uint32
+	Timestamp        uint64
+	PlatformID       edk2.GUID
+	PartitionID      edk2.GUID
+	CreatorID        edk2.GUID
+	NotificationType edk2.GUID
+	RecordID         uint64
+	Flags            uint32
+	PersistenceInfo  uint64
+	Reserved         [12]byte
+}
+
+// represents a CPER section descriptor
+type SectionDescriptor struct {
+	SectionOffset   uint32
+	SectionLength   uint32
+	Revision        uint16
+	ValidationBits  uint8
+	Reserved        uint8
+	Flags           uint32
+	SectionType     edk2.GUID
+	FRUID           edk2.GUID
+	SectionSeverity uint32
+	FRUText         [20]byte
+}
+
+// represents an ACPI Generic Error Status Block header
+type ErrorStatusBlock struct {
+	BlockStatus   uint32
+	RawDataOffset uint32
+	RawDataLength uint32
+	DataLength    uint32
+	ErrorSeverity uint32
+}
+
+// represents an ACPI Generic Error Data Entry header
+type ErrorDataEntry struct {
+	SectionType     edk2.GUID
+	ErrorSeverity   uint32
+	Revision        uint16
+	ValidationBits  uint8
+	Flags           uint8
+	ErrorDataLength uint32
+	FRUID           edk2.GUID
+	FRUText         [20]byte
+}
+
+const (
+	recordHeaderSize      = 128
+	sectionDescriptorSize = 72
+	errorStatusBlockSize  = 20
+	errorDataEntrySize    = 64
+)
+
+// represents a decoded error record
+type Record struct {
+	Source    string     `json:"source"`
+	Severity  string     `json:"severity"`
+	Timestamp string     `json:"timestamp,omitempty"`
+	RecordID  uint64     `json:"record_id,omitempty"`
+	CreatorID *edk2.GUID `json:"creator_id,omitempty"`
+	Sections  []Section  `json:"sections"`
+}
+
+// represents a decoded error section
+type Section struct {
+	Type      edk2.GUID       `json:"type"`
+	TypeName  string          `json:"type_name"`
+	Severity  string          `json:"severity"`
+	FRUID     *edk2.GUID      `json:"fru_id,omitempty"`
+	FRUText   string          `json:"fru_text,omitempty"`
+	Processor *ProcessorError `json:"processor,omitempty"`
+	Memory    *MemoryError    `json:"memory,omitempty"`
+	PCIe      *PCIeError      `json:"pcie,omitempty"`
+	Raw       []byte          `json:"raw,omitempty"`
+	Related   *StatusCodeRef  `json:"related_status_code,omitempty"`
+}
+
+func describeSeverity(severity uint32) string {
+	desc, ok := severityDesc[severity]
+	if !ok {
+		return fmt.Sprintf("Unknown (%d)", severity)
+	}
+
+	return desc
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+
+	return string(bytes.TrimSpace(b))
+}
+
+// formatTimestamp decodes a CPER time stamp: seconds, minutes, hours,
+// flags, day, month, year and century, one BCD byte each.
+func formatTimestamp(ts uint64) string {
+	if ts == 0 {
+		return ""
+	}
+
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], ts)
+
+	bcd := func(v byte) int { return int(v>>4)*10 + int(v&0x0F) }
+	return fmt.Sprintf("%02d%02d-%02d-%02d %02d:%02d:%02d",
+		bcd(b[7]), bcd(b[6]), bcd(b[5]), bcd(b[4]), bcd(b[2]), bcd(b[1]), bcd(b[0]))
+}
+
+// Parse decodes the error records of a buffer holding either a
+// sequence of CPER records or a BERT Generic Error Status Block.
+func Parse(data []byte) ([]Record, error) {
+	if bytes.HasPrefix(data, []byte("CPER")) {
+		return parseRecords(data)
+	}
+
+	record, err := parseErrorStatusBlock(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Record{record}, nil
+}
+
+func ReadFile(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return records, nil
+}
+
+func parseRecords(data []byte) ([]Record, error) {
+	var records []Record
+
+	for offset := 0; offset+recordHeaderSize <= len(data); {
+		if !bytes.HasPrefix(data[offset:], []byte("CPER")) {
+			break
+		}
+
+		record, length, err := parseRecord(data[offset:])
+		if err != nil {
+			return nil, fmt.Errorf("record at offset 0x%X: %v", offset, err)
+		}
+		records = append(records, record)
+		offset += length
+	}
+
+	return records, nil
+}
+
+func parseRecord(data []byte) (Record, int, error) {
+	var header RecordHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return Record{}, 0, fmt.Errorf("invalid record header: %v", err)
+	}
+	if header.SignatureEnd != 0xFFFFFFFF {
+		return Record{}, 0, fmt.Errorf("invalid signature end 0x%08X", header.SignatureEnd)
+	}
+	if int(header.RecordLength) < recordHeaderSize || int(header.RecordLength) > len(data) {
+		return Record{}, 0, fmt.Errorf("invalid record length %d", header.RecordLength)
+	}
+	data = data[:header.RecordLength]
+
+	record := Record{
+		Source:   "CPER",
+		Severity: describeSeverity(header.ErrorSeverity),
+		RecordID: header.RecordID,
+	}
+	if !header.CreatorID.IsZero() {
+		creatorID := header.CreatorID
+		record.CreatorID = &creatorID
+	}
+	if header.ValidationBits&0x2 != 0 {
+		record.Timestamp = formatTimestamp(header.Timestamp)
+	}
+
+	for i := 0; i < int(header.SectionCount); i++ {
+		offset := recordHeaderSize + i*sectionDescriptorSize
+		if offset+sectionDescriptorSize > len(data) {
+			return Record{}, 0, fmt.Errorf("section descriptor %d out of bounds", i)
+		}
+
+		var desc SectionDescriptor
+		if err := binary.Read(bytes.NewReader(data[offset:]), binary.LittleEndian, &desc); err != nil {
+			return Record{}, 0, fmt.Errorf("invalid section descriptor %d: %v", i, err)
+		}
+
+		start, end := int(desc.SectionOffset), int(desc.SectionOffset)+int(desc.SectionLength)
+		if start < 0 || end > len(data) || start > end {
+			return Record{}, 0, fmt.Errorf("section %d out of bounds", i)
+		}
+
+		section := decodeSection(desc.SectionType, data[start:end])
+		section.Severity = describeSeverity(desc.SectionSeverity)
+		if desc.ValidationBits&0x1 != 0 {
+			fruID := desc.FRUID
+			section.FRUID = &fruID
+		}
+		if desc.ValidationBits&0x2 != 0 {
+			section.FRUText = cString(desc.FRUText[:])
+		}
+		record.Sections = append(record.Sections, section)
+	}
+
+	return record, int(header.RecordLength), nil
+}
+
+func parseErrorStatusBlock(data []byte) (Record, error) {
+	r := bytes.NewReader(data)
+
+	var block ErrorStatusBlock
+	if err := binary.Read(r, binary.LittleEndian, &block); err != nil {
+		return Record{}, fmt.Errorf("invalid error status block: %v", err)
+	}
+	if errorStatusBlockSize+int(block.DataLength) > len(data) {
+		return Record{}, fmt.Errorf("invalid error status block data length %d", block.DataLength)
+	}
+
+	record := Record{
+		Source:   "Generic Error Status Block",
+		Severity: describeSeverity(block.ErrorSeverity),
+	}
+
+	entries := data[errorStatusBlockSize : errorStatusBlockSize+int(block.DataLength)]
+	for offset := 0; offset+errorDataEntrySize <= len(entries); {
+		var entry ErrorDataEntry
+		if err := binary.Read(bytes.NewReader(entries[offset:]), binary.LittleEndian, &entry); err != nil {
+			return Record{}, fmt.Errorf("invalid error data entry: %v", err)
+		}
+
+		// Revision 3 and later entries carry an 8-byte time stamp
+		headerSize := errorDataEntrySize
+		if entry.Revision >= 0x300 {
+			headerSize += 8
+		}
+
+		start := offset + headerSize
+		end := start + int(entry.ErrorDataLength)
+		if end > len(entries) {
+			return Record{}, fmt.Errorf("error data entry at offset 0x%X out of bounds", offset)
+		}
+
+		section := decodeSection(entry.SectionType, entries[start:end])
+		section.Severity = describeSeverity(entry.ErrorSeverity)
+		if entry.ValidationBits&0x1 != 0 {
+			fruID := entry.FRUID
+			section.FRUID = &fruID
+		}
+		if entry.ValidationBits&0x2 != 0 {
+			section.FRUText = cString(entry.FRUText[:])
+		}
+		if entry.Revision >= 0x300 && entry.ValidationBits&0x4 != 0 && record.Timestamp == "" {
+			record.Timestamp = formatTimestamp(binary.LittleEndian.Uint64(entries[offset+errorDataEntrySize:]))
+		}
+		record.Sections = append(record.Sections, section)
+
+		offset = end
+	}
+
+	return record, nil
+}
+
+// ReadAll reads and decodes the error records from r.
+func ReadAll(r io.Reader) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package cper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// represents a section of a hand-built record
+type testSection struct {
+	sectionType edk2.GUID
+	severity    uint32
+	fruText     string
+	data        []byte
+}
+
+func encode(t *testing.T, v any) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := binary.Write(&b, binary.LittleEndian, v); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func memorySection(t *testing.T, errorType uint8) []byte {
+	return encode(t, memoryErrorSection{
+		ValidationBits:  1<<1 | 1<<3 | 1<<6 | 1<<14,
+		PhysicalAddress: 0x1_2345_6000,
+		Node:            1,
+		Bank:            3,
+		MemoryErrorType: errorType,
+	})
+}
+
+func processorSection(t *testing.T, errorType, flags uint8) []byte {
+	s := processorErrorSection{
+		ValidationBits: 1<<0 | 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5 | 1<<7 | 1<<8,
+		ProcessorType:  0x00,
+		ProcessorISA:   0x02,
+		ErrorType:      errorType,
+		Operation:      0x01,
+		Flags:          flags,
+		Level:          2,
+		ProcessorID:    0x10,
+	}
+	copy(s.CPUBrandString[:], "AMD EPYC 9654 96-Core Processor")
+
+	return encode(t, s)
+}
+
+func pcieSection(t *testing.T, uncorrectable, correctable uint32) []byte {
+	s := pcieErrorSection{
+		ValidationBits: 1<<0 | 1<<3 | 1<<7,
+		PortType:       0x04,
+		VendorID:       0x8086,
+		DeviceID:       0x347A,
+		Function:       0,
+		Device:         2,
+		Segment:        0,
+		PrimaryBus:     0x16,
+	}
+	binary.LittleEndian.PutUint32(s.AERInfo[4:8], uncorrectable)
+	binary.LittleEndian.PutUint32(s.AERInfo[16:20], correctable)
+
+	return encode(t, s)
+}
+
+// cperRecord builds a CPER record: the header, one descriptor per
+// section, then the sections
+func cperRecord(t *testing.T, recordID uint64, sections ...testSection) []byte {
+	length := recordHeaderSize + len(sections)*sectionDescriptorSize
+	offset := length
+	for _, s := range sections {
+		length += len(s.data)
+	}
+
+	header := RecordHeader{
+		SignatureStart: [4]byte{'C', 'P', 'E', 'R'},
+		Revision:       0x0101,
+		SignatureEnd:   0xFFFFFFFF,
+		SectionCount:   uint16(len(sections)),
+		ErrorSeverity:  1,
+		ValidationBits: 0x2,
+		RecordLength:   uint32(length),
+		Timestamp:      0x20_24_03_15_00_12_34_56, // 2024-03-15 12:34:56
+		CreatorID:      edk2.MustParseGUID("2DCE8BB1-BDD7-450E-B9AD-9CF4EBD4F890"),
+		RecordID:       recordID,
+	}
+
+	var b bytes.Buffer
+	b.Write(encode(t, header))
+	for _, s := range sections {
+		desc := SectionDescriptor{
+			SectionOffset:   uint32(offset),
+			SectionLength:   uint32(len(s.data)),
+			Revision:        0x0300,
+			SectionType:     s.sectionType,
+			SectionSeverity: s.severity,
+		}
+		if s.fruText != "" {
+			desc.ValidationBits = 0x2
+			copy(desc.FRUText[:], s.fruText)
+		}
+		b.Write(encode(t, desc))
+		offset += len(s.data)
+	}
+	for _, s := range sections {
+		b.Write(s.data)
+	}
+
+	return b.Bytes()
+}
+
+// bertDump builds the Generic Error Status Block of a BERT, with
+// revision 3 error data entries carrying a time stamp
+func bertDump(t *testing.T, sections ...testSection) []byte {
+	var entries bytes.Buffer
+	for _, s := range sections {
+		entry := ErrorDataEntry{
+			SectionType:     s.sectionType,
+			ErrorSeverity:   s.severity,
+			Revision:        0x0300,
+			ValidationBits:  0x4,
+			ErrorDataLength: uint32(len(s.data)),
+		}
+		if s.fruText != "" {
+			entry.ValidationBits |= 0x2
+			copy(entry.FRUText[:], s.fruText)
+		}
+		entries.Write(encode(t, entry))
+		entries.Write(encode(t, uint64(0x20_24_03_15_00_01_02_03))) // 2024-03-15 01:02:03
+		entries.Write(s.data)
+	}
+
+	block := ErrorStatusBlock{
+		BlockStatus:   0x1 | 1<<4,
+		DataLength:    uint32(entries.Len()),
+		ErrorSeverity: 1,
+	}
+
+	return append(encode(t, block), entries.Bytes()...)
+}
+
+func TestParseRecords(t *testing.T) {
+	data := append(
+		cperRecord(t, 0x1122,
+			testSection{ProcessorGenericSectionGUID, 1, "CPU0", processorSection(t, 0x01, 0x0)},
+			testSection{PlatformMemorySectionGUID, 2, "DIMM A1", memorySection(t, 0x02)},
+		),
+		cperRecord(t, 0x1123,
+			testSection{FirmwareErrorSectionGUID, 3, "", []byte{1, 2, 3, 4}},
+		)...,
+	)
+
+	records, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	r := records[0]
+	if r.Source != "CPER" || r.Severity != "Fatal" || r.RecordID != 0x1122 {
+		t.Errorf("record = %s %s 0x%X, want CPER Fatal 0x1122", r.Source, r.Severity, r.RecordID)
+	}
+	if r.Timestamp != "2024-03-15 12:34:56" {
+		t.Errorf("timestamp = %q, want 2024-03-15 12:34:56", r.Timestamp)
+	}
+	if r.CreatorID == nil || r.CreatorID.String() != "2DCE8BB1-BDD7-450E-B9AD-9CF4EBD4F890" {
+		t.Errorf("creator ID = %v", r.CreatorID)
+	}
+	if len(r.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(r.Sections))
+	}
+
+	p := r.Sections[0]
+	if p.TypeName != "Processor Generic" || p.Severity != "Fatal" || p.FRUText != "CPU0" {
+		t.Errorf("section 0 = %s %s %q", p.TypeName, p.Severity, p.FRUText)
+	}
+	if p.Processor == nil {
+		t.Fatal("section 0 has no processor error")
+	}
+	if got := *p.Processor; got.ProcessorType != "IA32/X64" || got.ISA != "X64" ||
+		got.ErrorType != "Cache Error" || got.Operation != "Data Read" || got.Corrected ||
+		got.Level == nil || *got.Level != 2 || got.ProcessorID == nil || *got.ProcessorID != 0x10 ||
+		got.BrandString != "AMD EPYC 9654 96-Core Processor" {
+		t.Errorf("processor error = %+v", got)
+	}
+	if p.Processor.TargetAddress != nil {
+		t.Errorf("target address is set without its validation bit")
+	}
+
+	m := r.Sections[1]
+	if m.TypeName != "Platform Memory" || m.Severity != "Corrected" || m.FRUText != "DIMM A1" {
+		t.Errorf("section 1 = %s %s %q", m.TypeName, m.Severity, m.FRUText)
+	}
+	if m.Memory == nil {
+		t.Fatal("section 1 has no memory error")
+	}
+	if got := *m.Memory; got.ErrorType != "Single-bit ECC" ||
+		got.PhysicalAddress == nil || *got.PhysicalAddress != 0x1_2345_6000 ||
+		got.Node == nil || *got.Node != 1 || got.Bank == nil || *got.Bank != 3 ||
+		got.Card != nil || got.Rank != nil {
+		t.Errorf("memory error = %+v", got)
+	}
+
+	f := records[1].Sections[0]
+	if f.TypeName != "Firmware Error Record Reference" || !bytes.Equal(f.Raw, []byte{1, 2, 3, 4}) {
+		t.Errorf("record 1 section = %s %v, want raw firmware error data", f.TypeName, f.Raw)
+	}
+}
+
+func TestParseErrorStatusBlock(t *testing.T) {
+	data := bertDump(t,
+		testSection{PCIeSectionGUID, 0, "Slot 3", pcieSection(t, 1<<12|1<<14, 1<<0)},
+		testSection{PlatformMemorySectionGUID, 1, "", memorySection(t, 0x03)},
+	)
+
+	records, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	r := records[0]
+	if r.Source != "Generic Error Status Block" || r.Severity != "Fatal" {
+		t.Errorf("record = %s %s", r.Source, r.Severity)
+	}
+	if r.Timestamp != "2024-03-15 01:02:03" {
+		t.Errorf("timestamp = %q, want the time stamp of the first entry", r.Timestamp)
+	}
+	if len(r.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(r.Sections))
+	}
+
+	p := r.Sections[0]
+	if p.TypeName != "PCI Express" || p.Severity != "Recoverable" || p.FRUText != "Slot 3" {
+		t.Errorf("section 0 = %s %s %q", p.TypeName, p.Severity, p.FRUText)
+	}
+	if p.PCIe == nil {
+		t.Fatal("section 0 has no PCIe error")
+	}
+	if got := *p.PCIe; got.PortType != "Root Port" || got.Device != "0000:16:02.0" ||
+		*got.VendorID != 0x8086 || *got.DeviceID != 0x347A ||
+		strings.Join(got.Uncorrectable, ",") != "Poisoned TLP,Completion Timeout" ||
+		strings.Join(got.Correctable, ",") != "Receiver Error" {
+		t.Errorf("PCIe error = %+v", got)
+	}
+
+	if m := r.Sections[1].Memory; m == nil || m.ErrorType != "Multi-bit ECC" {
+		t.Errorf("section 1 memory error = %+v, want Multi-bit ECC", m)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	record := cperRecord(t, 1, testSection{PlatformMemorySectionGUID, 2, "", memorySection(t, 0x02)})
+
+	corrupt := func(offset int, v uint32) []byte {
+		data := bytes.Clone(record)
+		binary.LittleEndian.PutUint32(data[offset:], v)
+		return data
+	}
+
+	// Offsets in the record header and the first section descriptor
+	const (
+		signatureEnd  = 6
+		recordLength  = 20
+		sectionOffset = recordHeaderSize
+		sectionLength = recordHeaderSize + 4
+	)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"signature end", corrupt(signatureEnd, 0), "invalid signature end 0x00000000"},
+		{"record length too short", corrupt(recordLength, 64), "invalid record length 64"},
+		{"record length too long", corrupt(recordLength, uint32(len(record)+1)), "invalid record length"},
+		{"section offset", corrupt(sectionOffset, uint32(len(record))), "section 0 out of bounds"},
+		{"section length", corrupt(sectionLength, 0x1000), "section 0 out of bounds"},
+		{"truncated status block", []byte{1, 0, 0, 0}, "invalid error status block"},
+		{
+			"status block data length",
+			func() []byte {
+				data := bertDump(t)
+				binary.LittleEndian.PutUint32(data[12:], 0x1000)
+				return data
+			}(),
+			"invalid error status block data length 4096",
+		},
+		{
+			"error data entry length",
+			func() []byte {
+				data := bertDump(t, testSection{PlatformMemorySectionGUID, 2, "", memorySection(t, 0x02)})
+				binary.LittleEndian.PutUint32(data[errorStatusBlockSize+24:], 0x1000)
+				return data
+			}(),
+			"error data entry at offset 0x0 out of bounds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseShortSection(t *testing.T) {
+	// A memory section too short to decode is kept as raw data
+	records, err := Parse(cperRecord(t, 1, testSection{PlatformMemorySectionGUID, 2, "", []byte{1, 2, 3}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := records[0].Sections[0]
+	if s.Memory != nil || len(s.Raw) != 3 || s.Related != nil {
+		t.Errorf("section = %+v, want 3 raw bytes", s)
+	}
+}
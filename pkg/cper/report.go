@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package cper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// EDK2 error code values corresponding to CPER errors
+const (
+	cuHostProcessorCache         uint32 = 0x00011009 // EFI_CU_HP_EC_CACHE
+	cuHostProcessorCorrectable   uint32 = 0x0001100B // EFI_CU_HP_EC_CORRECTABLE
+	cuHostProcessorUncorrectable uint32 = 0x0001100C // EFI_CU_HP_EC_UNCORRECTABLE
+	cuMemoryCorrectable          uint32 = 0x00051002 // EFI_CU_MEMORY_EC_CORRECTABLE
+	cuMemoryUncorrectable        uint32 = 0x00051003 // EFI_CU_MEMORY_EC_UNCORRECTABLE
+	iobPciPERR                   uint32 = 0x02011000 // EFI_IOB_PCI_EC_PERR
+	iobPciSERR                   uint32 = 0x02011001 // EFI_IOB_PCI_EC_SERR
+)
+
+// Memory Error Type to EDK2 error code mappings
+var memoryErrorStatusCode = map[uint8]uint32{
+	0x02: cuMemoryCorrectable,
+	0x03: cuMemoryUncorrectable,
+	0x04: cuMemoryCorrectable,
+	0x05: cuMemoryUncorrectable,
+	0x0D: cuMemoryCorrectable,
+	0x0E: cuMemoryUncorrectable,
+}
+
+// AER uncorrectable errors reported as data parity errors
+const aerParityErrors uint32 = 1<<12 | 1<<18 | 1<<19 // Poisoned TLP, Malformed TLP, ECRC
+
+// represents the EDK2 error code corresponding to an error section,
+// as reported by the firmware through ReportStatusCode()
+type StatusCodeRef struct {
+	Value     string `json:"value"`
+	Class     string `json:"class"`
+	Subclass  string `json:"subclass"`
+	Operation string `json:"operation"`
+}
+
+func newStatusCodeRef(value uint32) *StatusCodeRef {
+	desc := edk2.DescribeStatusCode(edk2.StatusCode{
+		Type:  edk2.EFIStatusCodeType{Type: edk2.EFI_ERROR_CODE},
+		Value: edk2.NewStatusCodeValue(value),
+	})
+
+	return &StatusCodeRef{
+		Value:     fmt.Sprintf("V%08X", value),
+		Class:     desc.Class,
+		Subclass:  desc.Subclass,
+		Operation: desc.Operation,
+	}
+}
+
+func relatedStatusCode(section Section) *StatusCodeRef {
+	switch {
+	case section.Memory != nil && section.Memory.hasType:
+		if value, ok := memoryErrorStatusCode[section.Memory.errorType]; ok {
+			return newStatusCodeRef(value)
+		}
+	case section.Processor != nil:
+		if section.Processor.hasType && section.Processor.errorType == 0x01 {
+			return newStatusCodeRef(cuHostProcessorCache)
+		}
+		if section.Processor.Corrected {
+			return newStatusCodeRef(cuHostProcessorCorrectable)
+		}
+		return newStatusCodeRef(cuHostProcessorUncorrectable)
+	case section.PCIe != nil && section.PCIe.uncorrectable != 0:
+		if section.PCIe.uncorrectable&aerParityErrors != 0 {
+			return newStatusCodeRef(iobPciPERR)
+		}
+		return newStatusCodeRef(iobPciSERR)
+	}
+
+	return nil
+}
+
+// WriteJSON writes the records as an indented JSON report.
+func WriteJSON(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(records)
+}
+
+// WriteText writes the records as a human-readable report.
+func WriteText(w io.Writer, records []Record) error {
+	var b strings.Builder
+
+	line := func(indent int, name, format string, args ...any) {
+		fmt.Fprintf(&b, "%s%-16s:  %s\n", strings.Repeat("  ", indent), name, fmt.Sprintf(format, args...))
+	}
+
+	for i, record := range records {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "Record %d (%s)\n", i, record.Source)
+		line(0, "Severity", "%s", record.Severity)
+		if record.Timestamp != "" {
+			line(0, "Timestamp", "%s", record.Timestamp)
+		}
+		if record.RecordID != 0 {
+			line(0, "Record ID", "0x%016X", record.RecordID)
+		}
+		if record.CreatorID != nil {
+			line(0, "Creator ID", "%s", record.CreatorID)
+		}
+
+		for j, section := range record.Sections {
+			fmt.Fprintf(&b, "  Section %d: %s\n", j, section.TypeName)
+			line(1, "Type", "%s", section.Type)
+			line(1, "Severity", "%s", section.Severity)
+			if section.FRUID != nil {
+				line(1, "FRU ID", "%s", section.FRUID)
+			}
+			if section.FRUText != "" {
+				line(1, "FRU Text", "%s", section.FRUText)
+			}
+
+			if p := section.Processor; p != nil {
+				line(1, "Processor Type", "%s", p.ProcessorType)
+				line(1, "ISA", "%s", p.ISA)
+				line(1, "Error Type", "%s", p.ErrorType)
+				line(1, "Operation", "%s", p.Operation)
+				line(1, "Corrected", "%t", p.Corrected)
+				if p.Level != nil {
+					line(1, "Level", "%d", *p.Level)
+				}
+				if p.ProcessorID != nil {
+					line(1, "Processor ID", "0x%X", *p.ProcessorID)
+				}
+				if p.TargetAddress != nil {
+					line(1, "Target Address", "0x%016X", *p.TargetAddress)
+				}
+				if p.InstructionIP != nil {
+					line(1, "Instruction IP", "0x%016X", *p.InstructionIP)
+				}
+			}
+
+			if m := section.Memory; m != nil {
+				line(1, "Error Type", "%s", m.ErrorType)
+				if m.PhysicalAddress != nil {
+					line(1, "Physical Address", "0x%016X", *m.PhysicalAddress)
+				}
+				for _, f := range []struct {
+					name  string
+					value *uint16
+				}{
+					{"Node", m.Node}, {"Card", m.Card}, {"Module", m.Module}, {"Bank", m.Bank},
+					{"Device", m.Device}, {"Row", m.Row}, {"Column", m.Column},
+					{"Bit Position", m.BitPosition}, {"Rank", m.Rank},
+				} {
+					if f.value != nil {
+						line(1, f.name, "%d", *f.value)
+					}
+				}
+			}
+
+			if p := section.PCIe; p != nil {
+				line(1, "Port Type", "%s", p.PortType)
+				if p.Device != "" {
+					line(1, "Device", "%s [%04x:%04x]", p.Device, *p.VendorID, *p.DeviceID)
+				}
+				if len(p.Uncorrectable) > 0 {
+					line(1, "Uncorrectable", "%s", strings.Join(p.Uncorrectable, ", "))
+				}
+				if len(p.Correctable) > 0 {
+					line(1, "Correctable", "%s", strings.Join(p.Correctable, ", "))
+				}
+			}
+
+			if len(section.Raw) > 0 {
+				line(1, "Data", "%d bytes", len(section.Raw))
+			}
+
+			if r := section.Related; r != nil {
+				line(1, "EDK2 Status Code", "%s (%s, %s, %s)", r.Value, r.Class, r.Subclass, r.Operation)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
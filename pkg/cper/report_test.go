@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package cper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRelatedStatusCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		section testSection
+		want    string
+	}{
+		{"single-bit ECC", testSection{PlatformMemorySectionGUID, 2, "", memorySection(t, 0x02)},
+			"V00051002 (Computing, Memory, Correctable)"},
+		{"multi-bit ECC", testSection{PlatformMemorySectionGUID, 1, "", memorySection(t, 0x03)},
+			"V00051003 (Computing, Memory, Uncorrectable)"},
+		{"scrub corrected", testSection{PlatformMemorySectionGUID, 2, "", memorySection(t, 0x0D)},
+			"V00051002 (Computing, Memory, Correctable)"},
+		{"scrub uncorrected", testSection{PlatformMemorySectionGUID, 1, "", memorySection(t, 0x0E)},
+			"V00051003 (Computing, Memory, Uncorrectable)"},
+		{"memory map-out", testSection{PlatformMemorySectionGUID, 3, "", memorySection(t, 0x0F)}, ""},
+		{"processor cache", testSection{ProcessorGenericSectionGUID, 1, "", processorSection(t, 0x01, 0x0)},
+			"V00011009 (Computing, Host Processor, Cache)"},
+		{"processor corrected", testSection{ProcessorGenericSectionGUID, 2, "", processorSection(t, 0x04, 0x8)},
+			"V0001100B (Computing, Host Processor, Correctable)"},
+		{"processor uncorrected", testSection{ProcessorGenericSectionGUID, 1, "", processorSection(t, 0x04, 0x0)},
+			"V0001100C (Computing, Host Processor, Uncorrectable)"},
+		{"poisoned TLP", testSection{PCIeSectionGUID, 0, "", pcieSection(t, 1<<12, 0)},
+			"V02011000 (I/O Bus, PCI, PCI PERR)"},
+		{"completion timeout", testSection{PCIeSectionGUID, 0, "", pcieSection(t, 1<<14, 0)},
+			"V02011001 (I/O Bus, PCI, PCI SERR)"},
+		{"PCIe correctable only", testSection{PCIeSectionGUID, 2, "", pcieSection(t, 0, 1<<6)}, ""},
+		{"firmware error", testSection{FirmwareErrorSectionGUID, 1, "", []byte{0}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(bertDump(t, tt.section))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			related := records[0].Sections[0].Related
+			got := ""
+			if related != nil {
+				got = related.Value + " (" + related.Class + ", " + related.Subclass + ", " + related.Operation + ")"
+			}
+			if got != tt.want {
+				t.Errorf("related status code = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	records, err := Parse(bertDump(t, testSection{PlatformMemorySectionGUID, 2, "DIMM A1", memorySection(t, 0x0D)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := WriteText(&b, records); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Record 0 (Generic Error Status Block)
+Severity        :  Fatal
+Timestamp       :  2024-03-15 01:02:03
+  Section 0: Platform Memory
+  Type            :  A5BC1114-6F64-4EDE-B863-3E83ED7C83B1
+  Severity        :  Corrected
+  FRU Text        :  DIMM A1
+  Error Type      :  Scrub Corrected Error
+  Physical Address:  0x0000000123456000
+  Node            :  1
+  Bank            :  3
+  EDK2 Status Code:  V00051002 (Computing, Memory, Correctable)
+`
+	if b.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	records, err := Parse(bertDump(t, testSection{PCIeSectionGUID, 0, "", pcieSection(t, 1<<12, 0)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := WriteJSON(&b, records); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"type": "D995E954-BBC1-430F-AD91-B44DCB3C6F35"`,
+		`"Poisoned TLP"`,
+		`"value": "V02011000"`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteJSON() output lacks %s:\n%s", want, b.String())
+		}
+	}
+}
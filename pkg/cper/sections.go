@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package cper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Section Type GUIDs
+var (
+	ProcessorGenericSectionGUID = edk2.MustParseGUID("9876CCAD-47B4-4BDB-B65E-16F193C4F3DB")
+	ProcessorIA32X64SectionGUID = edk2.MustParseGUID("DC3EA0B0-A144-4797-B95B-53FA242B6E1D")
+	ProcessorARMSectionGUID     = edk2.MustParseGUID("E19E3D16-BC11-11E4-9CAA-C2051D5D46B0")
+	PlatformMemorySectionGUID   = edk2.MustParseGUID("A5BC1114-6F64-4EDE-B863-3E83ED7C83B1")
+	PlatformMemory2SectionGUID  = edk2.MustParseGUID("61EC04FC-48E6-D813-25C9-8DAA44750B12")
+	PCIeSectionGUID             = edk2.MustParseGUID("D995E954-BBC1-430F-AD91-B44DCB3C6F35")
+	FirmwareErrorSectionGUID    = edk2.MustParseGUID("81212A96-09ED-4996-9471-8D729C8E69ED")
+	PCIBusSectionGUID           = edk2.MustParseGUID("C5753963-3B84-4095-BF78-EDDAD3F9C9DD")
+	PCIComponentSectionGUID     = edk2.MustParseGUID("EB5E4685-CA66-4769-B6A2-26068B001326")
+)
+
+// Section Type mappings
+var sectionTypeDesc = map[edk2.GUID]string{
+	ProcessorGenericSectionGUID: "Processor Generic",
+	ProcessorIA32X64SectionGUID: "IA32/X64 Processor",
+	ProcessorARMSectionGUID:     "ARM Processor",
+	PlatformMemorySectionGUID:   "Platform Memory",
+	PlatformMemory2SectionGUID:  "Platform Memory 2",
+	PCIeSectionGUID:             "PCI Express",
+	FirmwareErrorSectionGUID:    "Firmware Error Record Reference",
+	PCIBusSectionGUID:           "PCI/PCI-X Bus",
+	PCIComponentSectionGUID:     "PCI Component/Device",
+}
+
+//
+// Processor Generic Error Section
+//
+
+type processorErrorSection struct {
+	ValidationBits uint64
+	ProcessorType  uint8
+	ProcessorISA   uint8
+	ErrorType      uint8
+	Operation      uint8
+	Flags          uint8
+	Level          uint8
+	Reserved       uint16
+	CPUVersionInfo uint64
+	CPUBrandString [128]byte
+	ProcessorID    uint64
+	TargetAddress  uint64
+	RequestorID    uint64
+	ResponderID    uint64
+	InstructionIP  uint64
+}
+
+var processorTypeDesc = map[uint8]string{
+	0x00: "IA32/X64",
+	0x01: "IA64",
+	0x02: "ARM",
+}
+
+var processorISADesc = map[uint8]string{
+	0x00: "IA32",
+	0x01: "IA64",
+	0x02: "X64",
+	0x03: "ARM A32/T32",
+	0x04: "ARM A64",
+}
+
+var processorErrorTypeDesc = map[uint8]string{
+	0x00: "Unknown",
+	0x01: "Cache Error",
+	0x02: "TLB Error",
+	0x04: "Bus Error",
+	0x08: "Micro-Architectural Error",
+}
+
+var processorOperationDesc = map[uint8]string{
+	0x00: "Unknown or Generic",
+	0x01: "Data Read",
+	0x02: "Data Write",
+	0x03: "Instruction Execution",
+}
+
+// represents a decoded processor generic error section
+type ProcessorError struct {
+	ProcessorType string  `json:"processor_type,omitempty"`
+	ISA           string  `json:"isa,omitempty"`
+	ErrorType     string  `json:"error_type,omitempty"`
+	Operation     string  `json:"operation,omitempty"`
+	Corrected     bool    `json:"corrected"`
+	Restartable   bool    `json:"restartable"`
+	Overflow      bool    `json:"overflow"`
+	Level         *uint8  `json:"level,omitempty"`
+	BrandString   string  `json:"brand_string,omitempty"`
+	ProcessorID   *uint64 `json:"processor_id,omitempty"`
+	TargetAddress *uint64 `json:"target_address,omitempty"`
+	InstructionIP *uint64 `json:"instruction_ip,omitempty"`
+
+	errorType uint8
+	hasType   bool
+}
+
+func decodeProcessorError(data []byte) (*ProcessorError, error) {
+	var s processorErrorSection
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("invalid processor error section: %v", err)
+	}
+
+	valid := func(bit uint) bool { return s.ValidationBits&(1<<bit) != 0 }
+	describe := func(desc map[uint8]string, v uint8) string {
+		if d, ok := desc[v]; ok {
+			return d
+		}
+		return fmt.Sprintf("Unknown (0x%02X)", v)
+	}
+
+	p := &ProcessorError{}
+	if valid(0) {
+		p.ProcessorType = describe(processorTypeDesc, s.ProcessorType)
+	}
+	if valid(1) {
+		p.ISA = describe(processorISADesc, s.ProcessorISA)
+	}
+	if valid(2) {
+		p.ErrorType = describe(processorErrorTypeDesc, s.ErrorType)
+		p.errorType = s.ErrorType
+		p.hasType = true
+	}
+	if valid(3) {
+		p.Operation = describe(processorOperationDesc, s.Operation)
+	}
+	if valid(4) {
+		p.Restartable = s.Flags&0x1 != 0
+		p.Overflow = s.Flags&0x4 != 0
+		p.Corrected = s.Flags&0x8 != 0
+	}
+	if valid(5) {
+		level := s.Level
+		p.Level = &level
+	}
+	if valid(7) {
+		p.BrandString = cString(s.CPUBrandString[:])
+	}
+	if valid(8) {
+		p.ProcessorID = &s.ProcessorID
+	}
+	if valid(9) {
+		p.TargetAddress = &s.TargetAddress
+	}
+	if valid(12) {
+		p.InstructionIP = &s.InstructionIP
+	}
+
+	return p, nil
+}
+
+//
+// Platform Memory Error Section
+//
+
+type memoryErrorSection struct {
+	ValidationBits      uint64
+	ErrorStatus         uint64
+	PhysicalAddress     uint64
+	PhysicalAddressMask uint64
+	Node                uint16
+	Card                uint16
+	Module              uint16
+	Bank                uint16
+	Device              uint16
+	Row                 uint16
+	Column              uint16
+	BitPosition         uint16
+	RequestorID         uint64
+	ResponderID         uint64
+	TargetID            uint64
+	MemoryErrorType     uint8
+	Extended            uint8
+	RankNumber          uint16
+	CardHandle          uint16
+	ModuleHandle        uint16
+}
+
+var memoryErrorTypeDesc = map[uint8]string{
+	0x00: "Unknown",
+	0x01: "No Error",
+	0x02: "Single-bit ECC",
+	0x03: "Multi-bit ECC",
+	0x04: "Single-symbol ChipKill ECC",
+	0x05: "Multi-symbol ChipKill ECC",
+	0x06: "Master Abort",
+	0x07: "Target Abort",
+	0x08: "Parity Error",
+	0x09: "Watchdog Timeout",
+	0x0A: "Invalid Address",
+	0x0B: "Mirror Broken",
+	0x0C: "Memory Sparing",
+	0x0D: "Scrub Corrected Error",
+	0x0E: "Scrub Uncorrected Error",
+	0x0F: "Physical Memory Map-out Event",
+}
+
+// represents a decoded platform memory error section
+type MemoryError struct {
+	ErrorType       string  `json:"error_type,omitempty"`
+	PhysicalAddress *uint64 `json:"physical_address,omitempty"`
+	Node            *uint16 `json:"node,omitempty"`
+	Card            *uint16 `json:"card,omitempty"`
+	Module          *uint16 `json:"module,omitempty"`
+	Bank            *uint16 `json:"bank,omitempty"`
+	Device          *uint16 `json:"device,omitempty"`
+	Row             *uint16 `json:"row,omitempty"`
+	Column          *uint16 `json:"column,omitempty"`
+	BitPosition     *uint16 `json:"bit_position,omitempty"`
+	Rank            *uint16 `json:"rank,omitempty"`
+
+	errorType uint8
+	hasType   bool
+}
+
+func decodeMemoryError(data []byte) (*MemoryError, error) {
+	var s memoryErrorSection
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("invalid memory error section: %v", err)
+	}
+
+	valid := func(bit uint) bool { return s.ValidationBits&(1<<bit) != 0 }
+	field := func(bit uint, v uint16) *uint16 {
+		if !valid(bit) {
+			return nil
+		}
+		return &v
+	}
+
+	m := &MemoryError{
+		Node:        field(3, s.Node),
+		Card:        field(4, s.Card),
+		Module:      field(5, s.Module),
+		Bank:        field(6, s.Bank),
+		Device:      field(7, s.Device),
+		Row:         field(8, s.Row),
+		Column:      field(9, s.Column),
+		BitPosition: field(10, s.BitPosition),
+		Rank:        field(15, s.RankNumber),
+	}
+	if valid(1) {
+		m.PhysicalAddress = &s.PhysicalAddress
+	}
+	if valid(14) {
+		desc, ok := memoryErrorTypeDesc[s.MemoryErrorType]
+		if !ok {
+			desc = fmt.Sprintf("Unknown (0x%02X)", s.MemoryErrorType)
+		}
+		m.ErrorType = desc
+		m.errorType = s.MemoryErrorType
+		m.hasType = true
+	}
+
+	return m, nil
+}
+
+//
+// PCI Express Error Section
+//
+
+type pcieErrorSection struct {
+	ValidationBits      uint64
+	PortType            uint32
+	VersionMinor        uint8
+	VersionMajor        uint8
+	VersionReserved     uint16
+	Command             uint16
+	Status              uint16
+	Reserved            uint32
+	VendorID            uint16
+	DeviceID            uint16
+	ClassCode           [3]byte
+	Function            uint8
+	Device              uint8
+	Segment             uint16
+	PrimaryBus          uint8
+	SecondaryBus        uint8
+	Slot                uint16
+	DeviceReserved      uint8
+	SerialNumber        uint64
+	BridgeSecondaryStat uint16
+	BridgeControl       uint16
+	Capability          [60]byte
+	AERInfo             [96]byte
+}
+
+var pciePortTypeDesc = map[uint32]string{
+	0x00: "PCI Express End Point",
+	0x01: "Legacy PCI End Point",
+	0x04: "Root Port",
+	0x05: "Upstream Switch Port",
+	0x06: "Downstream Switch Port",
+	0x07: "PCI Express to PCI/PCI-X Bridge",
+	0x08: "PCI/PCI-X to PCI Express Bridge",
+	0x09: "Root Complex Integrated Endpoint",
+	0x0A: "Root Complex Event Collector",
+}
+
+// AER Uncorrectable Error Status bits
+var aerUncorrectableDesc = map[uint]string{
+	4:  "Data Link Protocol Error",
+	5:  "Surprise Down Error",
+	12: "Poisoned TLP",
+	13: "Flow Control Protocol Error",
+	14: "Completion Timeout",
+	15: "Completer Abort",
+	16: "Unexpected Completion",
+	17: "Receiver Overflow",
+	18: "Malformed TLP",
+	19: "ECRC Error",
+	20: "Unsupported Request",
+	21: "ACS Violation",
+	22: "Uncorrectable Internal Error",
+}
+
+// AER Correctable Error Status bits
+var aerCorrectableDesc = map[uint]string{
+	0:  "Receiver Error",
+	6:  "Bad TLP",
+	7:  "Bad DLLP",
+	8:  "REPLAY_NUM Rollover",
+	12: "Replay Timer Timeout",
+	13: "Advisory Non-Fatal Error",
+	14: "Corrected Internal Error",
+	15: "Header Log Overflow",
+}
+
+// represents a decoded PCI Express error section
+type PCIeError struct {
+	PortType      string   `json:"port_type,omitempty"`
+	Device        string   `json:"device,omitempty"`
+	VendorID      *uint16  `json:"vendor_id,omitempty"`
+	DeviceID      *uint16  `json:"device_id,omitempty"`
+	Uncorrectable []string `json:"uncorrectable_errors,omitempty"`
+	Correctable   []string `json:"correctable_errors,omitempty"`
+
+	uncorrectable uint32
+}
+
+func decodePCIeError(data []byte) (*PCIeError, error) {
+	var s pcieErrorSection
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("invalid PCIe error section: %v", err)
+	}
+
+	valid := func(bit uint) bool { return s.ValidationBits&(1<<bit) != 0 }
+
+	p := &PCIeError{}
+	if valid(0) {
+		desc, ok := pciePortTypeDesc[s.PortType]
+		if !ok {
+			desc = fmt.Sprintf("Unknown (0x%X)", s.PortType)
+		}
+		p.PortType = desc
+	}
+	if valid(3) {
+		p.Device = fmt.Sprintf("%04x:%02x:%02x.%x", s.Segment, s.PrimaryBus, s.Device, s.Function)
+		p.VendorID = &s.VendorID
+		p.DeviceID = &s.DeviceID
+	}
+	if valid(7) {
+		uncorrectable := binary.LittleEndian.Uint32(s.AERInfo[4:8])
+		correctable := binary.LittleEndian.Uint32(s.AERInfo[16:20])
+		p.Uncorrectable = statusBits(uncorrectable, aerUncorrectableDesc)
+		p.uncorrectable = uncorrectable
+		p.Correctable = statusBits(correctable, aerCorrectableDesc)
+	}
+
+	return p, nil
+}
+
+func statusBits(status uint32, desc map[uint]string) []string {
+	var bits []string
+	for bit := uint(0); bit < 32; bit++ {
+		if status&(1<<bit) == 0 {
+			continue
+		}
+
+		name, ok := desc[bit]
+		if !ok {
+			name = fmt.Sprintf("Bit %d", bit)
+		}
+		bits = append(bits, name)
+	}
+
+	return bits
+}
+
+func decodeSection(sectionType edk2.GUID, data []byte) Section {
+	section := Section{
+		Type:     sectionType,
+		TypeName: sectionTypeDesc[sectionType],
+	}
+	if section.TypeName == "" {
+		section.TypeName = "Unknown"
+	}
+
+	var err error
+	switch sectionType {
+	case ProcessorGenericSectionGUID:
+		section.Processor, err = decodeProcessorError(data)
+	case PlatformMemorySectionGUID:
+		section.Memory, err = decodeMemoryError(data)
+	case PCIeSectionGUID:
+		section.PCIe, err = decodePCIeError(data)
+	default:
+		section.Raw = data
+	}
+	if err != nil {
+		section.Raw = data
+	}
+
+	section.Related = relatedStatusCode(section)
+
+	return section
+}
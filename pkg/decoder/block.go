@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+// BlockDecoder is implemented by decoders of lines that are only in
+// their format inside a block of the log, such as the rows of a table
+// following its header. Match and Decode see one line at a time, so
+// the Scanner and the Pipeline follow the blocks in line order and drop
+// the records that need a block but were decoded outside of one.
+type BlockDecoder interface {
+	Decoder
+	// InBlock reports whether the line following line is inside a
+	// block, given whether line is. The line has no time stamp prefix.
+	InBlock(line string, inBlock bool) bool
+	// NeedsBlock reports whether a record of the decoder is only valid
+	// inside a block
+	NeedsBlock(record Record) bool
+}
+
+// blockState follows the blocks of the block decoders, in line order
+type blockState struct {
+	decoders []BlockDecoder
+	inBlock  []bool
+}
+
+func newBlockState(decoders []Decoder) *blockState {
+	b := &blockState{}
+	for _, d := range decoders {
+		if bd, ok := d.(BlockDecoder); ok {
+			b.decoders = append(b.decoders, bd)
+		}
+	}
+	b.inBlock = make([]bool, len(b.decoders))
+
+	return b
+}
+
+func (b *blockState) reset() {
+	clear(b.inBlock)
+}
+
+// next drops the record of a line decoded outside of the block it
+// needs, then moves past the line
+func (b *blockState) next(d *decodedLine) {
+	for i, bd := range b.decoders {
+		if d.ok && d.err == nil && !b.inBlock[i] && d.record.Decoder == bd.Name() && bd.NeedsBlock(d.record) {
+			*d = decodedLine{text: d.text}
+		}
+		b.inBlock[i] = bd.InBlock(d.text, b.inBlock[i])
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decoder recognises one boot log line format and decodes matching
+// lines into records.
+type Decoder interface {
+	// Name returns a short identifier of the decoder, e.g. "edk2"
+	Name() string
+	// Match reports whether the line is in the decoder's format
+	Match(line string) bool
+	// Decode decodes a line for which Match returned true
+	Decode(line string) (Record, error)
+}
+
+// represents the kind of a decoded record
+type Kind uint8
+
+const (
+	KindInfo Kind = iota
+	KindProgress
+	KindError
+	KindDebug
+)
+
+var kindDesc = map[Kind]string{
+	KindInfo:     "Info",
+	KindProgress: "Progress Code",
+	KindError:    "Error Code",
+	KindDebug:    "Debug Code",
+}
+
+func (k Kind) String() string {
+	return kindDesc[k]
+}
+
+// represents the severity of an error record, ordered from least to
+// most severe
+type Severity uint8
+
+const (
+	SeverityNone Severity = iota
+	SeverityMinor
+	SeverityMajor
+	SeverityUnrecovered
+	SeverityUncontained
+)
+
+var severityDesc = map[Severity]string{
+	SeverityNone:        "",
+	SeverityMinor:       "Minor Error",
+	SeverityMajor:       "Major Error",
+	SeverityUnrecovered: "Unrecovered Error",
+	SeverityUncontained: "Uncontained Error",
+}
+
+func (s Severity) String() string {
+	return severityDesc[s]
+}
+
+// ParseSeverity parses a severity name, either as printed ("Major
+// Error") or short ("major"). "none" parses as SeverityNone.
+func ParseSeverity(name string) (Severity, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "none" {
+		return SeverityNone, true
+	}
+
+	for severity, desc := range severityDesc {
+		desc = strings.ToLower(desc)
+		if desc != "" && (name == desc || name == strings.TrimSuffix(desc, " error")) {
+			return severity, true
+		}
+	}
+
+	return SeverityNone, false
+}
+
+// represents a boot boundary marked by a record
+type Boundary uint8
+
+const (
+	BoundaryNone      Boundary = iota
+	BoundaryBootStart          // the record is the first of a boot, e.g. a SEC entry code
+	BoundaryReset              // the record is the last of a boot, e.g. a reset message
+	BoundaryHistory            // the record reports an earlier event of the boot, e.g. a bootstage report line
+)
+
+// represents an additional decoded field of a record
+type Field struct {
+	Name  string
+	Value string
+}
+
+// represents a decoded boot log line
+type Record struct {
+	Decoder      string
+	LineNo       int
+	Line         string
+	Timestamp    time.Duration
+	HasTimestamp bool
+	Kind         Kind
+	Severity     Severity
+	Phase        Phase
+	Boundary     Boundary
+	Code         uint64
+	Instance     uint32
+	Class        string
+	Subclass     string
+	Operation    string
+	Module       string
+	Fields       []Field
+}
+
+// IsUnknown reports whether the decoder could not describe the class,
+// subclass or operation of the record's code.
+func (r Record) IsUnknown() bool {
+	for _, desc := range []string{r.Class, r.Subclass, r.Operation} {
+		if desc == "Unknown" || strings.HasPrefix(desc, "Unknown ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+var postCodeRegex = regexp.MustCompile(`(?i)^(?:post\s*code|port\s*80)\s*[:=]\s*<?\s*(?:0x)?([0-9a-f]{1,16})\s*>?`)
+
+// ParsePostCodeLine extracts the value of a raw POST code line, as
+// printed by many firmware stacks and port 0x80 capture tools, e.g.
+//
+//	POST CODE: 0xD800
+//	POSTCODE=<0xEA00E0B0>
+//	Port80: 4F
+func ParsePostCodeLine(line string) (uint64, bool) {
+	// cheap check first, most lines of a log are not post codes
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] != 'P' && line[0] != 'p' {
+		return 0, false
+	}
+
+	m := postCodeRegex.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+
+	value, err := strconv.ParseUint(m[1], 16, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+// Boot phases of the firmware stacks are mapped to a common model,
+// named after the PI boot phases:
+//
+// ┌───────┬─────────────────┬─────────────────┬──────────────┬──────────────────┐
+// │ Phase │ EDK2            │ coreboot        │ U-Boot       │ TF-A             │
+// ├───────┼─────────────────┼─────────────────┼──────────────┼──────────────────┤
+// │ SEC   │ SEC             │ bootblock       │ reset        │ BL1              │
+// │ PEI   │ PEI             │ romstage        │ SPL          │ BL2              │
+// │ DXE   │ DXE             │ ramstage        │ board_init_r │ BL31             │
+// │ BDS   │ BDS             │ payload load    │ autoboot     │ EL3 exit         │
+// │ OS    │ ExitBootServices│ payload boot    │ start_kernel │                  │
+// └───────┴─────────────────┴─────────────────┴──────────────┴──────────────────┘
+//
+
+// represents a boot phase of the common phase model
+type Phase uint8
+
+const (
+	PhaseUnknown Phase = iota
+	PhaseSEC
+	PhasePEI
+	PhaseDXE
+	PhaseBDS
+	PhaseOS
+)
+
+var phaseDesc = map[Phase]string{
+	PhaseUnknown: "Unknown",
+	PhaseSEC:     "SEC",
+	PhasePEI:     "PEI",
+	PhaseDXE:     "DXE",
+	PhaseBDS:     "BDS",
+	PhaseOS:      "OS",
+}
+
+func (p Phase) String() string {
+	return phaseDesc[p]
+}
+
+// ParsePhase returns the phase with the given name.
+func ParsePhase(name string) (Phase, bool) {
+	for phase, desc := range phaseDesc {
+		if desc == name {
+			return phase, true
+		}
+	}
+
+	return PhaseUnknown, false
+}
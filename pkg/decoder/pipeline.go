@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Number of lines handed to a worker at once
+const batchSize = 4096
+
+// Pipeline decodes boot logs with a pool of workers, for archives too
+// large to decode line by line. Unlike Scanner it keeps no state
+// between calls, so a single Pipeline may decode several logs at the
+// same time. Records are delivered in line order.
+//
+//	reader ──► batch ──► worker ─┐
+//	                ├──► worker ─┼──► reorder ──► fn
+//	                └──► worker ─┘
+//
+// Decoders must be safe for concurrent use, which holds for decoders
+// keeping no state between lines.
+type Pipeline struct {
+	decoders []Decoder
+	workers  int
+}
+
+// NewPipeline returns a pipeline decoding with the given number of
+// workers, or one per CPU if workers is 0 or less.
+func NewPipeline(workers int, decoders ...Decoder) *Pipeline {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &Pipeline{decoders: decoders, workers: workers}
+}
+
+// represents a run of consecutive lines of the log
+type batch struct {
+	seq       int
+	firstLine int
+	lines     []string
+	decoded   []decodedLine
+}
+
+// Decode decodes every recognised line of r and calls fn in line order
+// with the record, or with the error of a line that failed to decode,
+// as Scanner.Scan does. fn is called from the calling goroutine.
+// Decoding stops when fn returns an error.
+func (p *Pipeline) Decode(r io.Reader, fn func(Record, error) error) error {
+	jobs := make(chan *batch, p.workers)
+	results := make(chan *batch, p.workers)
+	done := make(chan struct{})
+
+	var readErr error
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		defer close(jobs)
+		readErr = p.read(r, jobs, done)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				b.decoded = b.decoded[:0]
+				for _, line := range b.lines {
+					b.decoded = append(b.decoded, decodeLine(p.decoders, line))
+				}
+
+				select {
+				case results <- b:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	err := p.deliver(results, fn)
+	close(done)
+	for range results {
+		// wait for the workers to stop
+	}
+	<-readDone
+	if err != nil {
+		return err
+	}
+
+	return readErr
+}
+
+// read splits r into batches of lines
+func (p *Pipeline) read(r io.Reader, jobs chan<- *batch, done <-chan struct{}) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	b := &batch{firstLine: 1}
+	send := func() bool {
+		select {
+		case jobs <- b:
+		case <-done:
+			return false
+		}
+
+		b = &batch{
+			seq:       b.seq + 1,
+			firstLine: b.firstLine + len(b.lines),
+			lines:     make([]string, 0, batchSize),
+			decoded:   make([]decodedLine, 0, batchSize),
+		}
+		return true
+	}
+
+	for scanner.Scan() {
+		b.lines = append(b.lines, scanner.Text())
+		if len(b.lines) == batchSize && !send() {
+			return nil
+		}
+	}
+	if len(b.lines) > 0 {
+		send()
+	}
+
+	return scanner.Err()
+}
+
+// deliver calls fn with the records of the batches in sequence order,
+// converting clock time stamps to offsets since the first one and
+// following the blocks of the block decoders
+func (p *Pipeline) deliver(results <-chan *batch, fn func(Record, error) error) error {
+	var base time.Time
+	blocks := newBlockState(p.decoders)
+	pending := make(map[int]*batch)
+	next := 0
+
+	for b := range results {
+		pending[b.seq] = b
+
+		for b, ok := pending[next]; ok; b, ok = pending[next] {
+			delete(pending, next)
+			next++
+
+			for i, d := range b.decoded {
+				blocks.next(&d)
+				if !d.ok {
+					continue
+				}
+
+				lineNo := b.firstLine + i
+				d.record.LineNo = lineNo
+				if d.err != nil {
+					d.err = fmt.Errorf("line %d: %v", lineNo, d.err)
+				} else if d.hasStamp {
+					d.record.Timestamp = offsetSince(&base, d.stamp)
+					d.record.HasTimestamp = true
+				}
+
+				if err := fn(d.record, d.err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// seqDecoder decodes "seq <n>" lines, failing on multiples of 1000
+type seqDecoder struct{}
+
+func (seqDecoder) Name() string { return "seq" }
+
+func (seqDecoder) Match(line string) bool { return strings.HasPrefix(line, "seq ") }
+
+func (seqDecoder) Decode(line string) (decoder.Record, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(line, "seq "))
+	if err != nil || n%1000 == 0 {
+		return decoder.Record{}, fmt.Errorf("invalid sequence number %q", line)
+	}
+
+	return decoder.Record{Code: uint64(n)}, nil
+}
+
+// seqLog returns a log of n numbered lines, with a foreign line after
+// each tenth one
+func seqLog(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "seq %d\n", i)
+		if i%10 == 0 {
+			b.WriteString("foreign line\n")
+		}
+	}
+
+	return b.String()
+}
+
+// represents a record or error as delivered
+type delivered struct {
+	lineNo int
+	code   uint64
+	err    string
+}
+
+func collect(decode func(io.Reader, func(decoder.Record, error) error) error, log string) ([]delivered, error) {
+	var out []delivered
+	err := decode(strings.NewReader(log), func(record decoder.Record, err error) error {
+		d := delivered{lineNo: record.LineNo, code: record.Code}
+		if err != nil {
+			d.err = err.Error()
+		}
+		out = append(out, d)
+		return nil
+	})
+
+	return out, err
+}
+
+func TestPipelineOrder(t *testing.T) {
+	// several batches per worker, with a partial last batch
+	log := seqLog(20000)
+
+	scanned, err := collect(func(r io.Reader, fn func(decoder.Record, error) error) error {
+		return decoder.NewScanner(seqDecoder{}).Scan(r, fn)
+	}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 3, 8} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			got, err := collect(decoder.NewPipeline(workers, seqDecoder{}).Decode, log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(scanned) {
+				t.Fatalf("got %d records, want %d", len(got), len(scanned))
+			}
+			for i := range got {
+				if got[i] != scanned[i] {
+					t.Fatalf("record %d = %+v, want %+v", i, got[i], scanned[i])
+				}
+			}
+		})
+	}
+
+	// the records are numbered in line order, with the failing lines
+	for i, d := range scanned {
+		n := i + 1
+		if d.lineNo != n+(n-1)/10 {
+			t.Fatalf("record %d on line %d, want %d", n, d.lineNo, n+(n-1)/10)
+		}
+		if (d.err != "") != (n%1000 == 0) {
+			t.Fatalf("record %d error = %q", n, d.err)
+		}
+	}
+}
+
+func TestPipelineStop(t *testing.T) {
+	stop := errors.New("stop")
+	count := 0
+	err := decoder.NewPipeline(4, seqDecoder{}).Decode(strings.NewReader(seqLog(50000)), func(record decoder.Record, err error) error {
+		count++
+		if count == 5000 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 5000 {
+		t.Errorf("Decode() = %v after %d records, want %v after 5000", err, count, stop)
+	}
+}
+
+// benchmarkLog returns a console log mixing the formats of the linked
+// decoders with lines that no decoder recognises, as in real logs
+func benchmarkLog() []byte {
+	lines := []string{
+		"PROGRESS CODE: V03020003 I0",
+		"ERROR: C40000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E",
+		"Loading driver at 0x0007F5C0000 EntryPoint=0x0007F5C1234 PciBus.efi",
+		"InstallProtocolInterface: 5B1B31A1-9562-11D2-8E3F-00A0C969723B 7F1C2A40",
+		"POST CODE: 0xD800",
+		"[12:00:00.100] U-Boot SPL 2024.01 (Jan 01 2024 - 00:00:00 +0000)",
+		"NOTICE:  BL31: v2.10.0(release):v2.10.0",
+		"[INFO ]  BS: BS_DEV_INIT run times (exec / console): 12 / 3 ms",
+		"PciBus: Discovered PCI @ [00|1F|02]",
+		"[   3.5]\tSome timestamped driver message",
+	}
+
+	var b bytes.Buffer
+	for b.Len() < 4<<20 {
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.Bytes()
+}
+
+func BenchmarkScanner(b *testing.B) {
+	log := benchmarkLog()
+	scanner := decoder.NewDefaultScanner()
+
+	b.SetBytes(int64(len(log)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := scanner.Scan(bytes.NewReader(log), func(decoder.Record, error) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipeline(b *testing.B) {
+	log := benchmarkLog()
+	pipeline := decoder.NewDefaultPipeline(0)
+
+	b.SetBytes(int64(len(log)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := pipeline.Decode(bytes.NewReader(log), func(decoder.Record, error) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMatch measures the cost of each decoder's Match, which runs
+// on every line of a log until a decoder recognises it
+func BenchmarkMatch(b *testing.B) {
+	lines := strings.Split(strings.TrimSpace(string(benchmarkLog()[:4096])), "\n")
+
+	for _, d := range decoder.Decoders() {
+		b.Run(d.Name(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, line := range lines {
+					d.Match(line)
+				}
+			}
+		})
+	}
+}
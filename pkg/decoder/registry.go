@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Decoder priorities. When several decoders match a line, the scanners
+// dispatch it to the decoder with the highest priority, so a format
+// with a distinctive signature wins over a free-form one sharing its
+// prefix, e.g. the EDK2 "ERROR: C40000002:V010E0005" lines over the
+// TF-A "ERROR:" log level.
+const (
+	// free-form formats, e.g. a log level followed by any message
+	PriorityGeneric = -10
+	PriorityDefault = 0
+	// formats with a signature, e.g. a status code value
+	PrioritySpecific = 10
+)
+
+// represents a registered decoder
+type registration struct {
+	decoder  Decoder
+	priority int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// Register makes a decoder available to the scanners created with
+// NewDefaultScanner, with the default priority. Decoder packages call
+// it from their init function, so importing a package, even for its
+// side effects only, plugs its line format in:
+//
+//	import _ "example.com/vendor/bpd-decoder"
+//
+// Register panics if the decoder is nil or its name is already taken.
+func Register(d Decoder) {
+	RegisterPriority(d, PriorityDefault)
+}
+
+// RegisterPriority registers a decoder with the given priority. The
+// order of the init functions, which follows the import paths, does
+// not matter: decoders of the same priority are ordered by name.
+func RegisterPriority(d Decoder, priority int) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if d == nil {
+		panic("decoder: Register decoder is nil")
+	}
+	for _, registered := range registry {
+		if registered.decoder.Name() == d.Name() {
+			panic(fmt.Sprintf("decoder: Register called twice for decoder %q", d.Name()))
+		}
+	}
+
+	registry = append(registry, registration{decoder: d, priority: priority})
+	sort.SliceStable(registry, func(i, j int) bool {
+		if registry[i].priority != registry[j].priority {
+			return registry[i].priority > registry[j].priority
+		}
+		return registry[i].decoder.Name() < registry[j].decoder.Name()
+	})
+}
+
+// Decoders returns the registered decoders in dispatch order: by
+// decreasing priority, then by name.
+func Decoders() []Decoder {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	decoders := make([]Decoder, 0, len(registry))
+	for _, registered := range registry {
+		decoders = append(decoders, registered.decoder)
+	}
+
+	return decoders
+}
+
+// Lookup returns the registered decoder with the given name.
+func Lookup(name string) (Decoder, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.decoder.Name() == name {
+			return registered.decoder, true
+		}
+	}
+
+	return nil, false
+}
+
+// NewDefaultScanner returns a scanner dispatching to all registered
+// decoders.
+func NewDefaultScanner() *Scanner {
+	return NewScanner(Decoders()...)
+}
+
+// NewDefaultPipeline returns a pipeline dispatching to all registered
+// decoders.
+func NewDefaultPipeline(workers int) *Pipeline {
+	return NewPipeline(workers, Decoders()...)
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	_ "github.com/nhivp/boot-progress-decoder/pkg/amd"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/coreboot"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/edk2"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/fsp"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/tfa"
+	_ "github.com/nhivp/boot-progress-decoder/pkg/uboot"
+)
+
+// prefixDecoder matches the lines starting with a prefix
+type prefixDecoder struct {
+	name   string
+	prefix string
+}
+
+func (d prefixDecoder) Name() string { return d.name }
+
+func (d prefixDecoder) Match(line string) bool { return strings.HasPrefix(line, d.prefix) }
+
+func (d prefixDecoder) Decode(line string) (decoder.Record, error) {
+	return decoder.Record{Operation: d.name}, nil
+}
+
+// the registry is global, so the test decoders are registered once
+// when the tests run several times
+var registerOnce sync.Once
+
+func TestRegisterPriority(t *testing.T) {
+	// registered in the reverse of the dispatch order
+	registerOnce.Do(func() {
+		decoder.RegisterPriority(prefixDecoder{"test-z-generic", "test:"}, decoder.PriorityGeneric)
+		decoder.Register(prefixDecoder{"test-b-default", "test:"})
+		decoder.Register(prefixDecoder{"test-a-default", "test:"})
+		decoder.RegisterPriority(prefixDecoder{"test-y-specific", "test: specific"}, decoder.PrioritySpecific)
+	})
+
+	var names []string
+	for _, d := range decoder.Decoders() {
+		if strings.HasPrefix(d.Name(), "test-") {
+			names = append(names, d.Name())
+		}
+	}
+	want := "test-y-specific test-a-default test-b-default test-z-generic"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("Decoders() = %s, want %s", got, want)
+	}
+
+	scanner := decoder.NewDefaultScanner()
+	for line, name := range map[string]string{
+		"test: specific line": "test-y-specific",
+		"test: other line":    "test-a-default",
+	} {
+		record, ok, err := scanner.DecodeLine(line)
+		if !ok || err != nil || record.Decoder != name {
+			t.Errorf("DecodeLine(%q) = %q, %v, %v, want %q", line, record.Decoder, ok, err, name)
+		}
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register did not panic for a taken name")
+		}
+	}()
+	decoder.Register(prefixDecoder{"edk2", "test:"})
+}
+
+// TestDispatchOrder checks the lines that several of the linked
+// decoders match go to the decoder of their format.
+func TestDispatchOrder(t *testing.T) {
+	tests := []struct {
+		line    string
+		decoder string
+	}{
+		{"ERROR: C40000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E", "edk2"},
+		{"ERROR:   BL2: Failed to load image id 3 (-2)", "tfa"},
+		{"POST CODE: 0xD800", "fsp"},
+		{"POST CODE: 0xEA00", "amd"},
+		{"PROGRESS CODE: V03020003 I0", "edk2"},
+	}
+
+	scanner := decoder.NewDefaultScanner()
+	for _, tt := range tests {
+		record, ok, err := scanner.DecodeLine(tt.line)
+		if !ok || err != nil || record.Decoder != tt.decoder {
+			t.Errorf("DecodeLine(%q) = %q, %v, %v, want %q", tt.line, record.Decoder, ok, err, tt.decoder)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Maximum length of a log line
+const maxLineSize = 1024 * 1024
+
+// Scanner reads a boot log and dispatches each line to the first
+// decoder that recognises its format, in the order the decoders are
+// given; NewDefaultScanner orders them by priority. Time stamp
+// prefixes are split off before dispatching and recorded as offsets
+// since the first time stamp of the log.
+type Scanner struct {
+	decoders []Decoder
+	base     time.Time
+	blocks   *blockState
+}
+
+func NewScanner(decoders ...Decoder) *Scanner {
+	return &Scanner{decoders: decoders, blocks: newBlockState(decoders)}
+}
+
+// DecodeLine decodes a single line. It returns false if no decoder
+// recognises the line. The lines of a log must be passed in order, as
+// some lines are only decoded inside a block, see BlockDecoder.
+func (s *Scanner) DecodeLine(line string) (Record, bool, error) {
+	d := decodeLine(s.decoders, line)
+	s.blocks.next(&d)
+	if d.ok && d.err == nil && d.hasStamp {
+		d.record.Timestamp = s.offset(d.stamp)
+		d.record.HasTimestamp = true
+	}
+
+	return d.record, d.ok, d.err
+}
+
+// represents a line dispatched to a decoder, with its time stamp
+// prefix as parsed and its text without the prefix
+type decodedLine struct {
+	text     string
+	record   Record
+	stamp    Timestamp
+	hasStamp bool
+	ok       bool
+	err      error
+}
+
+// decodeLine dispatches a line to the first decoder that recognises it.
+// It keeps no state, so the time stamp must be converted to an offset
+// by the caller.
+func decodeLine(decoders []Decoder, line string) decodedLine {
+	line = strings.TrimRight(line, "\r\n")
+	stamp, text, hasStamp := ParseTimestamp(strings.TrimSpace(line))
+	text = strings.TrimSpace(text)
+
+	for _, d := range decoders {
+		if !d.Match(text) {
+			continue
+		}
+
+		record, err := d.Decode(text)
+		if err != nil {
+			return decodedLine{
+				text:   text,
+				record: Record{Decoder: d.Name(), Line: line},
+				ok:     true,
+				err:    fmt.Errorf("%s: %v", d.Name(), err),
+			}
+		}
+		record.Decoder = d.Name()
+		record.Line = line
+
+		return decodedLine{text: text, record: record, stamp: stamp, hasStamp: hasStamp, ok: true}
+	}
+
+	return decodedLine{text: text}
+}
+
+func (s *Scanner) offset(stamp Timestamp) time.Duration {
+	return offsetSince(&s.base, stamp)
+}
+
+// offsetSince returns the offset of a time stamp since the start of
+// the log. Clock time stamps count from the first one, which is saved
+// in base.
+func offsetSince(base *time.Time, stamp Timestamp) time.Duration {
+	if !stamp.IsClock {
+		return stamp.Offset
+	}
+
+	if base.IsZero() {
+		*base = stamp.Clock
+	}
+
+	return stamp.Clock.Sub(*base)
+}
+
+// Scan decodes every recognised line of r in a single pass and calls
+// fn with the record, or with the error of a line that failed to
+// decode. Scanning stops when fn returns an error.
+func (s *Scanner) Scan(r io.Reader, fn func(Record, error) error) error {
+	s.base = time.Time{}
+	s.blocks.reset()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		record, ok, err := s.DecodeLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		record.LineNo = lineNo
+		if err != nil {
+			err = fmt.Errorf("line %d: %v", lineNo, err)
+		}
+
+		if err := fn(record, err); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package decoder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capture tools prefix each log line with a time stamp. The following
+// prefixes are recognised:
+//
+//	[   12.345678] ...              seconds since start (kernel style)
+//	[12:34:56.789] ...              time of day (minicom, picocom)
+//	2024-05-01 12:34:56.789 ...     date and time
+//	2024-05-01T12:34:56.789Z ...    RFC 3339 (ts, journald)
+//
+
+var clockRegex = regexp.MustCompile(`^\[?((?:\d{4}-\d{2}-\d{2}[T ])?\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?)\]?\s`)
+
+var clockLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"15:04:05.999999999",
+}
+
+// represents a time stamp parsed from a log line prefix
+type Timestamp struct {
+	// Offset since the start of the capture, for relative time stamps
+	Offset time.Duration
+	// Clock time, for absolute time stamps
+	Clock time.Time
+	// IsClock tells whether the time stamp is absolute
+	IsClock bool
+}
+
+// ParseTimestamp splits a leading time stamp off a log line. It returns
+// the time stamp, the rest of the line and whether a time stamp was
+// found.
+func ParseTimestamp(line string) (Timestamp, string, bool) {
+	// every prefix starts with a bracket or a digit
+	if line == "" || line[0] != '[' && (line[0] < '0' || line[0] > '9') {
+		return Timestamp{}, line, false
+	}
+
+	if stamp, rest, ok := parseSeconds(line); ok {
+		return stamp, rest, true
+	}
+
+	if strings.IndexByte(line, ':') < 0 {
+		return Timestamp{}, line, false
+	}
+
+	if m := clockRegex.FindStringSubmatch(line); m != nil {
+		value := strings.Replace(m[1], ",", ".", 1)
+		for _, layout := range clockLayouts {
+			if clock, err := time.Parse(layout, value); err == nil {
+				return Timestamp{Clock: clock, IsClock: true}, line[len(m[0]):], true
+			}
+		}
+	}
+
+	return Timestamp{}, line, false
+}
+
+// parseSeconds parses the "[   12.345678] " prefix by hand, as it is
+// the most common one and checked for every line
+func parseSeconds(line string) (Timestamp, string, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return Timestamp{}, line, false
+	}
+
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return Timestamp{}, line, false
+	}
+
+	value := strings.TrimSpace(line[1:end])
+	digits, fraction, hasFraction := strings.Cut(value, ".")
+	if !isDigits(digits) || hasFraction && !isDigits(fraction) {
+		return Timestamp{}, line, false
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Timestamp{}, line, false
+	}
+
+	rest := line[end+1:]
+	if rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		rest = rest[1:]
+	}
+
+	return Timestamp{Offset: time.Duration(seconds * float64(time.Second))}, rest, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package dp
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Below is the part of the EDK2 shell "dp" command output that holds
+// the per-module timing:
+//
+// ==[ Drivers by Handle ]========
+// Index:  Handle  Driver Name                    Description  Duration (us)
+//     1:  [6A]    PcdDxe                         NULL                  512
+//
+// ==[ PEIM Statistics ]========
+// Index:  Pointer Value                         PEIM Name      Duration (us)
+//     1:  9B3ADA4F-AE56-4C24-8DEA-F03B7558AE50  PcdPeim                  36
+//
+// A row starts with its index. The driver or PEIM name is the first
+// field that is not a handle, pointer or GUID, and the duration is the
+// last number of the row. The description column may hold spaces.
+//
+// Reference: https://github.com/tianocore/edk2/tree/master/ShellPkg/DynamicCommand/DpDynamicCommand
+//
+
+// represents the timing of a module reported by dp
+type Module struct {
+	Section  string
+	Name     string
+	GUID     edk2.GUID
+	HasGUID  bool
+	Duration time.Duration
+}
+
+var (
+	sectionRegex = regexp.MustCompile(`^\s*==\[\s*(.+?)\s*\]=*`)
+	rowRegex     = regexp.MustCompile(`^\s*\d+:\s+(.+)$`)
+)
+
+// Sections holding per-module rows
+var moduleSections = map[string]bool{
+	"Drivers by Handle": true,
+	"PEIM Statistics":   true,
+}
+
+// Parse reads the module timing rows of dp output.
+func Parse(r io.Reader) ([]Module, error) {
+	var modules []Module
+
+	section := ""
+	unit := time.Microsecond
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := sectionRegex.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			unit = time.Microsecond
+			continue
+		}
+		if !moduleSections[section] {
+			continue
+		}
+
+		// the column header gives the duration unit
+		if strings.Contains(line, "(ms)") {
+			unit = time.Millisecond
+		}
+
+		m := rowRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		if module, ok := parseRow(m[1], unit); ok {
+			module.Section = section
+			modules = append(modules, module)
+		}
+	}
+
+	return modules, scanner.Err()
+}
+
+func parseRow(row string, unit time.Duration) (Module, bool) {
+	fields := strings.Fields(row)
+	if len(fields) < 2 {
+		return Module{}, false
+	}
+
+	duration, err := strconv.ParseUint(strings.ReplaceAll(fields[len(fields)-1], ",", ""), 10, 64)
+	if err != nil {
+		return Module{}, false
+	}
+
+	module := Module{Duration: time.Duration(duration) * unit}
+	for _, field := range fields[:len(fields)-1] {
+		switch {
+		case strings.HasPrefix(field, "[") || strings.HasSuffix(field, "]"):
+			// handle
+		case edk2.IsValidUUID(field):
+			if !module.HasGUID {
+				module.GUID, module.HasGUID = edk2.MustParseGUID(field), true
+			}
+		case strings.HasPrefix(field, "0x"):
+			// pointer value
+		case module.Name == "":
+			module.Name = field
+		}
+	}
+
+	if module.Name == "" && !module.HasGUID {
+		return Module{}, false
+	}
+
+	return module, true
+}
+
+// Load lines of EDK2 DEBUG_LOAD output naming the module loaded by the
+// preceding "Loading driver <GUID>" or "Loading PEIM <GUID>" line
+var (
+	loadingGUIDRegex = regexp.MustCompile(`Loading (?:driver|PEIM) ([0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12})`)
+	loadingNameRegex = regexp.MustCompile(`Loading (?:driver|PEIM) at 0x[0-9A-Fa-f]+ EntryPoint=0x[0-9A-Fa-f]+ (\S+?)(?:\.efi)?\s*$`)
+)
+
+// ReadModuleNames maps module GUIDs to names using the DEBUG_LOAD lines
+// of an EDK2 boot log:
+//
+// Loading driver 9B680FCE-AD6B-4F3A-B60B-F59899003443
+// Loading driver at 0x0007EA6D000 EntryPoint=0x0007EA6E5B8 DevicePathDxe.efi
+func ReadModuleNames(r io.Reader) (map[edk2.GUID]string, error) {
+	names := make(map[edk2.GUID]string)
+
+	var pending *edk2.GUID
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := loadingGUIDRegex.FindStringSubmatch(line); m != nil {
+			guid := edk2.MustParseGUID(m[1])
+			pending = &guid
+			continue
+		}
+
+		if m := loadingNameRegex.FindStringSubmatch(line); m != nil && pending != nil {
+			names[*pending] = m[1]
+			pending = nil
+		}
+	}
+
+	return names, scanner.Err()
+}
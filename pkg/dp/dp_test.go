@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package dp_test
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/dp"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// testdata/dp.txt is the output of "dp -v" in the EDK2 shell, and
+// testdata/boot.log the DEBUG_LOAD lines and error codes of the same
+// boot
+
+var (
+	pcdPeimGUID       = edk2.MustParseGUID("9B3ADA4F-AE56-4C24-8DEA-F03B7558AE50")
+	dxeIplGUID        = edk2.MustParseGUID("86D70125-BAA3-4296-A62F-602BEBBB9081")
+	pcdDxeGUID        = edk2.MustParseGUID("80CF7257-87AB-47F9-A3FE-D50B76D89541")
+	devicePathDxeGUID = edk2.MustParseGUID("9B680FCE-AD6B-4F3A-B60B-F59899003443")
+	pciBusDxeGUID     = edk2.MustParseGUID("93B80004-9FB3-11D4-9A3A-0090273FC14D")
+	unknownGUID       = edk2.MustParseGUID("55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E")
+)
+
+func parseFile(t *testing.T, path string) []dp.Module {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	modules, err := dp.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return modules
+}
+
+func readLog(t *testing.T, path string) ([]decoder.Record, map[edk2.GUID]string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []decoder.Record
+	err = decoder.NewScanner(edk2.Decoder{}).Scan(strings.NewReader(string(data)), func(record decoder.Record, err error) error {
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := dp.ReadModuleNames(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return records, names
+}
+
+func TestParse(t *testing.T) {
+	// the General and Top 50 sections are not per module
+	want := []dp.Module{
+		{Section: "Drivers by Handle", Name: "PcdDxe", Duration: 512 * time.Microsecond},
+		{Section: "Drivers by Handle", Name: "DevicePathDxe", Duration: 1024 * time.Microsecond},
+		{Section: "Drivers by Handle", Name: "PciBusDxe", Duration: 183415 * time.Microsecond},
+		{Section: "Drivers by Handle", Name: "SataController", Duration: 238 * time.Microsecond},
+		{Section: "PEIM Statistics", Name: "PcdPeim", GUID: pcdPeimGUID, HasGUID: true, Duration: 36 * time.Microsecond},
+		{Section: "PEIM Statistics", Name: "DxeIpl", GUID: dxeIplGUID, HasGUID: true, Duration: 1503 * time.Microsecond},
+	}
+
+	modules := parseFile(t, "testdata/dp.txt")
+	if len(modules) != len(want) {
+		t.Fatalf("got %d modules, want %d: %+v", len(modules), len(want), modules)
+	}
+	for i, got := range modules {
+		if got != want[i] {
+			t.Errorf("module %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	// older dp versions print milliseconds, the unit of each section
+	// is given by its column header
+	const output = `==[ Drivers by Handle ]========
+Index:  Handle  Driver Name                    Description  Time(ms)
+    1:  [ 6A]   PcdDxe                         NULL                3
+==[ PEIM Statistics ]========
+Index:  Pointer Value                         PEIM Name      Duration (us)
+    1:  9B3ADA4F-AE56-4C24-8DEA-F03B7558AE50  PcdPeim                  36
+    2:  0x7FE1C0A8                            PlatformPei              x
+`
+
+	modules, err := dp.Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("got %d modules, want 2: %+v", len(modules), modules)
+	}
+	if modules[0].Duration != 3*time.Millisecond || modules[1].Duration != 36*time.Microsecond {
+		t.Errorf("durations = %v, %v, want 3ms, 36µs", modules[0].Duration, modules[1].Duration)
+	}
+}
+
+func TestReadModuleNames(t *testing.T) {
+	_, names := readLog(t, "testdata/boot.log")
+
+	want := map[edk2.GUID]string{
+		pcdPeimGUID:       "PcdPeim",
+		pcdDxeGUID:        "PcdDxe",
+		devicePathDxeGUID: "DevicePathDxe",
+		pciBusDxeGUID:     "PciBusDxe",
+	}
+	if len(names) != len(want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	for guid, name := range want {
+		if names[guid] != name {
+			t.Errorf("name of %s = %q, want %q", guid, names[guid], name)
+		}
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	modules := parseFile(t, "testdata/dp.txt")
+	records, names := readLog(t, "testdata/boot.log")
+
+	type report struct {
+		name     string
+		guid     string
+		duration time.Duration
+		lines    []int
+	}
+	want := []report{
+		// drivers listed by name get their GUID from the boot log
+		{"PcdDxe", pcdDxeGUID.String(), 512 * time.Microsecond, nil},
+		{"DevicePathDxe", devicePathDxeGUID.String(), 1024 * time.Microsecond, nil},
+		{"PciBusDxe", pciBusDxeGUID.String(), 183415 * time.Microsecond, []int{11, 12}},
+		{"SataController", "-", 238 * time.Microsecond, nil},
+		{"PcdPeim", pcdPeimGUID.String(), 36 * time.Microsecond, []int{4}},
+		{"DxeIpl", dxeIplGUID.String(), 1503 * time.Microsecond, nil},
+		// reported a code but is missing from the dp output
+		{"", unknownGUID.String(), -1, []int{13}},
+	}
+
+	reports := dp.Correlate(modules, records, names)
+	if len(reports) != len(want) {
+		t.Fatalf("got %d reports, want %d", len(reports), len(want))
+	}
+	for i, r := range reports {
+		got := report{name: r.Name, guid: "-", duration: -1}
+		if r.HasGUID {
+			got.guid = r.GUID.String()
+		}
+		if r.HasDuration {
+			got.duration = r.Duration
+		}
+		for _, code := range r.Codes {
+			got.lines = append(got.lines, code.LineNo)
+		}
+
+		w := want[i]
+		if got.name != w.name || got.guid != w.guid || got.duration != w.duration ||
+			!slices.Equal(got.lines, w.lines) {
+			t.Errorf("report %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestCorrelateDuplicate(t *testing.T) {
+	// a module listed by several sections keeps its longest duration
+	modules := []dp.Module{
+		{Section: "PEIM Statistics", Name: "PcdPeim", GUID: pcdPeimGUID, HasGUID: true, Duration: 36 * time.Microsecond},
+		{Section: "Drivers by Handle", Name: "PcdPeim", Duration: 40 * time.Microsecond},
+		{Section: "PEIM Statistics", Name: "Other", GUID: pcdPeimGUID, HasGUID: true, Duration: 20 * time.Microsecond},
+	}
+
+	reports := dp.Correlate(modules, nil, nil)
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1: %+v", len(reports), reports)
+	}
+	if reports[0].Name != "PcdPeim" || reports[0].Duration != 40*time.Microsecond {
+		t.Errorf("report = %s %v, want PcdPeim 40µs", reports[0].Name, reports[0].Duration)
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package dp
+
+import (
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// represents the timing of a module joined with the status codes it
+// reported
+type ModuleReport struct {
+	Name        string
+	GUID        edk2.GUID
+	HasGUID     bool
+	Duration    time.Duration
+	HasDuration bool
+	Codes       []decoder.Record
+}
+
+// Correlate joins the dp module timing with the records whose caller
+// ID names a module. Modules are matched by GUID, or by name through
+// the GUID to name mappings of the boot log when dp only lists names.
+// Modules that reported status codes but are missing from the dp
+// output are appended without a duration.
+func Correlate(modules []Module, records []decoder.Record, names map[edk2.GUID]string) []ModuleReport {
+	var reports []ModuleReport
+	byGUID := make(map[edk2.GUID]int)
+	byName := make(map[string]int)
+
+	guidOf := make(map[string]edk2.GUID, len(names))
+	for guid, name := range names {
+		guidOf[name] = guid
+	}
+
+	add := func(report ModuleReport) int {
+		if report.HasGUID && report.Name == "" {
+			report.Name = names[report.GUID]
+		}
+		if !report.HasGUID {
+			report.GUID, report.HasGUID = guidOf[report.Name]
+		}
+
+		reports = append(reports, report)
+		index := len(reports) - 1
+		if report.HasGUID {
+			byGUID[report.GUID] = index
+		}
+		if report.Name != "" {
+			byName[report.Name] = index
+		}
+		return index
+	}
+
+	for _, module := range modules {
+		index, ok := byGUID[module.GUID]
+		if !ok || !module.HasGUID {
+			index, ok = byName[module.Name]
+		}
+		if ok {
+			// a module may be listed by several sections
+			if module.Duration > reports[index].Duration {
+				reports[index].Duration = module.Duration
+			}
+			continue
+		}
+
+		add(ModuleReport{
+			Name:        module.Name,
+			GUID:        module.GUID,
+			HasGUID:     module.HasGUID,
+			Duration:    module.Duration,
+			HasDuration: true,
+		})
+	}
+
+	for _, record := range records {
+		guid, err := edk2.ParseGUID(record.Module)
+		if err != nil {
+			continue
+		}
+
+		index, ok := byGUID[guid]
+		if !ok {
+			index, ok = byName[names[guid]]
+		}
+		if !ok {
+			index = add(ModuleReport{GUID: guid, HasGUID: true})
+		}
+		reports[index].Codes = append(reports[index].Codes, record)
+	}
+
+	return reports
+}
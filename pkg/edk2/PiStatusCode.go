@@ -49,7 +49,7 @@ var statusTypeDesc = map[uint8]string{
 var errorSeverityDesc = map[uint8]string{
 	0x40: "Minor Error",
 	0x80: "Major Error",
-	0x90: "Uncovered Error",
+	0x90: "Unrecovered Error",
 	0xA0: "Uncontained Error",
 }
 
@@ -73,13 +73,17 @@ var errorSeverityDesc = map[uint8]string{
 //        ┌─────────────────────────┴────────┐  │ 0x00: Unspecified
 //        │                                  │  │ 0x01: PCI
 //        │   │For Computing:                │  │ 0x02: USB
-//        │   │ 0x00: Unspecified            │  │ 0x06: LPC
-//        │   │ 0x01: Host Processor         │  │ 0x07: SCSI
-//        │   │ 0x02: Firmware Processor     │  │ 0x08: ATAPI
-//        │   │ 0x03: I/O Processor          │  │ 0x0B: SMBUS
-//        │   │ 0x04: Cache                  │  │ 0x0C: I2C
-//        │   │ 0x05: Memory                 │  │
-//        │   │ 0x06: Chipset                │  │
+//        │   │ 0x00: Unspecified            │  │ 0x03: IBA
+//        │   │ 0x01: Host Processor         │  │ 0x04: AGP
+//        │   │ 0x02: Firmware Processor     │  │ 0x05: PC Card
+//        │   │ 0x03: I/O Processor          │  │ 0x06: LPC
+//        │   │ 0x04: Cache                  │  │ 0x07: SCSI
+//        │   │ 0x05: Memory                 │  │ 0x08: ATAPI
+//        │   │ 0x06: Chipset                │  │ 0x09: FC
+//        │   │                              │  │ 0x0A: IP Network
+//        │   │                              │  │ 0x0B: SMBUS
+//        │   │                              │  │ 0x0C: I2C
+//        │   │                              │  │
 //        │   │                              │  │For Software:
 //        └───┤                              └──┤ 0x00: Unspecified
 //            │For Peripheral:                  │ 0x01: SEC
@@ -91,12 +95,17 @@ var errorSeverityDesc = map[uint8]string{
 //            │ 0x05: Serial Port               │ 0x07: SMM Driver
 //            │ 0x06: Parallel Port             │ 0x08: EFI Application
 //            │ 0x07: Fixed Media               │ 0x09: OS Loader
-//            │ 0x08: Removable Media           │ 0x0C: EBC Exception
-//            │ 0x09: Audio Input               │ 0x0D: X86 Exception
-//            │ 0x0A: Audio Output              │ 0x0F: PEI Service
-//            │ 0x0B: LCD Device                │ 0x10: UEFI Boot Service
-//            │ 0x0C: Network                   │ 0x11: UEFI Runtime Service
+//            │ 0x08: Removable Media           │ 0x0A: Runtime
+//            │ 0x09: Audio Input               │ 0x0B: Afterlife
+//            │ 0x0A: Audio Output              │ 0x0C: EBC Exception
+//            │ 0x0B: LCD Device                │ 0x0D: X86 Exception
+//            │ 0x0C: Network                   │ 0x0E: IPF Exception
+//            │ 0x0D: Docking                   │ 0x0F: PEI Service
+//            │ 0x0E: TPM                       │ 0x10: UEFI Boot Service
+//                                              │ 0x11: UEFI Runtime Service
 //                                              │ 0x12: DXE Service
+//                                              │ 0x13: X64 Exception
+//                                              │ 0x14: ARM Exception
 //
 // Reference: https://github.com/tianocore/edk2/blob/master/MdePkg/Include/Pi/PiStatusCode.h
 //
@@ -158,9 +167,14 @@ var subclassIOBusCodeDesc = map[uint8]string{
 	0x00: "Unspecified",
 	0x01: "PCI",
 	0x02: "USB",
+	0x03: "IBA",
+	0x04: "AGP",
+	0x05: "PC Card",
 	0x06: "LPC",
 	0x07: "SCSI",
 	0x08: "ATAPI",
+	0x09: "FC",
+	0x0A: "IP Network",
 	0x0B: "SMBUS",
 	0x0C: "I2C",
 }
@@ -177,8 +191,11 @@ var subclassSoftwareCodeDesc = map[uint8]string{
 	0x07: "SMM Driver",
 	0x08: "EFI Application",
 	0x09: "OS Loader",
+	0x0A: "Runtime",
+	0x0B: "Afterlife",
 	0x0C: "EBC Exception",
 	0x0D: "X86 Exception",
+	0x0E: "IPF Exception",
 	0x0F: "PEI Service",
 	0x10: "UEFI Boot Service",
 	0x11: "UEFI Runtime Service",
@@ -265,7 +282,7 @@ var cUHPErrorCodeDesc = map[uint16]string{
 var cUFPErrorCodeDesc = map[uint16]string{
 	0x0000: "Hard Fail",
 	0x0001: "Soft Fail",
-	0x0002: "Common Error",
+	0x0002: "Communication Error",
 }
 
 var cUCacheErrorCodeDesc = map[uint16]string{
@@ -296,6 +313,24 @@ var cUChipsetErrorCodeDesc = map[uint16]string{
 	0x0003: "Intruder Detect",
 }
 
+// Subclass specific operation mappings of the Computing class. The
+// Firmware Processor progress codes and the I/O Processor codes are not
+// defined.
+var cUProgressCodeDesc = map[uint8]map[uint16]string{
+	0x01: cUHPProgressCodeDesc,
+	0x04: cUCacheProgressCodeDesc,
+	0x05: cUMemoryProgressCodeDesc,
+	0x06: cUChipsetProgressCodeDesc,
+}
+
+var cUErrorCodeDesc = map[uint8]map[uint16]string{
+	0x01: cUHPErrorCodeDesc,
+	0x02: cUFPErrorCodeDesc,
+	0x04: cUCacheErrorCodeDesc,
+	0x05: cUMemoryErrorCodeDesc,
+	0x06: cUChipsetErrorCodeDesc,
+}
+
 var commonPProgressCodeDesc = map[uint16]string{
 	0x0000: "Init",
 	0x0001: "Reset",
@@ -343,6 +378,22 @@ var pMouseErrorCodeDesc = map[uint16]string{
 	0x0000: "Locked",
 }
 
+// Subclass specific operation mappings of the Peripheral class. Only
+// the Keyboard, Mouse and Serial Port subclasses define operations, the
+// Local and Remote Console, Parallel Port, Fixed and Removable Media,
+// Audio, LCD, Network, Docking and TPM subclasses only use the common
+// ones.
+var pProgressCodeDesc = map[uint8]map[uint16]string{
+	0x01: pKeyBoardProgressCodeDesc,
+	0x02: pMouseProgressCodeDesc,
+	0x05: pSerialPortProgressCodeDesc,
+}
+
+var pErrorCodeDesc = map[uint8]map[uint16]string{
+	0x01: pKeyBoardErrorCodeDesc,
+	0x02: pMouseErrorCodeDesc,
+}
+
 var commonIOBProgressCodeDesc = map[uint16]string{
 	0x0000: "Init",
 	0x0001: "Reset",
@@ -389,6 +440,20 @@ var iOBAtaErrorCodeDesc = map[uint16]string{
 	0x0001: "ATA Bus SMART Disabled",
 }
 
+// Subclass specific operation mappings of the I/O Bus class. Only the
+// PCI and ATA/ATAPI subclasses define operations, the USB, IBA, AGP, PC
+// Card, LPC, SCSI, FC, IP Network, SMBUS and I2C subclasses only use
+// the common ones.
+var iOBProgressCodeDesc = map[uint8]map[uint16]string{
+	0x01: iOBPciProgressCodeDesc,
+	0x08: iOBAtaProgressCodeDesc,
+}
+
+var iOBErrorCodeDesc = map[uint8]map[uint16]string{
+	0x01: iOBPciErrorCodeDesc,
+	0x08: iOBAtaErrorCodeDesc,
+}
+
 var commonSWProgressCodeDesc = map[uint16]string{
 	0x0000: "Init",
 	0x0001: "Load",
@@ -443,12 +508,26 @@ var swDxeBsProgressCodeDesc = map[uint16]string{
 	0x0009: "DXE BS CSM Init",
 }
 
-var swDxeRtProgressCodeDesc = map[uint16]string{
+var swDxeRtDriverProgressCodeDesc = map[uint16]string{
+	0x0000: "S0",
+	0x0001: "S1",
+	0x0002: "S2",
+	0x0003: "S3",
+	0x0004: "S4",
+	0x0005: "S5",
+}
+
+var swRtProgressCodeDesc = map[uint16]string{
 	0x0000: "EFI RT Entry Point",
 	0x0001: "EFI RT Handoff To Next",
 	0x0002: "EFI RT Return To Last",
 }
 
+var swAlProgressCodeDesc = map[uint16]string{
+	0x0000: "EFI AL Entry Point",
+	0x0001: "EFI AL Return To Last",
+}
+
 var swPeiServicesProgressCodeDesc = map[uint16]string{
 	0x0000: "PEI Service Install PPI",
 	0x0001: "PEI Service Reinstall PPI",
@@ -646,18 +725,18 @@ var swIA32ErrorCodeDesc = map[uint16]string{
 }
 
 var swIPFErrorCodeDesc = map[uint16]string{
-	0x0000: "ALT DTLB",
-	0x0001: "DNESTED TLB",
-	0x0002: "Breakpoint",
-	0x0003: "External Interrupt",
-	0x0004: "Gen Except",
-	0x0005: "NAT Consumption",
-	0x0006: "Debug Except",
-	0x0007: "Unaligned Access",
-	0x0008: "FP Fault",
-	0x0009: "FP Trap",
-	0x000A: "Taken Branch",
-	0x000B: "Single Step",
+	0x0004: "ALT DTLB",
+	0x0005: "DNESTED TLB",
+	0x000B: "Breakpoint",
+	0x000C: "External Interrupt",
+	0x0018: "Gen Except",
+	0x001A: "NAT Consumption",
+	0x001D: "Debug Except",
+	0x001E: "Unaligned Access",
+	0x0020: "FP Fault",
+	0x0021: "FP Trap",
+	0x0023: "Taken Branch",
+	0x0024: "Single Step",
 }
 
 var swPeiServiceErrorCodeDesc = map[uint16]string{
@@ -665,20 +744,6 @@ var swPeiServiceErrorCodeDesc = map[uint16]string{
 	0x0001: "Memory Installed Twice",
 }
 
-var swDxeServiceErrorCodeDesc = map[uint16]string{
-	0x0005: "Begin Connecting Drivers",
-	0x0006: "Verifying Password",
-}
-
-var swDxeRtDriverProgressCodeDesc = map[uint16]string{
-	0x0000: "S0",
-	0x0001: "S1",
-	0x0002: "S2",
-	0x0003: "S3",
-	0x0004: "S4",
-	0x0005: "S5",
-}
-
 var swX64ExceptionErrorCodeDesc = map[uint16]string{
 	0x0000: "Divide Error",
 	0x0001: "Debug",
@@ -709,3 +774,42 @@ var swArmExceptionErrorCodeDesc = map[uint16]string{
 	0x0006: "IRQ",
 	0x0007: "FIQ",
 }
+
+// Subclass specific operation mappings of the Software class. The SEC,
+// SMM Driver, EFI Application and OS Loader subclasses define no
+// progress codes, and only the PEI Core, PEI Module, DXE Core, DXE Boot
+// Driver and PEI Service subclasses define error codes; the other
+// subclasses only use the common ones.
+var swProgressCodeDesc = map[uint8]map[uint16]string{
+	0x01: swSecProgressCodeDesc,
+	0x02: swPeiCoreProgressCodeDesc,
+	0x03: swPeiProgressCodeDesc,
+	0x04: swDxeCoreProgressCodeDesc,
+	0x05: swDxeBsProgressCodeDesc,
+	0x06: swDxeRtDriverProgressCodeDesc,
+	0x0A: swRtProgressCodeDesc,
+	0x0B: swAlProgressCodeDesc,
+	0x0F: swPeiServicesProgressCodeDesc,
+	0x10: swBootServicesProgressCodeDesc,
+	0x11: swRuntimeServicesProgressCodeDesc,
+	0x12: swDxeServicesProgressCodeDesc,
+}
+
+var swErrorCodeDesc = map[uint8]map[uint16]string{
+	0x02: swPeiCoreErrorCodeDesc,
+	0x03: swPeiErrorCodeDesc,
+	0x04: swDxeFoundationErrorCodeDesc,
+	0x05: swDxeBsErrorCodeDesc,
+	0x0F: swPeiServiceErrorCodeDesc,
+}
+
+// Error code mappings of the exception subclasses. The operation is the
+// exception type of the EFI debug support protocol, e.g. EXCEPT_X64_PAGE_FAULT,
+// rather than a common or subclass specific operation.
+var swExceptionErrorCodeDesc = map[uint8]map[uint16]string{
+	0x0C: swEBCErrorCodeDesc,
+	0x0D: swIA32ErrorCodeDesc,
+	0x0E: swIPFErrorCodeDesc,
+	0x13: swX64ExceptionErrorCodeDesc,
+	0x14: swArmExceptionErrorCodeDesc,
+}
@@ -0,0 +1,655 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import "testing"
+
+// Class and subclass values of PiStatusCode.h
+const (
+	EFI_COMPUTING_UNIT = 0x00000000
+	EFI_PERIPHERAL     = 0x01000000
+	EFI_IO_BUS         = 0x02000000
+	EFI_SOFTWARE       = 0x03000000
+
+	EFI_COMPUTING_UNIT_UNSPECIFIED        = EFI_COMPUTING_UNIT | 0x00000000
+	EFI_COMPUTING_UNIT_HOST_PROCESSOR     = EFI_COMPUTING_UNIT | 0x00010000
+	EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR = EFI_COMPUTING_UNIT | 0x00020000
+	EFI_COMPUTING_UNIT_IO_PROCESSOR       = EFI_COMPUTING_UNIT | 0x00030000
+	EFI_COMPUTING_UNIT_CACHE              = EFI_COMPUTING_UNIT | 0x00040000
+	EFI_COMPUTING_UNIT_MEMORY             = EFI_COMPUTING_UNIT | 0x00050000
+	EFI_COMPUTING_UNIT_CHIPSET            = EFI_COMPUTING_UNIT | 0x00060000
+
+	EFI_PERIPHERAL_UNSPECIFIED     = EFI_PERIPHERAL | 0x00000000
+	EFI_PERIPHERAL_KEYBOARD        = EFI_PERIPHERAL | 0x00010000
+	EFI_PERIPHERAL_MOUSE           = EFI_PERIPHERAL | 0x00020000
+	EFI_PERIPHERAL_LOCAL_CONSOLE   = EFI_PERIPHERAL | 0x00030000
+	EFI_PERIPHERAL_REMOTE_CONSOLE  = EFI_PERIPHERAL | 0x00040000
+	EFI_PERIPHERAL_SERIAL_PORT     = EFI_PERIPHERAL | 0x00050000
+	EFI_PERIPHERAL_PARALLEL_PORT   = EFI_PERIPHERAL | 0x00060000
+	EFI_PERIPHERAL_FIXED_MEDIA     = EFI_PERIPHERAL | 0x00070000
+	EFI_PERIPHERAL_REMOVABLE_MEDIA = EFI_PERIPHERAL | 0x00080000
+	EFI_PERIPHERAL_AUDIO_INPUT     = EFI_PERIPHERAL | 0x00090000
+	EFI_PERIPHERAL_AUDIO_OUTPUT    = EFI_PERIPHERAL | 0x000A0000
+	EFI_PERIPHERAL_LCD_DEVICE      = EFI_PERIPHERAL | 0x000B0000
+	EFI_PERIPHERAL_NETWORK         = EFI_PERIPHERAL | 0x000C0000
+	EFI_PERIPHERAL_DOCKING         = EFI_PERIPHERAL | 0x000D0000
+	EFI_PERIPHERAL_TPM             = EFI_PERIPHERAL | 0x000E0000
+
+	EFI_IO_BUS_UNSPECIFIED = EFI_IO_BUS | 0x00000000
+	EFI_IO_BUS_PCI         = EFI_IO_BUS | 0x00010000
+	EFI_IO_BUS_USB         = EFI_IO_BUS | 0x00020000
+	EFI_IO_BUS_IBA         = EFI_IO_BUS | 0x00030000
+	EFI_IO_BUS_AGP         = EFI_IO_BUS | 0x00040000
+	EFI_IO_BUS_PC_CARD     = EFI_IO_BUS | 0x00050000
+	EFI_IO_BUS_LPC         = EFI_IO_BUS | 0x00060000
+	EFI_IO_BUS_SCSI        = EFI_IO_BUS | 0x00070000
+	EFI_IO_BUS_ATA_ATAPI   = EFI_IO_BUS | 0x00080000
+	EFI_IO_BUS_FC          = EFI_IO_BUS | 0x00090000
+	EFI_IO_BUS_IP_NETWORK  = EFI_IO_BUS | 0x000A0000
+	EFI_IO_BUS_SMBUS       = EFI_IO_BUS | 0x000B0000
+	EFI_IO_BUS_I2C         = EFI_IO_BUS | 0x000C0000
+
+	EFI_SOFTWARE_UNSPECIFIED         = EFI_SOFTWARE | 0x00000000
+	EFI_SOFTWARE_SEC                 = EFI_SOFTWARE | 0x00010000
+	EFI_SOFTWARE_PEI_CORE            = EFI_SOFTWARE | 0x00020000
+	EFI_SOFTWARE_PEI_MODULE          = EFI_SOFTWARE | 0x00030000
+	EFI_SOFTWARE_DXE_CORE            = EFI_SOFTWARE | 0x00040000
+	EFI_SOFTWARE_DXE_BS_DRIVER       = EFI_SOFTWARE | 0x00050000
+	EFI_SOFTWARE_DXE_RT_DRIVER       = EFI_SOFTWARE | 0x00060000
+	EFI_SOFTWARE_SMM_DRIVER          = EFI_SOFTWARE | 0x00070000
+	EFI_SOFTWARE_EFI_APPLICATION     = EFI_SOFTWARE | 0x00080000
+	EFI_SOFTWARE_EFI_OS_LOADER       = EFI_SOFTWARE | 0x00090000
+	EFI_SOFTWARE_EFI_RT              = EFI_SOFTWARE | 0x000A0000
+	EFI_SOFTWARE_EFI_AL              = EFI_SOFTWARE | 0x000B0000
+	EFI_SOFTWARE_EBC_EXCEPTION       = EFI_SOFTWARE | 0x000C0000
+	EFI_SOFTWARE_IA32_EXCEPTION      = EFI_SOFTWARE | 0x000D0000
+	EFI_SOFTWARE_IPF_EXCEPTION       = EFI_SOFTWARE | 0x000E0000
+	EFI_SOFTWARE_PEI_SERVICE         = EFI_SOFTWARE | 0x000F0000
+	EFI_SOFTWARE_EFI_BOOT_SERVICE    = EFI_SOFTWARE | 0x00100000
+	EFI_SOFTWARE_EFI_RUNTIME_SERVICE = EFI_SOFTWARE | 0x00110000
+	EFI_SOFTWARE_EFI_DXE_SERVICE     = EFI_SOFTWARE | 0x00120000
+	EFI_SOFTWARE_X64_EXCEPTION       = EFI_SOFTWARE | 0x00130000
+	EFI_SOFTWARE_ARM_EXCEPTION       = EFI_SOFTWARE | 0x00140000
+
+	EFI_SUBCLASS_SPECIFIC = 0x00001000
+	EFI_OEM_SPECIFIC      = 0x00008000
+)
+
+func TestDescribeSubclass(t *testing.T) {
+	tests := []struct {
+		macro    string
+		value    uint32
+		subclass string
+	}{
+		{"EFI_COMPUTING_UNIT_UNSPECIFIED", EFI_COMPUTING_UNIT_UNSPECIFIED, "Unspecified"},
+		{"EFI_COMPUTING_UNIT_HOST_PROCESSOR", EFI_COMPUTING_UNIT_HOST_PROCESSOR, "Host Processor"},
+		{"EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR", EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR, "Firmware Processor"},
+		{"EFI_COMPUTING_UNIT_IO_PROCESSOR", EFI_COMPUTING_UNIT_IO_PROCESSOR, "I/O Processor"},
+		{"EFI_COMPUTING_UNIT_CACHE", EFI_COMPUTING_UNIT_CACHE, "Cache"},
+		{"EFI_COMPUTING_UNIT_MEMORY", EFI_COMPUTING_UNIT_MEMORY, "Memory"},
+		{"EFI_COMPUTING_UNIT_CHIPSET", EFI_COMPUTING_UNIT_CHIPSET, "Chipset"},
+
+		{"EFI_PERIPHERAL_UNSPECIFIED", EFI_PERIPHERAL_UNSPECIFIED, "Unspecified"},
+		{"EFI_PERIPHERAL_KEYBOARD", EFI_PERIPHERAL_KEYBOARD, "Keyboard"},
+		{"EFI_PERIPHERAL_MOUSE", EFI_PERIPHERAL_MOUSE, "Mouse"},
+		{"EFI_PERIPHERAL_LOCAL_CONSOLE", EFI_PERIPHERAL_LOCAL_CONSOLE, "Local Console"},
+		{"EFI_PERIPHERAL_REMOTE_CONSOLE", EFI_PERIPHERAL_REMOTE_CONSOLE, "Remote Console"},
+		{"EFI_PERIPHERAL_SERIAL_PORT", EFI_PERIPHERAL_SERIAL_PORT, "Serial Port"},
+		{"EFI_PERIPHERAL_PARALLEL_PORT", EFI_PERIPHERAL_PARALLEL_PORT, "Parallel Port"},
+		{"EFI_PERIPHERAL_FIXED_MEDIA", EFI_PERIPHERAL_FIXED_MEDIA, "Fixed Media"},
+		{"EFI_PERIPHERAL_REMOVABLE_MEDIA", EFI_PERIPHERAL_REMOVABLE_MEDIA, "Removable Media"},
+		{"EFI_PERIPHERAL_AUDIO_INPUT", EFI_PERIPHERAL_AUDIO_INPUT, "Audio Input"},
+		{"EFI_PERIPHERAL_AUDIO_OUTPUT", EFI_PERIPHERAL_AUDIO_OUTPUT, "Audio Output"},
+		{"EFI_PERIPHERAL_LCD_DEVICE", EFI_PERIPHERAL_LCD_DEVICE, "LCD Device"},
+		{"EFI_PERIPHERAL_NETWORK", EFI_PERIPHERAL_NETWORK, "Network"},
+		{"EFI_PERIPHERAL_DOCKING", EFI_PERIPHERAL_DOCKING, "Docking"},
+		{"EFI_PERIPHERAL_TPM", EFI_PERIPHERAL_TPM, "TPM"},
+
+		{"EFI_IO_BUS_UNSPECIFIED", EFI_IO_BUS_UNSPECIFIED, "Unspecified"},
+		{"EFI_IO_BUS_PCI", EFI_IO_BUS_PCI, "PCI"},
+		{"EFI_IO_BUS_USB", EFI_IO_BUS_USB, "USB"},
+		{"EFI_IO_BUS_IBA", EFI_IO_BUS_IBA, "IBA"},
+		{"EFI_IO_BUS_AGP", EFI_IO_BUS_AGP, "AGP"},
+		{"EFI_IO_BUS_PC_CARD", EFI_IO_BUS_PC_CARD, "PC Card"},
+		{"EFI_IO_BUS_LPC", EFI_IO_BUS_LPC, "LPC"},
+		{"EFI_IO_BUS_SCSI", EFI_IO_BUS_SCSI, "SCSI"},
+		{"EFI_IO_BUS_ATA_ATAPI", EFI_IO_BUS_ATA_ATAPI, "ATAPI"},
+		{"EFI_IO_BUS_FC", EFI_IO_BUS_FC, "FC"},
+		{"EFI_IO_BUS_IP_NETWORK", EFI_IO_BUS_IP_NETWORK, "IP Network"},
+		{"EFI_IO_BUS_SMBUS", EFI_IO_BUS_SMBUS, "SMBUS"},
+		{"EFI_IO_BUS_I2C", EFI_IO_BUS_I2C, "I2C"},
+
+		{"EFI_SOFTWARE_UNSPECIFIED", EFI_SOFTWARE_UNSPECIFIED, "Unspecified"},
+		{"EFI_SOFTWARE_SEC", EFI_SOFTWARE_SEC, "SEC"},
+		{"EFI_SOFTWARE_PEI_CORE", EFI_SOFTWARE_PEI_CORE, "PEI Core"},
+		{"EFI_SOFTWARE_PEI_MODULE", EFI_SOFTWARE_PEI_MODULE, "PEI Driver"},
+		{"EFI_SOFTWARE_DXE_CORE", EFI_SOFTWARE_DXE_CORE, "DXE Core"},
+		{"EFI_SOFTWARE_DXE_BS_DRIVER", EFI_SOFTWARE_DXE_BS_DRIVER, "DXE Boot Driver"},
+		{"EFI_SOFTWARE_DXE_RT_DRIVER", EFI_SOFTWARE_DXE_RT_DRIVER, "DXE Runtime Driver"},
+		{"EFI_SOFTWARE_SMM_DRIVER", EFI_SOFTWARE_SMM_DRIVER, "SMM Driver"},
+		{"EFI_SOFTWARE_EFI_APPLICATION", EFI_SOFTWARE_EFI_APPLICATION, "EFI Application"},
+		{"EFI_SOFTWARE_EFI_OS_LOADER", EFI_SOFTWARE_EFI_OS_LOADER, "OS Loader"},
+		{"EFI_SOFTWARE_EFI_RT", EFI_SOFTWARE_EFI_RT, "Runtime"},
+		{"EFI_SOFTWARE_EFI_AL", EFI_SOFTWARE_EFI_AL, "Afterlife"},
+		{"EFI_SOFTWARE_EBC_EXCEPTION", EFI_SOFTWARE_EBC_EXCEPTION, "EBC Exception"},
+		{"EFI_SOFTWARE_IA32_EXCEPTION", EFI_SOFTWARE_IA32_EXCEPTION, "X86 Exception"},
+		{"EFI_SOFTWARE_IPF_EXCEPTION", EFI_SOFTWARE_IPF_EXCEPTION, "IPF Exception"},
+		{"EFI_SOFTWARE_PEI_SERVICE", EFI_SOFTWARE_PEI_SERVICE, "PEI Service"},
+		{"EFI_SOFTWARE_EFI_BOOT_SERVICE", EFI_SOFTWARE_EFI_BOOT_SERVICE, "UEFI Boot Service"},
+		{"EFI_SOFTWARE_EFI_RUNTIME_SERVICE", EFI_SOFTWARE_EFI_RUNTIME_SERVICE, "UEFI Runtime Service"},
+		{"EFI_SOFTWARE_EFI_DXE_SERVICE", EFI_SOFTWARE_EFI_DXE_SERVICE, "DXE Service"},
+		{"EFI_SOFTWARE_X64_EXCEPTION", EFI_SOFTWARE_X64_EXCEPTION, "X64 Exception"},
+		{"EFI_SOFTWARE_ARM_EXCEPTION", EFI_SOFTWARE_ARM_EXCEPTION, "ARM Exception"},
+	}
+	for _, tt := range tests {
+		if _, subclass, _ := describeStatusValue(decodeStatusValue(tt.value), false); subclass != tt.subclass {
+			t.Errorf("%s (0x%08X) subclass = %q, want %q", tt.macro, tt.value, subclass, tt.subclass)
+		}
+	}
+}
+
+// TestDecodeOperation checks the operation of every status code macro of
+// PiStatusCode.h. The subclass specific macros are relative to
+// EFI_SUBCLASS_SPECIFIC, except the exception types.
+func TestDecodeOperation(t *testing.T) {
+	tests := []struct {
+		macro     string
+		value     uint32
+		isError   bool
+		operation string
+	}{
+		// Computing Unit Class progress codes
+		{"EFI_CU_PC_INIT_BEGIN", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000000, false, "Initialization Begin"},
+		{"EFI_CU_PC_INIT_END", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000001, false, "Initialization End"},
+
+		// Computing Unit Host Processor subclass progress codes
+		{"EFI_CU_HP_PC_POWER_ON_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Power On Init"},
+		{"EFI_CU_HP_PC_CACHE_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "Cache Init"},
+		{"EFI_CU_HP_PC_RAM_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "RAM Init"},
+		{"EFI_CU_HP_PC_MEMORY_CONTROLLER_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "Memory Controller Init"},
+		{"EFI_CU_HP_PC_IO_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "IO Init"},
+		{"EFI_CU_HP_PC_BSP_SELECT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "BSP Select"},
+		{"EFI_CU_HP_PC_BSP_RESELECT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "BSP Reselect"},
+		{"EFI_CU_HP_PC_AP_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "AP Init"},
+		{"EFI_CU_HP_PC_SMM_INIT", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "SMM Init"},
+
+		// Computing Unit Cache subclass progress codes
+		{"EFI_CU_CACHE_PC_PRESENCE_DETECT", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Presence Detect"},
+		{"EFI_CU_CACHE_PC_CONFIGURATION", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "Configuration"},
+
+		// Computing Unit Memory subclass progress codes
+		{"EFI_CU_MEMORY_PC_SPD_READ", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "SPD Read"},
+		{"EFI_CU_MEMORY_PC_PRESENCE_DETECT", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "Presence Detect"},
+		{"EFI_CU_MEMORY_PC_TIMING", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "Timing"},
+		{"EFI_CU_MEMORY_PC_CONFIGURING", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "Configuring"},
+		{"EFI_CU_MEMORY_PC_OPTIMIZING", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "Optimizing"},
+		{"EFI_CU_MEMORY_PC_INIT", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "Init"},
+		{"EFI_CU_MEMORY_PC_TEST", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "Test"},
+
+		// Computing Unit Chipset subclass progress codes
+		{"EFI_CHIPSET_PC_PEI_CAR_SB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "PEI CAR South Bridge Initialization"},
+		{"EFI_CHIPSET_PC_PEI_CAR_NB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "PEI CAR North Bridge Initialization"},
+		{"EFI_CHIPSET_PC_PEI_MEM_SB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "PEI MEM South Bridge Initialization"},
+		{"EFI_CHIPSET_PC_PEI_MEM_NB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "PEI MEM North Bridge Initialization"},
+		{"EFI_CHIPSET_PC_DXE_HB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "DXE PCI Host Bridge Initialization"},
+		{"EFI_CHIPSET_PC_DXE_NB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "DXE North Bridge Initialization"},
+		{"EFI_CHIPSET_PC_DXE_NB_SMM_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "DXE North Bridge SMM Initialization"},
+		{"EFI_CHIPSET_PC_DXE_SB_RT_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "DXE South Bridge Runtime Services Initialization"},
+		{"EFI_CHIPSET_PC_DXE_SB_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "DXE South Bridge Initialization"},
+		{"EFI_CHIPSET_PC_DXE_SB_SMM_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "DXE South Bridge SMM Initialization"},
+		{"EFI_CHIPSET_PC_DXE_SB_DEVICES_INIT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x0000000A, false, "DXE South Bridge Devices Initialization"},
+
+		// Computing Unit Class error codes
+		{"EFI_CU_EC_NON_SPECIFIC", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000000, true, "Unspecified"},
+		{"EFI_CU_EC_DISABLED", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000001, true, "Disabled"},
+		{"EFI_CU_EC_NOT_SUPPORTED", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000002, true, "Not Supported"},
+		{"EFI_CU_EC_NOT_DETECTED", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000003, true, "Not Detected"},
+		{"EFI_CU_EC_NOT_CONFIGURED", EFI_COMPUTING_UNIT_UNSPECIFIED | 0x00000004, true, "Not Configured"},
+
+		// Computing Unit Host Processor subclass error codes
+		{"EFI_CU_HP_EC_INVALID_TYPE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Invalid Type"},
+		{"EFI_CU_HP_EC_INVALID_SPEED", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Invalid Speed"},
+		{"EFI_CU_HP_EC_MISMATCH", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Mismatch"},
+		{"EFI_CU_HP_EC_TIMER_EXPIRED", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "Timer Expired"},
+		{"EFI_CU_HP_EC_SELF_TEST", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000004, true, "Self Test"},
+		{"EFI_CU_HP_EC_INTERNAL", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000005, true, "Internal"},
+		{"EFI_CU_HP_EC_THERMAL", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000006, true, "Thermal"},
+		{"EFI_CU_HP_EC_LOW_VOLTAGE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000007, true, "Low Voltage"},
+		{"EFI_CU_HP_EC_HIGH_VOLTAGE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000008, true, "High Voltage"},
+		{"EFI_CU_HP_EC_CACHE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000009, true, "Cache"},
+		{"EFI_CU_HP_EC_MICROCODE_UPDATE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x0000000A, true, "Microcode Update"},
+		{"EFI_CU_HP_EC_CORRECTABLE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x0000000B, true, "Correctable"},
+		{"EFI_CU_HP_EC_UNCORRECTABLE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x0000000C, true, "Uncorrectable"},
+		{"EFI_CU_HP_EC_NO_MICROCODE_UPDATE", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x0000000D, true, "No Microcode Update"},
+
+		// Computing Unit Firmware Processor subclass error codes
+		{"EFI_CU_FP_EC_HARD_FAIL", EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Hard Fail"},
+		{"EFI_CU_FP_EC_SOFT_FAIL", EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Soft Fail"},
+		{"EFI_CU_FP_EC_COMM_ERROR", EFI_COMPUTING_UNIT_FIRMWARE_PROCESSOR | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Communication Error"},
+
+		// Computing Unit Cache subclass error codes
+		{"EFI_CU_CACHE_EC_INVALID_TYPE", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Invalid Type"},
+		{"EFI_CU_CACHE_EC_INVALID_SPEED", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Invalid Speed"},
+		{"EFI_CU_CACHE_EC_INVALID_SIZE", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Invalid Size"},
+		{"EFI_CU_CACHE_EC_MISMATCH", EFI_COMPUTING_UNIT_CACHE | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "Mismatch"},
+
+		// Computing Unit Memory subclass error codes
+		{"EFI_CU_MEMORY_EC_INVALID_TYPE", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Invalid Type"},
+		{"EFI_CU_MEMORY_EC_INVALID_SPEED", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Invalid Speed"},
+		{"EFI_CU_MEMORY_EC_CORRECTABLE", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Correctable"},
+		{"EFI_CU_MEMORY_EC_UNCORRECTABLE", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "Uncorrectable"},
+		{"EFI_CU_MEMORY_EC_SPD_FAIL", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000004, true, "SPD Fail"},
+		{"EFI_CU_MEMORY_EC_INVALID_SIZE", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000005, true, "Invalid Size"},
+		{"EFI_CU_MEMORY_EC_MISMATCH", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000006, true, "Mismatch"},
+		{"EFI_CU_MEMORY_EC_S3_RESUME_FAIL", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000007, true, "S3 Resume Fail"},
+		{"EFI_CU_MEMORY_EC_UPDATE_FAIL", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000008, true, "Update Fail"},
+		{"EFI_CU_MEMORY_EC_NONE_DETECTED", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x00000009, true, "None Detected"},
+		{"EFI_CU_MEMORY_EC_NONE_USEFUL", EFI_COMPUTING_UNIT_MEMORY | EFI_SUBCLASS_SPECIFIC | 0x0000000A, true, "None Useful"},
+
+		// Computing Unit Chipset subclass error codes
+		{"EFI_CHIPSET_EC_BAD_BATTERY", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Bad Battery"},
+		{"EFI_CHIPSET_EC_DXE_NB_ERROR", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "DXE North Bridge Error"},
+		{"EFI_CHIPSET_EC_DXE_SB_ERROR", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "DXE South Bridge Error"},
+		{"EFI_CHIPSET_EC_INTRUDER_DETECT", EFI_COMPUTING_UNIT_CHIPSET | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "Intruder Detect"},
+
+		// Peripheral Class progress codes
+		{"EFI_P_PC_INIT", EFI_PERIPHERAL_UNSPECIFIED | 0x00000000, false, "Init"},
+		{"EFI_P_PC_RESET", EFI_PERIPHERAL_UNSPECIFIED | 0x00000001, false, "Reset"},
+		{"EFI_P_PC_DISABLE", EFI_PERIPHERAL_UNSPECIFIED | 0x00000002, false, "Disable"},
+		{"EFI_P_PC_PRESENCE_DETECT", EFI_PERIPHERAL_UNSPECIFIED | 0x00000003, false, "Presence Detect"},
+		{"EFI_P_PC_ENABLE", EFI_PERIPHERAL_UNSPECIFIED | 0x00000004, false, "Enable"},
+		{"EFI_P_PC_RECONFIG", EFI_PERIPHERAL_UNSPECIFIED | 0x00000005, false, "Reconfig"},
+		{"EFI_P_PC_DETECTED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000006, false, "Detected"},
+		{"EFI_P_PC_REMOVED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000007, false, "Removed"},
+
+		// Peripheral Keyboard subclass progress codes
+		{"EFI_P_KEYBOARD_PC_CLEAR_BUFFER", EFI_PERIPHERAL_KEYBOARD | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Clear Buffer"},
+		{"EFI_P_KEYBOARD_PC_SELF_TEST", EFI_PERIPHERAL_KEYBOARD | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "Self Test"},
+
+		// Peripheral Mouse subclass progress codes
+		{"EFI_P_MOUSE_PC_SELF_TEST", EFI_PERIPHERAL_MOUSE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Self Test"},
+
+		// Peripheral Serial Port subclass progress codes
+		{"EFI_P_SERIAL_PORT_PC_CLEAR_BUFFER", EFI_PERIPHERAL_SERIAL_PORT | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Clear Buffer"},
+
+		// Peripheral Class error codes
+		{"EFI_P_EC_NON_SPECIFIC", EFI_PERIPHERAL_UNSPECIFIED | 0x00000000, true, "Non Specific"},
+		{"EFI_P_EC_DISABLED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000001, true, "Disabled"},
+		{"EFI_P_EC_NOT_SUPPORTED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000002, true, "Not Supported"},
+		{"EFI_P_EC_NOT_DETECTED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000003, true, "Not Detected"},
+		{"EFI_P_EC_NOT_CONFIGURED", EFI_PERIPHERAL_UNSPECIFIED | 0x00000004, true, "Not Configured"},
+		{"EFI_P_EC_INTERFACE_ERROR", EFI_PERIPHERAL_UNSPECIFIED | 0x00000005, true, "Interface Error"},
+		{"EFI_P_EC_CONTROLLER_ERROR", EFI_PERIPHERAL_UNSPECIFIED | 0x00000006, true, "Controller Error"},
+		{"EFI_P_EC_INPUT_ERROR", EFI_PERIPHERAL_UNSPECIFIED | 0x00000007, true, "Input Error"},
+		{"EFI_P_EC_OUTPUT_ERROR", EFI_PERIPHERAL_UNSPECIFIED | 0x00000008, true, "Output Error"},
+		{"EFI_P_EC_RESOURCE_CONFLICT", EFI_PERIPHERAL_UNSPECIFIED | 0x00000009, true, "Resource Conflict"},
+
+		// Peripheral Keyboard subclass error codes
+		{"EFI_P_KEYBOARD_EC_LOCKED", EFI_PERIPHERAL_KEYBOARD | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Locked"},
+		{"EFI_P_KEYBOARD_EC_STUCK_KEY", EFI_PERIPHERAL_KEYBOARD | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Stuck Key"},
+		{"EFI_P_KEYBOARD_EC_BUFFER_FULL", EFI_PERIPHERAL_KEYBOARD | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Buffer Full"},
+
+		// Peripheral Mouse subclass error codes
+		{"EFI_P_MOUSE_EC_LOCKED", EFI_PERIPHERAL_MOUSE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Locked"},
+
+		// I/O Bus Class progress codes
+		{"EFI_IOB_PC_INIT", EFI_IO_BUS_UNSPECIFIED | 0x00000000, false, "Init"},
+		{"EFI_IOB_PC_RESET", EFI_IO_BUS_UNSPECIFIED | 0x00000001, false, "Reset"},
+		{"EFI_IOB_PC_DISABLE", EFI_IO_BUS_UNSPECIFIED | 0x00000002, false, "Disable"},
+		{"EFI_IOB_PC_DETECT", EFI_IO_BUS_UNSPECIFIED | 0x00000003, false, "Detect"},
+		{"EFI_IOB_PC_ENABLE", EFI_IO_BUS_UNSPECIFIED | 0x00000004, false, "Enable"},
+		{"EFI_IOB_PC_RECONFIG", EFI_IO_BUS_UNSPECIFIED | 0x00000005, false, "Reconfig"},
+		{"EFI_IOB_PC_HOTPLUG", EFI_IO_BUS_UNSPECIFIED | 0x00000006, false, "Hotplug"},
+
+		// I/O Bus PCI subclass progress codes
+		{"EFI_IOB_PCI_BUS_ENUM", EFI_IO_BUS_PCI | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "PCI Bus Enumeration"},
+		{"EFI_IOB_PCI_RES_ALLOC", EFI_IO_BUS_PCI | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "PCI Resource Allocation"},
+		{"EFI_IOB_PCI_HPC_INIT", EFI_IO_BUS_PCI | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "PCI HPC Initialization"},
+
+		// I/O Bus ATA/ATAPI subclass progress codes
+		{"EFI_IOB_ATA_BUS_SMART_ENABLE", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "SMART Enable"},
+		{"EFI_IOB_ATA_BUS_SMART_DISABLE", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "SMART Disable"},
+		{"EFI_IOB_ATA_BUS_SMART_OVERTHRESHOLD", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "SMART Overthreshold"},
+		{"EFI_IOB_ATA_BUS_SMART_UNDERTHRESHOLD", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "SMART Underthreshold"},
+
+		// I/O Bus Class error codes
+		{"EFI_IOB_EC_NON_SPECIFIC", EFI_IO_BUS_UNSPECIFIED | 0x00000000, true, "Non Specific"},
+		{"EFI_IOB_EC_DISABLED", EFI_IO_BUS_UNSPECIFIED | 0x00000001, true, "Disabled"},
+		{"EFI_IOB_EC_NOT_SUPPORTED", EFI_IO_BUS_UNSPECIFIED | 0x00000002, true, "Not Supported"},
+		{"EFI_IOB_EC_NOT_DETECTED", EFI_IO_BUS_UNSPECIFIED | 0x00000003, true, "Not Detected"},
+		{"EFI_IOB_EC_NOT_CONFIGURED", EFI_IO_BUS_UNSPECIFIED | 0x00000004, true, "Not Configured"},
+		{"EFI_IOB_EC_INTERFACE_ERROR", EFI_IO_BUS_UNSPECIFIED | 0x00000005, true, "Interface Error"},
+		{"EFI_IOB_EC_CONTROLLER_ERROR", EFI_IO_BUS_UNSPECIFIED | 0x00000006, true, "Controller Error"},
+		{"EFI_IOB_EC_READ_ERROR", EFI_IO_BUS_UNSPECIFIED | 0x00000007, true, "Read Error"},
+		{"EFI_IOB_EC_WRITE_ERROR", EFI_IO_BUS_UNSPECIFIED | 0x00000008, true, "Write Error"},
+		{"EFI_IOB_EC_RESOURCE_CONFLICT", EFI_IO_BUS_UNSPECIFIED | 0x00000009, true, "Resource Conflict"},
+
+		// I/O Bus PCI subclass error codes
+		{"EFI_IOB_PCI_EC_PERR", EFI_IO_BUS_PCI | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "PCI PERR"},
+		{"EFI_IOB_PCI_EC_SERR", EFI_IO_BUS_PCI | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "PCI SERR"},
+
+		// I/O Bus ATA/ATAPI subclass error codes
+		{"EFI_IOB_ATA_BUS_SMART_NOTSUPPORTED", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "ATA Bus SMART Not Supported"},
+		{"EFI_IOB_ATA_BUS_SMART_DISABLED", EFI_IO_BUS_ATA_ATAPI | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "ATA Bus SMART Disabled"},
+
+		// Software Class progress codes
+		{"EFI_SW_PC_INIT", EFI_SOFTWARE_UNSPECIFIED | 0x00000000, false, "Init"},
+		{"EFI_SW_PC_LOAD", EFI_SOFTWARE_UNSPECIFIED | 0x00000001, false, "Load"},
+		{"EFI_SW_PC_INIT_BEGIN", EFI_SOFTWARE_UNSPECIFIED | 0x00000002, false, "Init Begin"},
+		{"EFI_SW_PC_INIT_END", EFI_SOFTWARE_UNSPECIFIED | 0x00000003, false, "Init End"},
+		{"EFI_SW_PC_AUTHENTICATE_BEGIN", EFI_SOFTWARE_UNSPECIFIED | 0x00000004, false, "Authenticate Begin"},
+		{"EFI_SW_PC_AUTHENTICATE_END", EFI_SOFTWARE_UNSPECIFIED | 0x00000005, false, "Authenticate End"},
+		{"EFI_SW_PC_INPUT_WAIT", EFI_SOFTWARE_UNSPECIFIED | 0x00000006, false, "Input Wait"},
+		{"EFI_SW_PC_USER_SETUP", EFI_SOFTWARE_UNSPECIFIED | 0x00000007, false, "User Setup"},
+
+		// Software SEC subclass progress codes
+		{"EFI_SW_SEC_PC_ENTRY_POINT", EFI_SOFTWARE_SEC | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "SEC Entry Point"},
+		{"EFI_SW_SEC_PC_HANDOFF_TO_NEXT", EFI_SOFTWARE_SEC | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "SEC Handoff To Next"},
+
+		// Software PEI Core subclass progress codes
+		{"EFI_SW_PEI_CORE_PC_ENTRY_POINT", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "PEI Core Entry Point"},
+		{"EFI_SW_PEI_CORE_PC_HANDOFF_TO_NEXT", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "PEI Core Handoff To Next"},
+		{"EFI_SW_PEI_CORE_PC_RETURN_TO_LAST", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "PEI Core Return To Last"},
+
+		// Software PEI Module subclass progress codes
+		{"EFI_SW_PEI_PC_RECOVERY_BEGIN", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "PEI Recovery Begin"},
+		{"EFI_SW_PEI_PC_CAPSULE_LOAD", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "PEI Capsule Load"},
+		{"EFI_SW_PEI_PC_CAPSULE_START", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "PEI Capsule Start"},
+		{"EFI_SW_PEI_PC_RECOVERY_USER", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "PEI Recovery User"},
+		{"EFI_SW_PEI_PC_RECOVERY_AUTO", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "PEI Recovery Auto"},
+		{"EFI_SW_PEI_PC_S3_BOOT_SCRIPT", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "PEI S3 Boot Script"},
+		{"EFI_SW_PEI_PC_OS_WAKE", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "PEI OS Wake"},
+		{"EFI_SW_PEI_PC_S3_STARTED", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "PEI S3 Started"},
+
+		// Software DXE Core subclass progress codes
+		{"EFI_SW_DXE_CORE_PC_ENTRY_POINT", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "DXE Core Entry Point"},
+		{"EFI_SW_DXE_CORE_PC_HANDOFF_TO_NEXT", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "DXE Core Handoff To Next"},
+		{"EFI_SW_DXE_CORE_PC_RETURN_TO_LAST", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "DXE Core Return To Last"},
+		{"EFI_SW_DXE_CORE_PC_START_DRIVER", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "DXE Core Start Driver"},
+		{"EFI_SW_DXE_CORE_PC_ARCH_READY", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "DXE Core Arch Ready"},
+
+		// Software DXE Boot Service Driver subclass progress codes
+		{"EFI_SW_DXE_BS_PC_LEGACY_OPROM_INIT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "DXE BS Legacy OpROM Init"},
+		{"EFI_SW_DXE_BS_PC_READY_TO_BOOT_EVENT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "DXE BS Ready To Boot Event"},
+		{"EFI_SW_DXE_BS_PC_LEGACY_BOOT_EVENT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "DXE BS Legacy Boot Event"},
+		{"EFI_SW_DXE_BS_PC_EXIT_BOOT_SERVICES_EVENT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "DXE BS Exit Boot Services Event"},
+		{"EFI_SW_DXE_BS_PC_VIRTUAL_ADDRESS_CHANGE_EVENT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "DXE BS Virtual Address Change Event"},
+		{"EFI_SW_DXE_BS_PC_VARIABLE_SERVICES_INIT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "DXE BS Variable Services Init"},
+		{"EFI_SW_DXE_BS_PC_VARIABLE_RECLAIM", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "DXE BS Variable Reclaim"},
+		{"EFI_SW_DXE_BS_PC_ATTEMPT_BOOT_ORDER_EVENT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "DXE BS Attempt Boot Order Event"},
+		{"EFI_SW_DXE_BS_PC_CONFIG_RESET", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "DXE BS Config Reset"},
+		{"EFI_SW_DXE_BS_PC_CSM_INIT", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "DXE BS CSM Init"},
+
+		// Software DXE Runtime Service Driver subclass progress codes
+		{"EFI_SW_DXE_RT_PC_S0", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "S0"},
+		{"EFI_SW_DXE_RT_PC_S1", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "S1"},
+		{"EFI_SW_DXE_RT_PC_S2", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "S2"},
+		{"EFI_SW_DXE_RT_PC_S3", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "S3"},
+		{"EFI_SW_DXE_RT_PC_S4", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "S4"},
+		{"EFI_SW_DXE_RT_PC_S5", EFI_SOFTWARE_DXE_RT_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "S5"},
+
+		// Software Runtime subclass progress codes
+		{"EFI_SW_RT_PC_ENTRY_POINT", EFI_SOFTWARE_EFI_RT | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "EFI RT Entry Point"},
+		{"EFI_SW_RT_PC_HANDOFF_TO_NEXT", EFI_SOFTWARE_EFI_RT | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "EFI RT Handoff To Next"},
+		{"EFI_SW_RT_PC_RETURN_TO_LAST", EFI_SOFTWARE_EFI_RT | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "EFI RT Return To Last"},
+
+		// Software Afterlife subclass progress codes
+		{"EFI_SW_AL_PC_ENTRY_POINT", EFI_SOFTWARE_EFI_AL | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "EFI AL Entry Point"},
+		{"EFI_SW_AL_PC_RETURN_TO_LAST", EFI_SOFTWARE_EFI_AL | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "EFI AL Return To Last"},
+
+		// Software PEI Service subclass progress codes
+		{"EFI_SW_PS_PC_INSTALL_PPI", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "PEI Service Install PPI"},
+		{"EFI_SW_PS_PC_REINSTALL_PPI", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "PEI Service Reinstall PPI"},
+		{"EFI_SW_PS_PC_LOCATE_PPI", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "PEI Service Locate PPI"},
+		{"EFI_SW_PS_PC_NOTIFY_PPI", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "PEI Service Notify PPI"},
+		{"EFI_SW_PS_PC_GET_BOOT_MODE", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "PEI Service Get Boot Mode"},
+		{"EFI_SW_PS_PC_SET_BOOT_MODE", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "PEI Service Set Boot Mode"},
+		{"EFI_SW_PS_PC_GET_HOB_LIST", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "PEI Service Get HOB List"},
+		{"EFI_SW_PS_PC_CREATE_HOB", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "PEI Service Create HOB"},
+		{"EFI_SW_PS_PC_FFS_FIND_NEXT_VOLUME", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "PEI Service FFS Find Next Volume"},
+		{"EFI_SW_PS_PC_FFS_FIND_NEXT_FILE", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "PEI Service FFS Find Next File"},
+		{"EFI_SW_PS_PC_FFS_FIND_SECTION_DATA", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000A, false, "PEI Service FFS Find Section Data"},
+		{"EFI_SW_PS_PC_INSTALL_PEI_MEMORY", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000B, false, "PEI Service Install PEI Memory"},
+		{"EFI_SW_PS_PC_ALLOCATE_PAGES", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000C, false, "PEI Service Allocate Pages"},
+		{"EFI_SW_PS_PC_ALLOCATE_POOL", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000D, false, "PEI Service Allocate Pool"},
+		{"EFI_SW_PS_PC_COPY_MEM", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000E, false, "PEI Service Copy Mem"},
+		{"EFI_SW_PS_PC_SET_MEM", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000F, false, "PEI Service Set Mem"},
+		{"EFI_SW_PS_PC_RESET_SYSTEM", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000010, false, "PEI Service Reset System"},
+		{"EFI_SW_PS_PC_FFS_FIND_FILE_BY_NAME", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000013, false, "PEI Service FFS Find File By Name"},
+		{"EFI_SW_PS_PC_FFS_GET_FILE_INFO", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000014, false, "PEI Service FFS Get File Info"},
+		{"EFI_SW_PS_PC_FFS_GET_VOLUME_INFO", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000015, false, "PEI Service FFS Get Volume Info"},
+		{"EFI_SW_PS_PC_FFS_REGISTER_FOR_SHADOW", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000016, false, "PEI Service FFS Register For Shadow"},
+
+		// Software Boot Service subclass progress codes
+		{"EFI_SW_BS_PC_RAISE_TPL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "EFI BS Raise TPL"},
+		{"EFI_SW_BS_PC_RESTORE_TPL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "EFI BS Restore TPL"},
+		{"EFI_SW_BS_PC_ALLOCATE_PAGES", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "EFI BS Allocate Pages"},
+		{"EFI_SW_BS_PC_FREE_PAGES", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "EFI BS Free Pages"},
+		{"EFI_SW_BS_PC_GET_MEMORY_MAP", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "EFI BS Get Memory Map"},
+		{"EFI_SW_BS_PC_ALLOCATE_POOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "EFI BS Allocate Pool"},
+		{"EFI_SW_BS_PC_FREE_POOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "EFI BS Free Pool"},
+		{"EFI_SW_BS_PC_CREATE_EVENT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "EFI BS Create Event"},
+		{"EFI_SW_BS_PC_SET_TIMER", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "EFI BS Set Timer"},
+		{"EFI_SW_BS_PC_WAIT_FOR_EVENT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "EFI BS Wait For Event"},
+		{"EFI_SW_BS_PC_SIGNAL_EVENT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000A, false, "EFI BS Signal Event"},
+		{"EFI_SW_BS_PC_CLOSE_EVENT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000B, false, "EFI BS Close Event"},
+		{"EFI_SW_BS_PC_CHECK_EVENT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000C, false, "EFI BS Check Event"},
+		{"EFI_SW_BS_PC_INSTALL_PROTOCOL_INTERFACE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000D, false, "EFI BS Install Protocol Interface"},
+		{"EFI_SW_BS_PC_REINSTALL_PROTOCOL_INTERFACE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000E, false, "EFI BS Reinstall Protocol Interface"},
+		{"EFI_SW_BS_PC_UNINSTALL_PROTOCOL_INTERFACE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000F, false, "EFI BS Uninstall Protocol Interface"},
+		{"EFI_SW_BS_PC_HANDLE_PROTOCOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000010, false, "EFI BS Handle Protocol"},
+		{"EFI_SW_BS_PC_PC_HANDLE_PROTOCOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000011, false, "EFI BS PC Handle Protocol"},
+		{"EFI_SW_BS_PC_REGISTER_PROTOCOL_NOTIFY", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000012, false, "EFI BS Register Protocol Notify"},
+		{"EFI_SW_BS_PC_LOCATE_HANDLE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000013, false, "EFI BS Locate Handle"},
+		{"EFI_SW_BS_PC_INSTALL_CONFIGURATION_TABLE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000014, false, "EFI BS Install Configuration Table"},
+		{"EFI_SW_BS_PC_LOAD_IMAGE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000015, false, "EFI BS Load Image"},
+		{"EFI_SW_BS_PC_START_IMAGE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000016, false, "EFI BS Start Image"},
+		{"EFI_SW_BS_PC_EXIT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000017, false, "EFI BS Exit"},
+		{"EFI_SW_BS_PC_UNLOAD_IMAGE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000018, false, "EFI BS Unload Image"},
+		{"EFI_SW_BS_PC_EXIT_BOOT_SERVICES", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000019, false, "EFI BS Exit Boot Services"},
+		{"EFI_SW_BS_PC_GET_NEXT_MONOTONIC_COUNT", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001A, false, "EFI BS Get Next Monotonic Count"},
+		{"EFI_SW_BS_PC_STALL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001B, false, "EFI BS Stall"},
+		{"EFI_SW_BS_PC_SET_WATCHDOG_TIMER", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001C, false, "EFI BS Set Watchdog Timer"},
+		{"EFI_SW_BS_PC_CONNECT_CONTROLLER", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001D, false, "EFI BS Connect Controller"},
+		{"EFI_SW_BS_PC_DISCONNECT_CONTROLLER", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001E, false, "EFI BS Disconnect Controller"},
+		{"EFI_SW_BS_PC_OPEN_PROTOCOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000001F, false, "EFI BS Open Protocol"},
+		{"EFI_SW_BS_PC_CLOSE_PROTOCOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000020, false, "EFI BS Close Protocol"},
+		{"EFI_SW_BS_PC_OPEN_PROTOCOL_INFORMATION", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000021, false, "EFI BS Open Protocol Information"},
+		{"EFI_SW_BS_PC_PROTOCOLS_PER_HANDLE", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000022, false, "EFI BS Protocols Per Handle"},
+		{"EFI_SW_BS_PC_LOCATE_HANDLE_BUFFER", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000023, false, "EFI BS Locate Handle Buffer"},
+		{"EFI_SW_BS_PC_LOCATE_PROTOCOL", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000024, false, "EFI BS Locate Protocol"},
+		{"EFI_SW_BS_PC_INSTALL_MULTIPLE_INTERFACES", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000025, false, "EFI BS Install Multiple Interfaces"},
+		{"EFI_SW_BS_PC_UNINSTALL_MULTIPLE_INTERFACES", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000026, false, "EFI BS Uninstall Multiple Interfaces"},
+		{"EFI_SW_BS_PC_CALCULATE_CRC_32", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000027, false, "EFI BS Calculate CRC32"},
+		{"EFI_SW_BS_PC_COPY_MEM", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000028, false, "EFI BS Copy Mem"},
+		{"EFI_SW_BS_PC_SET_MEM", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000029, false, "EFI BS Set Mem"},
+		{"EFI_SW_BS_PC_CREATE_EVENT_EX", EFI_SOFTWARE_EFI_BOOT_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000002A, false, "EFI BS Create Event Ex"},
+
+		// Software Runtime Service subclass progress codes
+		{"EFI_SW_RS_PC_GET_TIME", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "EFI RS Get Time"},
+		{"EFI_SW_RS_PC_SET_TIME", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "EFI RS Set Time"},
+		{"EFI_SW_RS_PC_GET_WAKEUP_TIME", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "EFI RS Get Wakeup Time"},
+		{"EFI_SW_RS_PC_SET_WAKEUP_TIME", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "EFI RS Set Wakeup Time"},
+		{"EFI_SW_RS_PC_SET_VIRTUAL_ADDRESS_MAP", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "EFI RS Set Virtual Address Map"},
+		{"EFI_SW_RS_PC_CONVERT_POINTER", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "EFI RS Convert Pointer"},
+		{"EFI_SW_RS_PC_GET_VARIABLE", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "EFI RS Get Variable"},
+		{"EFI_SW_RS_PC_GET_NEXT_VARIABLE_NAME", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "EFI RS Get Next Variable Name"},
+		{"EFI_SW_RS_PC_SET_VARIABLE", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "EFI RS Set Variable"},
+		{"EFI_SW_RS_PC_GET_NEXT_HIGH_MONOTONIC_COUNT", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "EFI RS Get Next High Monotonic Count"},
+		{"EFI_SW_RS_PC_RESET_SYSTEM", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000A, false, "EFI RS Reset System"},
+		{"EFI_SW_RS_PC_UPDATE_CAPSULE", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000B, false, "EFI RS Update Capsule"},
+		{"EFI_SW_RS_PC_QUERY_CAPSULE_CAPABILITIES", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000C, false, "EFI RS Query Capsule Capabilities"},
+		{"EFI_SW_RS_PC_QUERY_VARIABLE_INFO", EFI_SOFTWARE_EFI_RUNTIME_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000D, false, "EFI RS Query Variable Info"},
+
+		// Software DXE Service subclass progress codes
+		{"EFI_SW_DS_PC_ADD_MEMORY_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "EFI DS Add Memory Space"},
+		{"EFI_SW_DS_PC_ALLOCATE_MEMORY_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000001, false, "EFI DS Allocate Memory Space"},
+		{"EFI_SW_DS_PC_FREE_MEMORY_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000002, false, "EFI DS Free Memory Space"},
+		{"EFI_SW_DS_PC_REMOVE_MEMORY_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000003, false, "EFI DS Remove Memory Space"},
+		{"EFI_SW_DS_PC_GET_MEMORY_SPACE_DESCRIPTOR", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000004, false, "EFI DS Get Memory Space Descriptor"},
+		{"EFI_SW_DS_PC_SET_MEMORY_SPACE_ATTRIBUTES", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000005, false, "EFI DS Set Memory Space Attributes"},
+		{"EFI_SW_DS_PC_GET_MEMORY_SPACE_MAP", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000006, false, "EFI DS Get Memory Space Map"},
+		{"EFI_SW_DS_PC_ADD_IO_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000007, false, "EFI DS Add IO Space"},
+		{"EFI_SW_DS_PC_ALLOCATE_IO_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000008, false, "EFI DS Allocate IO Space"},
+		{"EFI_SW_DS_PC_FREE_IO_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000009, false, "EFI DS Free IO Space"},
+		{"EFI_SW_DS_PC_REMOVE_IO_SPACE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000A, false, "EFI DS Remove IO Space"},
+		{"EFI_SW_DS_PC_GET_IO_SPACE_DESCRIPTOR", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000B, false, "EFI DS Get IO Space Descriptor"},
+		{"EFI_SW_DS_PC_GET_IO_SPACE_MAP", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000C, false, "EFI DS Get IO Space Map"},
+		{"EFI_SW_DS_PC_DISPATCH", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000D, false, "EFI DS Dispatch"},
+		{"EFI_SW_DS_PC_SCHEDULE", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000E, false, "EFI DS Schedule"},
+		{"EFI_SW_DS_PC_TRUST", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x0000000F, false, "EFI DS Trust"},
+		{"EFI_SW_DS_PC_PROCESS_FIRMWARE_VOLUME", EFI_SOFTWARE_EFI_DXE_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000010, false, "EFI DS Process Firmware Volume"},
+
+		// Software Class error codes
+		{"EFI_SW_EC_NON_SPECIFIC", EFI_SOFTWARE_UNSPECIFIED | 0x00000000, true, "Non-specific"},
+		{"EFI_SW_EC_LOAD_ERROR", EFI_SOFTWARE_UNSPECIFIED | 0x00000001, true, "Load Error"},
+		{"EFI_SW_EC_INVALID_PARAMETER", EFI_SOFTWARE_UNSPECIFIED | 0x00000002, true, "Invalid Parameter"},
+		{"EFI_SW_EC_UNSUPPORTED", EFI_SOFTWARE_UNSPECIFIED | 0x00000003, true, "Unsupported"},
+		{"EFI_SW_EC_INVALID_BUFFER", EFI_SOFTWARE_UNSPECIFIED | 0x00000004, true, "Invalid Buffer"},
+		{"EFI_SW_EC_OUT_OF_RESOURCES", EFI_SOFTWARE_UNSPECIFIED | 0x00000005, true, "Out of Resources"},
+		{"EFI_SW_EC_ABORTED", EFI_SOFTWARE_UNSPECIFIED | 0x00000006, true, "Aborted"},
+		{"EFI_SW_EC_ILLEGAL_SOFTWARE_STATE", EFI_SOFTWARE_UNSPECIFIED | 0x00000007, true, "Illegal Software State"},
+		{"EFI_SW_EC_ILLEGAL_HARDWARE_STATE", EFI_SOFTWARE_UNSPECIFIED | 0x00000008, true, "Illegal Hardware State"},
+		{"EFI_SW_EC_START_ERROR", EFI_SOFTWARE_UNSPECIFIED | 0x00000009, true, "Start Error"},
+		{"EFI_SW_EC_BAD_DATE_TIME", EFI_SOFTWARE_UNSPECIFIED | 0x0000000A, true, "Bad Date Time"},
+		{"EFI_SW_EC_CFG_INVALID", EFI_SOFTWARE_UNSPECIFIED | 0x0000000B, true, "CFG Invalid"},
+		{"EFI_SW_EC_CFG_CLR_REQUEST", EFI_SOFTWARE_UNSPECIFIED | 0x0000000C, true, "CFG CLR Request"},
+		{"EFI_SW_EC_CFG_DEFAULT", EFI_SOFTWARE_UNSPECIFIED | 0x0000000D, true, "CFG Default"},
+		{"EFI_SW_EC_PWD_INVALID", EFI_SOFTWARE_UNSPECIFIED | 0x0000000E, true, "PWD Invalid"},
+		{"EFI_SW_EC_PWD_CLR_REQUEST", EFI_SOFTWARE_UNSPECIFIED | 0x0000000F, true, "PWD CLR Request"},
+		{"EFI_SW_EC_PWD_CLEARED", EFI_SOFTWARE_UNSPECIFIED | 0x00000010, true, "PWD Cleared"},
+		{"EFI_SW_EC_EVENT_LOG_FULL", EFI_SOFTWARE_UNSPECIFIED | 0x00000011, true, "Event Log Full"},
+		{"EFI_SW_EC_WRITE_PROTECTED", EFI_SOFTWARE_UNSPECIFIED | 0x00000012, true, "Write Protected"},
+		{"EFI_SW_EC_FV_CORRUPTED", EFI_SOFTWARE_UNSPECIFIED | 0x00000013, true, "FV Corrupted"},
+		{"EFI_SW_EC_INCONSISTENT_MEMORY_MAP", EFI_SOFTWARE_UNSPECIFIED | 0x00000014, true, "Inconsistent Memory Map"},
+
+		// Software PEI Core subclass error codes
+		{"EFI_SW_PEI_CORE_EC_DXE_CORRUPT", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "DXE Core Corrupt"},
+		{"EFI_SW_PEI_CORE_EC_DXEIPL_NOT_FOUND", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "DXEIPL Not Found"},
+		{"EFI_SW_PEI_CORE_EC_MEMORY_NOT_INSTALLED", EFI_SOFTWARE_PEI_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Memory Not Installed"},
+
+		// Software PEI Module subclass error codes
+		{"EFI_SW_PEI_EC_NO_RECOVERY_CAPSULE", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "No Recovery Capsule"},
+		{"EFI_SW_PEI_EC_INVALID_CAPSULE_DESCRIPTOR", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Invalid Capsule Descriptor"},
+		{"EFI_SW_PEI_EC_S3_RESUME_PPI_NOT_FOUND", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "S3 Resume PPI Not Found"},
+		{"EFI_SW_PEI_EC_S3_BOOT_SCRIPT_ERROR", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "S3 Boot Script Error"},
+		{"EFI_SW_PEI_EC_S3_OS_WAKE_ERROR", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000004, true, "S3 OS Wake Error"},
+		{"EFI_SW_PEI_EC_S3_RESUME_FAILED", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000005, true, "S3 Resume Failed"},
+		{"EFI_SW_PEI_EC_RECOVERY_PPI_NOT_FOUND", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000006, true, "Recovery PPI Not Found"},
+		{"EFI_SW_PEI_EC_RECOVERY_FAILED", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000007, true, "Recovery Failed"},
+		{"EFI_SW_PEI_EC_S3_RESUME_ERROR", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000008, true, "S3 Resume Error"},
+		{"EFI_SW_PEI_EC_INVALID_CAPSULE", EFI_SOFTWARE_PEI_MODULE | EFI_SUBCLASS_SPECIFIC | 0x00000009, true, "Invalid Capsule"},
+
+		// Software DXE Foundation subclass error codes
+		{"EFI_SW_DXE_CORE_EC_NO_ARCH", EFI_SOFTWARE_DXE_CORE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "No Arch"},
+
+		// Software DXE Boot Service Driver subclass error codes
+		{"EFI_SW_DXE_BS_EC_LEGACY_OPROM_NO_SPACE", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Legacy OpROM No Space"},
+		{"EFI_SW_DXE_BS_EC_INVALID_PASSWORD", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Invalid Password"},
+		{"EFI_SW_DXE_BS_EC_BOOT_OPTION_LOAD_ERROR", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000002, true, "Boot Option Load Error"},
+		{"EFI_SW_DXE_BS_EC_BOOT_OPTION_FAILED", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000003, true, "Boot Option Failed"},
+		{"EFI_SW_DXE_BS_EC_INVALID_IDE_PASSWORD", EFI_SOFTWARE_DXE_BS_DRIVER | EFI_SUBCLASS_SPECIFIC | 0x00000004, true, "Invalid IDE Password"},
+
+		// Software PEI Service subclass error codes
+		{"EFI_SW_PS_EC_RESET_NOT_AVAILABLE", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Reset Not Available"},
+		{"EFI_SW_PS_EC_MEMORY_INSTALLED_TWICE", EFI_SOFTWARE_PEI_SERVICE | EFI_SUBCLASS_SPECIFIC | 0x00000001, true, "Memory Installed Twice"},
+
+		// Software EBC Exception subclass error codes
+		{"EFI_SW_EC_EBC_UNDEFINED", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000000, true, "Undefined"},
+		{"EFI_SW_EC_EBC_DIVIDE_ERROR", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000001, true, "Divide Error"},
+		{"EFI_SW_EC_EBC_DEBUG", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000002, true, "Debug"},
+		{"EFI_SW_EC_EBC_BREAKPOINT", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000003, true, "Breakpoint"},
+		{"EFI_SW_EC_EBC_OVERFLOW", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000004, true, "Overflow"},
+		{"EFI_SW_EC_EBC_INVALID_OPCODE", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000005, true, "Invalid Opcode"},
+		{"EFI_SW_EC_EBC_STACK_FAULT", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000006, true, "Stack Fault"},
+		{"EFI_SW_EC_EBC_ALIGNMENT_CHECK", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000007, true, "Alignment Check"},
+		{"EFI_SW_EC_EBC_INSTRUCTION_ENCODING", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000008, true, "Instruction Encoding"},
+		{"EFI_SW_EC_EBC_BAD_BREAK", EFI_SOFTWARE_EBC_EXCEPTION | 0x00000009, true, "Bad Break"},
+		{"EFI_SW_EC_EBC_STEP", EFI_SOFTWARE_EBC_EXCEPTION | 0x0000000A, true, "Step"},
+
+		// Software IA32 Exception subclass error codes
+		{"EFI_SW_EC_IA32_DIVIDE_ERROR", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000000, true, "Divide Error"},
+		{"EFI_SW_EC_IA32_DEBUG", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000001, true, "Debug"},
+		{"EFI_SW_EC_IA32_NMI", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000002, true, "NMI"},
+		{"EFI_SW_EC_IA32_BREAKPOINT", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000003, true, "Breakpoint"},
+		{"EFI_SW_EC_IA32_OVERFLOW", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000004, true, "Overflow"},
+		{"EFI_SW_EC_IA32_BOUND", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000005, true, "Bound"},
+		{"EFI_SW_EC_IA32_INVALID_OPCODE", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000006, true, "Invalid Opcode"},
+		{"EFI_SW_EC_IA32_DOUBLE_FAULT", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000008, true, "Double Fault"},
+		{"EFI_SW_EC_IA32_INVALID_TSS", EFI_SOFTWARE_IA32_EXCEPTION | 0x0000000A, true, "Invalid TSS"},
+		{"EFI_SW_EC_IA32_SEG_NOT_PRESENT", EFI_SOFTWARE_IA32_EXCEPTION | 0x0000000B, true, "Segment Not Present"},
+		{"EFI_SW_EC_IA32_STACK_FAULT", EFI_SOFTWARE_IA32_EXCEPTION | 0x0000000C, true, "Stack Fault"},
+		{"EFI_SW_EC_IA32_GP_FAULT", EFI_SOFTWARE_IA32_EXCEPTION | 0x0000000D, true, "GP Fault"},
+		{"EFI_SW_EC_IA32_PAGE_FAULT", EFI_SOFTWARE_IA32_EXCEPTION | 0x0000000E, true, "Page Fault"},
+		{"EFI_SW_EC_IA32_FP_ERROR", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000010, true, "FP Error"},
+		{"EFI_SW_EC_IA32_ALIGNMENT_CHECK", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000011, true, "Alignment Check"},
+		{"EFI_SW_EC_IA32_MACHINE_CHECK", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000012, true, "Machine Check"},
+		{"EFI_SW_EC_IA32_SIMD", EFI_SOFTWARE_IA32_EXCEPTION | 0x00000013, true, "SIMD"},
+
+		// Software IPF Exception subclass error codes
+		{"EFI_SW_EC_IPF_ALT_DTLB", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000004, true, "ALT DTLB"},
+		{"EFI_SW_EC_IPF_DNESTED_TLB", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000005, true, "DNESTED TLB"},
+		{"EFI_SW_EC_IPF_BREAKPOINT", EFI_SOFTWARE_IPF_EXCEPTION | 0x0000000B, true, "Breakpoint"},
+		{"EFI_SW_EC_IPF_EXTERNAL_INTERRUPT", EFI_SOFTWARE_IPF_EXCEPTION | 0x0000000C, true, "External Interrupt"},
+		{"EFI_SW_EC_IPF_GEN_EXCEPT", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000018, true, "Gen Except"},
+		{"EFI_SW_EC_IPF_NAT_CONSUMPTION", EFI_SOFTWARE_IPF_EXCEPTION | 0x0000001A, true, "NAT Consumption"},
+		{"EFI_SW_EC_IPF_DEBUG_EXCEPT", EFI_SOFTWARE_IPF_EXCEPTION | 0x0000001D, true, "Debug Except"},
+		{"EFI_SW_EC_IPF_UNALIGNED_ACCESS", EFI_SOFTWARE_IPF_EXCEPTION | 0x0000001E, true, "Unaligned Access"},
+		{"EFI_SW_EC_IPF_FP_FAULT", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000020, true, "FP Fault"},
+		{"EFI_SW_EC_IPF_FP_TRAP", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000021, true, "FP Trap"},
+		{"EFI_SW_EC_IPF_TAKEN_BRANCH", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000023, true, "Taken Branch"},
+		{"EFI_SW_EC_IPF_SINGLE_STEP", EFI_SOFTWARE_IPF_EXCEPTION | 0x00000024, true, "Single Step"},
+
+		// Software X64 Exception subclass error codes
+		{"EFI_SW_EC_X64_DIVIDE_ERROR", EFI_SOFTWARE_X64_EXCEPTION | 0x00000000, true, "Divide Error"},
+		{"EFI_SW_EC_X64_DEBUG", EFI_SOFTWARE_X64_EXCEPTION | 0x00000001, true, "Debug"},
+		{"EFI_SW_EC_X64_NMI", EFI_SOFTWARE_X64_EXCEPTION | 0x00000002, true, "NMI"},
+		{"EFI_SW_EC_X64_BREAKPOINT", EFI_SOFTWARE_X64_EXCEPTION | 0x00000003, true, "Breakpoint"},
+		{"EFI_SW_EC_X64_OVERFLOW", EFI_SOFTWARE_X64_EXCEPTION | 0x00000004, true, "Overflow"},
+		{"EFI_SW_EC_X64_BOUND", EFI_SOFTWARE_X64_EXCEPTION | 0x00000005, true, "Bound"},
+		{"EFI_SW_EC_X64_INVALID_OPCODE", EFI_SOFTWARE_X64_EXCEPTION | 0x00000006, true, "Invalid Opcode"},
+		{"EFI_SW_EC_X64_DOUBLE_FAULT", EFI_SOFTWARE_X64_EXCEPTION | 0x00000008, true, "Double Fault"},
+		{"EFI_SW_EC_X64_INVALID_TSS", EFI_SOFTWARE_X64_EXCEPTION | 0x0000000A, true, "Invalid TSS"},
+		{"EFI_SW_EC_X64_SEG_NOT_PRESENT", EFI_SOFTWARE_X64_EXCEPTION | 0x0000000B, true, "Segment Not Present"},
+		{"EFI_SW_EC_X64_STACK_FAULT", EFI_SOFTWARE_X64_EXCEPTION | 0x0000000C, true, "Stack Fault"},
+		{"EFI_SW_EC_X64_GP_FAULT", EFI_SOFTWARE_X64_EXCEPTION | 0x0000000D, true, "GP Fault"},
+		{"EFI_SW_EC_X64_PAGE_FAULT", EFI_SOFTWARE_X64_EXCEPTION | 0x0000000E, true, "Page Fault"},
+		{"EFI_SW_EC_X64_FP_ERROR", EFI_SOFTWARE_X64_EXCEPTION | 0x00000010, true, "FP Error"},
+		{"EFI_SW_EC_X64_ALIGNMENT_CHECK", EFI_SOFTWARE_X64_EXCEPTION | 0x00000011, true, "Alignment Check"},
+		{"EFI_SW_EC_X64_MACHINE_CHECK", EFI_SOFTWARE_X64_EXCEPTION | 0x00000012, true, "Machine Check"},
+		{"EFI_SW_EC_X64_SIMD", EFI_SOFTWARE_X64_EXCEPTION | 0x00000013, true, "SIMD"},
+
+		// Software ARM Exception subclass error codes
+		{"EFI_SW_EC_ARM_RESET", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000000, true, "Reset"},
+		{"EFI_SW_EC_ARM_UNDEFINED", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000001, true, "Undefined Instruction"},
+		{"EFI_SW_EC_ARM_SOFTWARE_INTERRUPT", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000002, true, "Software Interrupt"},
+		{"EFI_SW_EC_ARM_PREFETCH_ABORT", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000003, true, "Prefetch Abort"},
+		{"EFI_SW_EC_ARM_DATA_ABORT", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000004, true, "Data Abort"},
+		{"EFI_SW_EC_ARM_RESERVED", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000005, true, "Reserved"},
+		{"EFI_SW_EC_ARM_IRQ", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000006, true, "IRQ"},
+		{"EFI_SW_EC_ARM_FIQ", EFI_SOFTWARE_ARM_EXCEPTION | 0x00000007, true, "FIQ"},
+
+		// the subclasses without specific operations only use the
+		// common ones
+		{"EFI_IOB_PC_INIT", EFI_IO_BUS_IBA | 0x00000000, false, "Init"},
+		{"EFI_IOB_PC_DETECT", EFI_IO_BUS_AGP | 0x00000003, false, "Detect"},
+		{"EFI_IOB_PC_HOTPLUG", EFI_IO_BUS_PC_CARD | 0x00000006, false, "Hotplug"},
+		{"EFI_IOB_EC_CONTROLLER_ERROR", EFI_IO_BUS_FC | 0x00000006, true, "Controller Error"},
+		{"EFI_IOB_EC_NOT_DETECTED", EFI_IO_BUS_IP_NETWORK | 0x00000003, true, "Not Detected"},
+		{"EFI_SUBCLASS_SPECIFIC", EFI_IO_BUS_IP_NETWORK | EFI_SUBCLASS_SPECIFIC | 0x00000000, false, "Unknown"},
+		{"EFI_SUBCLASS_SPECIFIC", EFI_PERIPHERAL_TPM | EFI_SUBCLASS_SPECIFIC | 0x00000000, true, "Unknown"},
+
+		// exception types reported as subclass specific operations
+		{"EFI_SW_EC_X64_PAGE_FAULT", EFI_SOFTWARE_X64_EXCEPTION | EFI_SUBCLASS_SPECIFIC | 0x0000000E, true, "Page Fault"},
+		{"EFI_SW_EC_ARM_DATA_ABORT", EFI_SOFTWARE_ARM_EXCEPTION | EFI_SUBCLASS_SPECIFIC | 0x00000004, true, "Data Abort"},
+
+		{"EFI_OEM_SPECIFIC", EFI_COMPUTING_UNIT_HOST_PROCESSOR | EFI_OEM_SPECIFIC | 0x00000001, false, "OEM Specific Progress Code"},
+		{"EFI_OEM_SPECIFIC", EFI_SOFTWARE_DXE_CORE | EFI_OEM_SPECIFIC | 0x00000001, true, "OEM Specific Error Code"},
+	}
+	for _, tt := range tests {
+		if _, _, operation := describeStatusValue(decodeStatusValue(tt.value), tt.isError); operation != tt.operation {
+			t.Errorf("%s (0x%08X) operation = %q, want %q", tt.macro, tt.value, operation, tt.operation)
+		}
+	}
+}
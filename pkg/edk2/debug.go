@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Below are the fatal messages printed by the EDK2 DebugLib and
+// CpuExceptionHandlerLib, which stop the boot without a status code:
+//
+// ASSERT [PciBusDxe] /build/MdeModulePkg/Bus/Pci/PciBusDxe/PciLib.c(123): !(((INTN)(RETURN_STATUS)(Status)) < 0)
+// ASSERT /build/MdePkg/Library/BaseLib/String.c(42): String != ((void *) 0)
+// !!!! X64 Exception Type - 0E(#PF - Page-Fault)  CPU Apic ID - 00000000 !!!!
+// Synchronous Exception at 0x000000007F1C5A2C
+//
+// and the message of the ResetSystem PEIM and driver, ending a boot:
+//
+// DXE ResetSystem2: ResetType Cold, Call Depth = 1.
+//
+
+var (
+	assertRegex        = regexp.MustCompile(`^ASSERT (?:\[([^\]]+)\] )?(.+)\((\d+)\): (.*)$`)
+	exceptionRegex     = regexp.MustCompile(`^!!!! (X64|IA32) Exception Type - ([0-9A-Fa-f]+)\((.*?)\)\s+CPU Apic ID - ([0-9A-Fa-f]+) !!!!`)
+	syncExceptionRegex = regexp.MustCompile(`^Synchronous Exception at (0x[0-9A-Fa-f]+)`)
+	resetRegex         = regexp.MustCompile(`^(PEI|DXE) ResetSystem2: ResetType (\w+)`)
+)
+
+// IsDebugLine reports whether the line is an EDK2 assert, CPU
+// exception or reset message.
+func IsDebugLine(line string) bool {
+	switch {
+	case strings.HasPrefix(line, "ASSERT "):
+		return assertRegex.MatchString(line)
+	case strings.HasPrefix(line, "!!!! "):
+		return exceptionRegex.MatchString(line)
+	case strings.HasPrefix(line, "Synchronous Exception at "):
+		return syncExceptionRegex.MatchString(line)
+	case strings.HasPrefix(line, "PEI ResetSystem2: "), strings.HasPrefix(line, "DXE ResetSystem2: "):
+		return resetRegex.MatchString(line)
+	}
+
+	return false
+}
+
+// DecodeDebugLine decodes an EDK2 assert, CPU exception or reset
+// message.
+func DecodeDebugLine(line string) (decoder.Record, error) {
+	if m := resetRegex.FindStringSubmatch(line); m != nil {
+		return decoder.Record{
+			Decoder:   "edk2",
+			Line:      line,
+			Kind:      decoder.KindInfo,
+			Boundary:  decoder.BoundaryReset,
+			Class:     "EDK2",
+			Subclass:  m[1] + " Reset System",
+			Operation: m[2] + " Reset",
+		}, nil
+	}
+
+	record := decoder.Record{
+		Decoder:  "edk2",
+		Line:     line,
+		Kind:     decoder.KindError,
+		Severity: decoder.SeverityUnrecovered,
+		Class:    "EDK2",
+	}
+
+	if m := assertRegex.FindStringSubmatch(line); m != nil {
+		record.Subclass = "Assert"
+		record.Operation = m[4]
+		record.Module = m[1]
+		record.Fields = []decoder.Field{
+			{Name: "File", Value: m[2]},
+			{Name: "Line", Value: m[3]},
+		}
+		return record, nil
+	}
+
+	if m := exceptionRegex.FindStringSubmatch(line); m != nil {
+		record.Subclass = m[1] + " CPU Exception"
+		record.Operation = m[3]
+		record.Fields = []decoder.Field{
+			{Name: "Vector", Value: "0x" + m[2]},
+			{Name: "APIC ID", Value: "0x" + m[4]},
+		}
+		return record, nil
+	}
+
+	if m := syncExceptionRegex.FindStringSubmatch(line); m != nil {
+		record.Subclass = "AArch64 CPU Exception"
+		record.Operation = "Synchronous Exception"
+		record.Fields = []decoder.Field{{Name: "ELR", Value: m[1]}}
+		return record, nil
+	}
+
+	return decoder.Record{}, fmt.Errorf("not an assert, exception or reset line: %q", line)
+}
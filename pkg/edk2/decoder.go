@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Error Severity to common severity mappings
+var errorSeverityLevel = map[uint8]decoder.Severity{
+	0x40: decoder.SeverityMinor,
+	0x80: decoder.SeverityMajor,
+	0x90: decoder.SeverityUnrecovered,
+	0xA0: decoder.SeverityUncontained,
+}
+
+// Status Type to record kind mappings
+var statusTypeKind = map[uint8]decoder.Kind{
+	EFI_PROGRESS_CODE: decoder.KindProgress,
+	EFI_ERROR_CODE:    decoder.KindError,
+	EFI_DEBUG_CODE:    decoder.KindDebug,
+}
+
+// Software subclass to boot phase mappings. The service subclasses
+// are reported from several phases and take the phase of the codes
+// around them.
+var softwareSubclassPhase = map[uint8]decoder.Phase{
+	0x01: decoder.PhaseSEC, // SEC
+	0x02: decoder.PhasePEI, // PEI Core
+	0x03: decoder.PhasePEI, // PEI Driver
+	0x04: decoder.PhaseDXE, // DXE Core
+	0x05: decoder.PhaseDXE, // DXE Boot Driver
+	0x06: decoder.PhaseDXE, // DXE Runtime Driver
+	0x07: decoder.PhaseDXE, // SMM Driver
+	0x08: decoder.PhaseBDS, // EFI Application
+	0x09: decoder.PhaseBDS, // OS Loader
+	0x0A: decoder.PhaseOS,  // Runtime
+	0x0B: decoder.PhaseOS,  // Afterlife
+}
+
+// Status code values marking a phase change within a subclass
+var statusValuePhase = map[uint32]decoder.Phase{
+	0x03041001: decoder.PhaseBDS, // DXE Core Handoff To Next (BDS entry)
+	0x03051001: decoder.PhaseBDS, // DXE BS Ready To Boot Event
+	0x03051007: decoder.PhaseBDS, // DXE BS Attempt Boot Order Event
+	0x03051003: decoder.PhaseOS,  // DXE BS Exit Boot Services Event
+	0x03101019: decoder.PhaseOS,  // EFI BS Exit Boot Services
+}
+
+// Status code values marking a boot boundary: the entry codes of SEC
+// and PEI Core start a boot, the reset services end it
+var statusValueBoundary = map[uint32]decoder.Boundary{
+	0x03010000: decoder.BoundaryBootStart, // SEC Init
+	0x03010002: decoder.BoundaryBootStart, // SEC Init Begin
+	0x03011000: decoder.BoundaryBootStart, // SEC Entry Point
+	0x03020000: decoder.BoundaryBootStart, // PEI Core Init
+	0x03021000: decoder.BoundaryBootStart, // PEI Core Entry Point
+	0x030F1010: decoder.BoundaryReset,     // PEI Service Reset System
+	0x0311100A: decoder.BoundaryReset,     // EFI RS Reset System
+}
+
+// Decoder decodes the status code lines printed by the EDK2
+// StatusCodeHandler.
+type Decoder struct{}
+
+func init() {
+	decoder.RegisterPriority(Decoder{}, decoder.PrioritySpecific)
+}
+
+func (Decoder) Name() string {
+	return "edk2"
+}
+
+func (Decoder) Match(line string) bool {
+	return strings.HasPrefix(line, "PROGRESS CODE:") || IsErrorCodeLine(line) || IsDebugLine(line)
+}
+
+// IsErrorCodeLine reports whether the line is an EDK2 error code line,
+// "ERROR: C<type>:V<value> ...", as opposed to other firmware's error
+// messages such as TF-A's "ERROR:   BL2: Failed to load image".
+func IsErrorCodeLine(line string) bool {
+	rest, ok := strings.CutPrefix(line, "ERROR:")
+	if !ok {
+		return false
+	}
+
+	field, _, _ := strings.Cut(strings.TrimLeft(rest, " "), " ")
+	typeString, valueString, ok := strings.Cut(field, ":")
+	if !ok || len(typeString) < 2 || len(valueString) < 2 || typeString[0] != 'C' || valueString[0] != 'V' {
+		return false
+	}
+
+	return isHex(typeString[1:]) && isHex(valueString[1:])
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	if IsDebugLine(line) {
+		return DecodeDebugLine(line)
+	}
+
+	code, err := ParseStatusCodeLine(line)
+	if err != nil {
+		return decoder.Record{}, err
+	}
+
+	return code.Record(), nil
+}
+
+// Record converts the status code into a decoded record.
+func (c StatusCode) Record() decoder.Record {
+	desc := DescribeStatusCode(c)
+
+	return decoder.Record{
+		Decoder:   "edk2",
+		Line:      c.String(),
+		Kind:      statusTypeKind[c.Type.Type],
+		Severity:  errorSeverityLevel[c.Type.Severity],
+		Phase:     c.Phase(),
+		Boundary:  statusValueBoundary[c.Value.Uint32()],
+		Code:      uint64(c.Value.Uint32()),
+		Instance:  c.Instance,
+		Class:     desc.Class,
+		Subclass:  desc.Subclass,
+		Operation: desc.Operation,
+		Module:    c.CallerID,
+	}
+}
+
+// Phase returns the boot phase the status code belongs to, or
+// decoder.PhaseUnknown if the code may be reported from any phase.
+func (c StatusCode) Phase() decoder.Phase {
+	if phase, ok := statusValuePhase[c.Value.Uint32()]; ok {
+		return phase
+	}
+
+	if c.Value.Class != 0x03 { // Software
+		return decoder.PhaseUnknown
+	}
+
+	return softwareSubclassPhase[c.Value.Subclass]
+}
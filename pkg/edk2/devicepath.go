@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf16"
+)
+
+// A device path is a sequence of nodes, each starting with a 4-byte
+// header, and ends with an End Entire Device Path node:
+//
+// ┌──────────┬─────────────┬─────────────┬──────────────┐
+// │ Type (1) │ SubType (1) │ Length (2)  │ Data         │ ... 7F FF 04 00
+// └──────────┴─────────────┴─────────────┴──────────────┘
+//
+// The text form follows the UEFI Device Path to Text conversion, e.g.
+// PciRoot(0x0)/Pci(0x1F,0x2)/Sata(0x0,0xFFFF,0x0)/HD(1,GPT,...)
+//
+// Reference: UEFI Specification, 10 - Device Path Protocol
+//
+
+// Device path node types
+const (
+	hardwareDevicePath  uint8 = 0x01
+	acpiDevicePath      uint8 = 0x02
+	messagingDevicePath uint8 = 0x03
+	mediaDevicePath     uint8 = 0x04
+	bbsDevicePath       uint8 = 0x05
+	endDevicePath       uint8 = 0x7F
+)
+
+// Device path end node subtypes
+const (
+	endInstanceDevicePath uint8 = 0x01
+	endEntireDevicePath   uint8 = 0xFF
+)
+
+// UART parity and stop bits mappings
+var (
+	uartParityDesc   = []string{"D", "N", "E", "O", "M", "S"}
+	uartStopBitsDesc = []string{"D", "1", "1.5", "2"}
+)
+
+// DevicePathString converts a binary device path to its text form. The
+// path must end with an End Entire Device Path node, or at the end of
+// the data.
+func DevicePathString(data []byte) (string, error) {
+	var b strings.Builder
+
+	separator := ""
+	for offset := 0; offset < len(data); {
+		if offset+4 > len(data) {
+			return "", fmt.Errorf("truncated device path node at offset %d", offset)
+		}
+		nodeType, subType := data[offset], data[offset+1]
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		if length < 4 || offset+length > len(data) {
+			return "", fmt.Errorf("invalid device path node length %d at offset %d", length, offset)
+		}
+		node := data[offset+4 : offset+length]
+		offset += length
+
+		if nodeType == endDevicePath {
+			switch subType {
+			case endEntireDevicePath:
+				return b.String(), nil
+			case endInstanceDevicePath:
+				b.WriteString(",")
+				separator = ""
+				continue
+			}
+		}
+
+		b.WriteString(separator)
+		b.WriteString(deviceNodeString(nodeType, subType, node))
+		separator = "/"
+	}
+
+	if b.Len() == 0 {
+		return "", fmt.Errorf("empty device path")
+	}
+
+	return b.String(), nil
+}
+
+// IsDevicePath reports whether the data holds a well-formed device path
+// ending with an End Entire Device Path node
+func IsDevicePath(data []byte) bool {
+	for offset := 0; offset+4 <= len(data); {
+		length := int(binary.LittleEndian.Uint16(data[offset+2:]))
+		if length < 4 || offset+length > len(data) {
+			return false
+		}
+		if data[offset] == endDevicePath && data[offset+1] == endEntireDevicePath {
+			return offset+length == len(data) && offset > 0
+		}
+		offset += length
+	}
+
+	return false
+}
+
+func deviceNodeString(nodeType, subType uint8, node []byte) string {
+	u8 := func(i int) uint8 { return node[i] }
+	u16 := func(i int) uint16 { return binary.LittleEndian.Uint16(node[i:]) }
+	u32 := func(i int) uint32 { return binary.LittleEndian.Uint32(node[i:]) }
+	u64 := func(i int) uint64 { return binary.LittleEndian.Uint64(node[i:]) }
+	guid := func(i int) GUID {
+		var g GUID
+		copy(g[:], node[i:i+16])
+		return g
+	}
+
+	switch {
+	case nodeType == hardwareDevicePath && subType == 0x01 && len(node) >= 2:
+		return fmt.Sprintf("Pci(0x%X,0x%X)", u8(1), u8(0))
+	case nodeType == hardwareDevicePath && subType == 0x02 && len(node) >= 1:
+		return fmt.Sprintf("PcCard(0x%X)", u8(0))
+	case nodeType == hardwareDevicePath && subType == 0x03 && len(node) >= 20:
+		return fmt.Sprintf("MemoryMapped(0x%X,0x%X,0x%X)", u32(0), u64(4), u64(12))
+	case nodeType == hardwareDevicePath && subType == 0x04 && len(node) >= 16:
+		return vendorNodeString("VenHw", guid(0), node[16:])
+	case nodeType == hardwareDevicePath && subType == 0x05 && len(node) >= 4:
+		return fmt.Sprintf("Ctrl(0x%X)", u32(0))
+	case nodeType == hardwareDevicePath && subType == 0x06 && len(node) >= 9:
+		return fmt.Sprintf("BMC(0x%X,0x%X)", u8(0), u64(1))
+
+	case nodeType == acpiDevicePath && subType == 0x01 && len(node) >= 8:
+		switch hid := u32(0); hid {
+		case 0x0A0341D0: // PNP0A03
+			return fmt.Sprintf("PciRoot(0x%X)", u32(4))
+		case 0x0A0841D0: // PNP0A08
+			return fmt.Sprintf("PcieRoot(0x%X)", u32(4))
+		default:
+			return fmt.Sprintf("Acpi(%s,0x%X)", eisaIDString(hid), u32(4))
+		}
+	case nodeType == acpiDevicePath && subType == 0x03 && len(node) >= 4:
+		return fmt.Sprintf("AcpiAdr(0x%X)", u32(0))
+
+	case nodeType == messagingDevicePath && subType == 0x01 && len(node) >= 4:
+		channel, drive := "Primary", "Master"
+		if u8(0) != 0 {
+			channel = "Secondary"
+		}
+		if u8(1) != 0 {
+			drive = "Slave"
+		}
+		return fmt.Sprintf("Ata(%s,%s,0x%X)", channel, drive, u16(2))
+	case nodeType == messagingDevicePath && subType == 0x02 && len(node) >= 4:
+		return fmt.Sprintf("Scsi(0x%X,0x%X)", u16(0), u16(2))
+	case nodeType == messagingDevicePath && subType == 0x03 && len(node) >= 20:
+		return fmt.Sprintf("Fibre(0x%X,0x%X)", u64(4), u64(12))
+	case nodeType == messagingDevicePath && subType == 0x05 && len(node) >= 2:
+		return fmt.Sprintf("USB(0x%X,0x%X)", u8(0), u8(1))
+	case nodeType == messagingDevicePath && subType == 0x0A && len(node) >= 16:
+		return vendorNodeString("VenMsg", guid(0), node[16:])
+	case nodeType == messagingDevicePath && subType == 0x0B && len(node) >= 33:
+		size := 32
+		if u8(32) == 0x00 || u8(32) == 0x01 {
+			// Ethernet
+			size = 6
+		}
+		return fmt.Sprintf("MAC(%X,0x%X)", node[:size], u8(32))
+	case nodeType == messagingDevicePath && subType == 0x0C && len(node) >= 8:
+		return fmt.Sprintf("IPv4(%s)", net.IP(node[4:8]))
+	case nodeType == messagingDevicePath && subType == 0x0D && len(node) >= 32:
+		return fmt.Sprintf("IPv6(%s)", net.IP(node[16:32]))
+	case nodeType == messagingDevicePath && subType == 0x0E && len(node) >= 15:
+		parity, stopBits := fmt.Sprint(u8(13)), fmt.Sprint(u8(14))
+		if int(u8(13)) < len(uartParityDesc) {
+			parity = uartParityDesc[u8(13)]
+		}
+		if int(u8(14)) < len(uartStopBitsDesc) {
+			stopBits = uartStopBitsDesc[u8(14)]
+		}
+		return fmt.Sprintf("Uart(%d,%d,%s,%s)", u64(4), u8(12), parity, stopBits)
+	case nodeType == messagingDevicePath && subType == 0x0F && len(node) >= 7:
+		return fmt.Sprintf("UsbClass(0x%X,0x%X,0x%X,0x%X,0x%X)", u16(0), u16(2), u8(4), u8(5), u8(6))
+	case nodeType == messagingDevicePath && subType == 0x12 && len(node) >= 6:
+		return fmt.Sprintf("Sata(0x%X,0x%X,0x%X)", u16(0), u16(2), u16(4))
+	case nodeType == messagingDevicePath && subType == 0x17 && len(node) >= 12:
+		eui := make([]string, 8)
+		for i := range eui {
+			// the EUI-64 is stored with its last byte first
+			eui[i] = fmt.Sprintf("%02X", node[11-i])
+		}
+		return fmt.Sprintf("NVMe(0x%X,%s)", u32(0), strings.Join(eui, "-"))
+	case nodeType == messagingDevicePath && subType == 0x18:
+		return fmt.Sprintf("Uri(%s)", node)
+	case nodeType == messagingDevicePath && subType == 0x1A && len(node) >= 1:
+		return fmt.Sprintf("SD(0x%X)", u8(0))
+	case nodeType == messagingDevicePath && subType == 0x1D && len(node) >= 1:
+		return fmt.Sprintf("eMMC(0x%X)", u8(0))
+
+	case nodeType == mediaDevicePath && subType == 0x01 && len(node) >= 38:
+		switch u8(37) {
+		case 0x01:
+			return fmt.Sprintf("HD(%d,MBR,0x%08X,0x%X,0x%X)", u32(0), u32(20), u64(4), u64(12))
+		case 0x02:
+			return fmt.Sprintf("HD(%d,GPT,%s,0x%X,0x%X)", u32(0), guid(20), u64(4), u64(12))
+		default:
+			return fmt.Sprintf("HD(%d,%d,0,0x%X,0x%X)", u32(0), u8(37), u64(4), u64(12))
+		}
+	case nodeType == mediaDevicePath && subType == 0x02 && len(node) >= 20:
+		return fmt.Sprintf("CDROM(0x%X,0x%X,0x%X)", u32(0), u64(4), u64(12))
+	case nodeType == mediaDevicePath && subType == 0x03 && len(node) >= 16:
+		return vendorNodeString("VenMedia", guid(0), node[16:])
+	case nodeType == mediaDevicePath && subType == 0x04:
+		return ucs2String(node)
+	case nodeType == mediaDevicePath && subType == 0x06 && len(node) >= 16:
+		return fmt.Sprintf("FvFile(%s)", guid(0))
+	case nodeType == mediaDevicePath && subType == 0x07 && len(node) >= 16:
+		return fmt.Sprintf("Fv(%s)", guid(0))
+	case nodeType == mediaDevicePath && subType == 0x08 && len(node) >= 20:
+		return fmt.Sprintf("Offset(0x%X,0x%X)", u64(4), u64(12))
+
+	case nodeType == bbsDevicePath && subType == 0x01 && len(node) >= 4:
+		return fmt.Sprintf("BBS(0x%X,%s,0x%X)", u16(0), asciiString(node[4:]), u16(2))
+	}
+
+	return fmt.Sprintf("Path(%d,%d,%X)", nodeType, subType, node)
+}
+
+func vendorNodeString(name string, guid GUID, data []byte) string {
+	if len(data) == 0 {
+		return fmt.Sprintf("%s(%s)", name, guid)
+	}
+
+	return fmt.Sprintf("%s(%s,%X)", name, guid, data)
+}
+
+// eisaIDString formats a compressed EISA ID, e.g. PNP0501
+func eisaIDString(id uint32) string {
+	vendor := uint16(id)
+	if vendor != 0x41D0 {
+		return fmt.Sprintf("0x%08X", id)
+	}
+
+	return fmt.Sprintf("PNP%04X", id>>16)
+}
+
+// ucs2String decodes a NUL-terminated UCS-2 string
+func ucs2String(data []byte) string {
+	chars := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		c := binary.LittleEndian.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+
+	return string(utf16.Decode(chars))
+}
+
+// asciiString decodes a NUL-terminated ASCII string
+func asciiString(data []byte) string {
+	if i := strings.IndexByte(string(data), 0); i >= 0 {
+		data = data[:i]
+	}
+
+	return string(data)
+}
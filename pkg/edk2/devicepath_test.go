@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"testing"
+)
+
+// Nodes of the device paths, as laid out by EDK2
+const (
+	pciRootNode = "02010C00 D041030A 00000000"                  // PciRoot(0x0)
+	endNode     = "7FFF0400"                                    // End Entire Device Path
+	sataNode    = "03120A00 0000 FFFF 0000"                     // Sata(0x0,0xFFFF,0x0)
+	uartNode    = "030E1300 00000000 00C2010000000000 08 01 01" // Uart(115200,8,N,1)
+)
+
+func TestDevicePathString(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"SATA", pciRootNode + "01010600 021F" + sataNode + endNode,
+			"PciRoot(0x0)/Pci(0x1F,0x2)/Sata(0x0,0xFFFF,0x0)"},
+		{"NVMe", pciRootNode + "01010600 001D" +
+			"03171000 01000000 C321B0915A382500" +
+			"04012A00 01000000 0008000000000000 0000100000000000 E9F6A315760D4D4D9C4C6E8B1B4B2E0A 02 02" + endNode,
+			"PciRoot(0x0)/Pci(0x1D,0x0)/NVMe(0x1,00-25-38-5A-91-B0-21-C3)/HD(1,GPT,15A3F6E9-0D76-4D4D-9C4C-6E8B1B4B2E0A,0x800,0x100000)"},
+		{"MBR partition", pciRootNode + "01010600 0001" + "03010800 00010000" +
+			"04012A00 02000000 0010000000000000 00A0000000000000 1E4B3C7A000000000000000000000000 01 01" + endNode,
+			"PciRoot(0x0)/Pci(0x1,0x0)/Ata(Primary,Slave,0x0)/HD(2,MBR,0x7A3C4B1E,0x1000,0xA000)"},
+		{"PCIe network", "02010C00 D041080A 01000000" + "01010600 0003" +
+			"030B2500 525400123456" + "0000000000000000000000000000000000000000000000000000" + "01" + endNode,
+			"PcieRoot(0x1)/Pci(0x3,0x0)/MAC(525400123456,0x1)"},
+		{"USB", pciRootNode + "01010600 0014" + "03050600 0300" + "030F0B00 FFFF FFFF 03 01 01" + endNode,
+			"PciRoot(0x0)/Pci(0x14,0x0)/USB(0x3,0x0)/UsbClass(0xFFFF,0xFFFF,0x3,0x1,0x1)"},
+		// the serial console and the display, separated by an End Instance
+		// node
+		{"console", pciRootNode + "01010600 001F" + "02010C00 D0410105 00000000" + uartNode +
+			"030A1400 6560A6DF19B4D3119A2D0090273FC14D" + "7F010400" +
+			pciRootNode + "01010600 0002" + "02030800 00010180" + endNode,
+			"PciRoot(0x0)/Pci(0x1F,0x0)/Acpi(PNP0501,0x0)/Uart(115200,8,N,1)/VenMsg(DFA66065-B419-11D3-9A2D-0090273FC14D)," +
+				"PciRoot(0x0)/Pci(0x2,0x0)/AcpiAdr(0x80010100)"},
+		// the UEFI shell of OVMF
+		{"firmware volume file", "04071400 C9BDB87CEBF8344FAAEA3EE4AF6516A1" +
+			"04061400 83A5047C3E9E1C4FAD65E05268D0B4D1" + endNode,
+			"Fv(7CB8BDC9-F8EB-4F34-AAEA-3EE4AF6516A1)/FvFile(7C04A583-9E3E-4F1C-AD65-E05268D0B4D1)"},
+		{"file path", "04041800" + "5C00 4500 4600 4900 5C00 4200 4F00 4F00 5400 0000" + endNode,
+			`\EFI\BOOT`},
+		{"vendor data", "01041800 C9BDB87CEBF8344FAAEA3EE4AF6516A1 AABBCCDD" + endNode,
+			"VenHw(7CB8BDC9-F8EB-4F34-AAEA-3EE4AF6516A1,AABBCCDD)"},
+		{"EISA ID", "02010C00 3412CDAB 02000000" + endNode, "Acpi(0xABCD1234,0x2)"},
+		{"BMC", "01060D00 01 001000FE00000000" + endNode, "BMC(0x1,0xFE001000)"},
+		// nodes without a text form, or too short for it
+		{"unknown node", "017F0600 ABCD" + endNode, "Path(1,127,ABCD)"},
+		{"short node", "01010500 1F" + endNode, "Path(1,1,1F)"},
+		// the path ends with the data
+		{"no end node", pciRootNode + "01010600 021F", "PciRoot(0x0)/Pci(0x1F,0x2)"},
+		// the nodes after the end are ignored
+		{"after the end", pciRootNode + endNode + "01010600 021F", "PciRoot(0x0)"},
+		{"end only", endNode, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DevicePathString(hexBytes(t, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DevicePathString() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevicePathStringInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"empty", "", "empty device path"},
+		{"truncated header", pciRootNode + "0101", "truncated device path node at offset 12"},
+		{"short length", "01010200" + endNode, "invalid device path node length 2 at offset 0"},
+		{"oversized length", pciRootNode + "0101FF00 021F" + endNode, "invalid device path node length 255 at offset 12"},
+		{"truncated node", "02010C00 D041030A", "invalid device path node length 12 at offset 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DevicePathString(hexBytes(t, tt.path)); err == nil || err.Error() != tt.want {
+				t.Errorf("DevicePathString() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDevicePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"SATA", pciRootNode + "01010600 021F" + sataNode + endNode, true},
+		{"instances", pciRootNode + "7F010400" + pciRootNode + endNode, true},
+		{"empty", "", false},
+		{"end only", endNode, false},
+		{"no end node", pciRootNode + "01010600 021F", false},
+		{"end instance only", pciRootNode + "7F010400", false},
+		{"trailing bytes", pciRootNode + endNode + "00", false},
+		{"short length", "01010000" + endNode, false},
+		{"oversized length", pciRootNode + "0101FF00 021F" + endNode, false},
+		// e.g. EFI_DEBUG_ASSERT_DATA
+		{"other data", "ED030000 28000000 10108A7E 00000000", false},
+	}
+	for _, tt := range tests {
+		if got := IsDevicePath(hexBytes(t, tt.path)); got != tt.want {
+			t.Errorf("IsDevicePath() of %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,642 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Below are definitions of the extended data of status codes
+//
+// REPORT_STATUS_CODE_WITH_EXTENDED_DATA() passes a typed structure with
+// the status code, starting with an EFI_STATUS_CODE_DATA header:
+//
+// ┌──────────────────────────┐
+// │ HeaderSize (2)           │  size of the header, 20
+// │ Size (2)                 │  size of the data following the header
+// │ Type (16)                │  GUID of the data format
+// ├──────────────────────────┤
+// │ Data                     │  EFI_DEBUG_ASSERT_DATA, EFI_DEVICE_PATH_
+// │ ...                      │  EXTENDED_DATA, EFI_STATUS_CODE_STRING_
+// └──────────────────────────┘  DATA, ...
+//
+// Most structures are of the EFI_STATUS_CODE_SPECIFIC_DATA type, and
+// their format depends on the status code they are reported with. The
+// data hub keeps the status codes with their data as a record:
+//
+// ┌──────────────────────────┐
+// │ CodeType (4)             │
+// │ Value (4)                │
+// │ Instance (4)             │
+// │ CallerId (16)            │
+// ├──────────────────────────┤
+// │ EFI_STATUS_CODE_DATA     │
+// └──────────────────────────┘
+//
+// The structures are laid out with the natural alignment of the
+// firmware, so the fields following a UINTN or a pointer move with the
+// word size. The data is decoded with the offsets of the 64-bit layout
+// first, and of the 32-bit layout if its size does not match.
+//
+// Reference: PI Specification, Volume 3, 6.6 - Status Code Data Types
+//            PI Specification, Volume 3, 6.7 - Status Code Specific Data
+//
+
+// Size of the EFI_STATUS_CODE_DATA header
+const statusCodeDataHeaderSize = 20
+
+// Size of a DATA_HUB_STATUS_CODE_DATA_RECORD before its data
+const statusCodeRecordHeaderSize = 28
+
+// Status code data type GUIDs
+var (
+	StatusCodeDataTypeStringGUID = MustParseGUID("92D11080-496F-4D95-BE7E-037488382B0A")
+	StatusCodeSpecificDataGUID   = MustParseGUID("335984BD-E805-409A-B8F8-D27ECE5FF7A6")
+	StatusCodeDataTypeDebugGUID  = MustParseGUID("9A4E9246-D553-11D5-87E2-00062945C3B9")
+)
+
+// Status code data type mappings
+var statusCodeDataTypeDesc = map[GUID]string{
+	StatusCodeDataTypeStringGUID: "String",
+	StatusCodeSpecificDataGUID:   "Specific Data",
+	StatusCodeDataTypeDebugGUID:  "Debug",
+}
+
+// EFI_STRING_TYPE mappings
+var stringTypeDesc = map[uint32]string{
+	0: "ASCII",
+	1: "Unicode",
+	2: "HII Token",
+}
+
+// represents the name of a flag bit
+type bitDesc struct {
+	bit  uint32
+	desc string
+}
+
+// EFI_CPU_CAUSE_* mappings of EFI_COMPUTING_UNIT_CPU_DISABLED_ERROR_DATA
+var cpuDisabledCauseDesc = []bitDesc{
+	{0x0001, "Internal Error"},
+	{0x0002, "Thermal Error"},
+	{0x0004, "Self-test Failure"},
+	{0x0008, "Pre-boot Timeout"},
+	{0x0010, "Failed to Start"},
+	{0x0020, "Configuration Error"},
+	{0x0080, "User Selection"},
+	{0x0100, "By Association"},
+	{0x8000, "Unspecified"},
+}
+
+// EFI_COMPUTING_UNIT_MISMATCH_* mappings of
+// EFI_HOST_PROCESSOR_MISMATCH_ERROR_DATA
+var processorMismatchDesc = []bitDesc{
+	{0x0001, "Speed"},
+	{0x0002, "FSB Speed"},
+	{0x0004, "Family"},
+	{0x0008, "Model"},
+	{0x0010, "Stepping"},
+	{0x0020, "Cache Size"},
+	{0x1000, "OEM1"},
+	{0x2000, "OEM2"},
+	{0x4000, "OEM3"},
+	{0x8000, "OEM4"},
+}
+
+// EFI_INIT_CACHE_TYPE mappings
+var initCacheTypeDesc = map[uint32]string{
+	0: "Data Only",
+	1: "Instruction Only",
+	2: "Data and Instruction",
+	3: "Unspecified",
+}
+
+// EFI_MEMORY_ERROR_GRANULARITY mappings
+var memoryErrorGranularityDesc = map[uint8]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Device",
+	0x04: "Partition",
+}
+
+// EFI_MEMORY_ERROR_OPERATION mappings
+var memoryErrorOperationDesc = map[uint8]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Read",
+	0x04: "Write",
+	0x05: "Partial Write",
+}
+
+// Debug print error level mappings, from MdePkg DebugLib.h
+var debugErrorLevelDesc = []bitDesc{
+	{0x00000001, "INIT"},
+	{0x00000002, "WARN"},
+	{0x00000004, "LOAD"},
+	{0x00000008, "FS"},
+	{0x00000010, "POOL"},
+	{0x00000020, "PAGE"},
+	{0x00000040, "INFO"},
+	{0x00000080, "DISPATCH"},
+	{0x00000100, "VARIABLE"},
+	{0x00000400, "BM"},
+	{0x00001000, "BLKIO"},
+	{0x00004000, "NET"},
+	{0x00010000, "UNDI"},
+	{0x00020000, "LOADFILE"},
+	{0x00080000, "EVENT"},
+	{0x00100000, "GCD"},
+	{0x00200000, "CACHE"},
+	{0x00400000, "VERBOSE"},
+	{0x00800000, "MANAGEABILITY"},
+	{0x80000000, "ERROR"},
+}
+
+// represents an EFI_STATUS_CODE_DATA structure
+type StatusCodeData struct {
+	HeaderSize uint16
+	Size       uint16
+	Type       GUID
+	// Data following the header, Size bytes
+	Data []byte
+}
+
+// ParseStatusCodeData parses an EFI_STATUS_CODE_DATA header and its data
+func ParseStatusCodeData(b []byte) (StatusCodeData, error) {
+	var d StatusCodeData
+
+	if len(b) < statusCodeDataHeaderSize {
+		return d, fmt.Errorf("status code data too short: %d bytes", len(b))
+	}
+	d.HeaderSize = binary.LittleEndian.Uint16(b[0:])
+	d.Size = binary.LittleEndian.Uint16(b[2:])
+	copy(d.Type[:], b[4:20])
+
+	if d.HeaderSize < statusCodeDataHeaderSize {
+		return d, fmt.Errorf("invalid status code data header size %d", d.HeaderSize)
+	}
+	end := int(d.HeaderSize) + int(d.Size)
+	if end > len(b) {
+		return d, fmt.Errorf("status code data truncated: %d of %d bytes", len(b), end)
+	}
+	d.Data = b[d.HeaderSize:end]
+
+	return d, nil
+}
+
+// ParseStatusCodeRecord parses a DATA_HUB_STATUS_CODE_DATA_RECORD: the
+// status code as reported, followed by its EFI_STATUS_CODE_DATA
+func ParseStatusCodeRecord(b []byte) (StatusCode, StatusCodeData, error) {
+	if len(b) < statusCodeRecordHeaderSize {
+		return StatusCode{}, StatusCodeData{}, fmt.Errorf("status code record too short: %d bytes", len(b))
+	}
+
+	var caller GUID
+	copy(caller[:], b[12:28])
+	code := StatusCode{
+		Type:     NewStatusCodeType(binary.LittleEndian.Uint32(b[0:])),
+		Value:    NewStatusCodeValue(binary.LittleEndian.Uint32(b[4:])),
+		Instance: binary.LittleEndian.Uint32(b[8:]),
+	}
+	if !caller.IsZero() {
+		code.CallerID = caller.String()
+	}
+
+	data, err := ParseStatusCodeData(b[statusCodeRecordHeaderSize:])
+
+	return code, data, err
+}
+
+// ParseHexDump converts a hex dump to bytes. It accepts the output of
+// the EDK2 InternalDumpHex() and of hexdump -C, e.g.
+//
+//	00000000: 14 00 10 00 BD 84 59 33-05 E8 9A 40 B8 F8 D2 7E  *......Y3...@...~*
+//	00000000  14 00 10 00 bd 84 59 33  05 e8 9a 40 b8 f8 d2 7e  |......Y3...@...~|
+//
+// as well as plain hex bytes. The offsets and ASCII columns are ignored.
+func ParseHexDump(s string) ([]byte, error) {
+	var data []byte
+
+	asciiColumn := false
+	for n, line := range strings.Split(s, "\n") {
+		for _, delim := range []string{"*", "|"} {
+			if i := strings.Index(line, delim); i >= 0 {
+				line = line[:i]
+				asciiColumn = true
+			}
+		}
+
+		fields := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ' ' || c == '\t' || c == '\r' || c == '-'
+		})
+		// the offsets of hexdump -C have no colon, and its last line
+		// holds the length of the data
+		if len(fields) > 0 && (strings.HasSuffix(fields[0], ":") || asciiColumn && len(fields[0]) == 8) {
+			fields = fields[1:]
+		}
+
+		for _, field := range fields {
+			b, err := hex.DecodeString(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid hex %q", n+1, field)
+			}
+			data = append(data, b...)
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no hex data")
+	}
+
+	return data, nil
+}
+
+// TypeName returns the name of the data format
+func (d StatusCodeData) TypeName() string {
+	if desc, ok := statusCodeDataTypeDesc[d.Type]; ok {
+		return desc
+	}
+
+	return d.Type.String()
+}
+
+// Fields decodes the data reported with a status code. The data of an
+// unknown format, or that does not match its format, is returned as hex.
+func (d StatusCodeData) Fields(code StatusCode) []decoder.Field {
+	fields := []decoder.Field{{Name: "Data Type", Value: d.TypeName()}}
+
+	var decoded []decoder.Field
+	switch d.Type {
+	case StatusCodeDataTypeStringGUID:
+		decoded = decodeStringData(d.Data)
+	case StatusCodeDataTypeDebugGUID:
+		decoded = decodeDebugData(d.Data)
+	case StatusCodeSpecificDataGUID:
+		decoded = decodeSpecificData(code, d.Data)
+	}
+	if decoded == nil && IsDevicePath(d.Data) {
+		// EFI_DEVICE_PATH_EXTENDED_DATA of a vendor type
+		decoded = decodeDevicePathData(d.Data)
+	}
+	if decoded == nil && len(d.Data) > 0 {
+		decoded = []decoder.Field{{Name: "Data", Value: fmt.Sprintf("% X", d.Data)}}
+	}
+
+	return append(fields, decoded...)
+}
+
+// decodeSpecificData decodes the EFI_STATUS_CODE_SPECIFIC_DATA types
+// by the status code they are defined for, or nil if the data does not
+// match the type of the code
+func decodeSpecificData(code StatusCode, data []byte) []decoder.Field {
+	value, isError := code.Value, code.Type.IsError()
+
+	switch {
+	case value.Class == 0x03 && isError && value.Operation == 0x0007:
+		// EFI_SW_EC_ILLEGAL_SOFTWARE_STATE
+		return decodeAssertData(data)
+
+	case value.Class == 0x00 && isError && value.Operation == 0x0001 && len(data) >= 5:
+		// EFI_CU_EC_DISABLED
+		return []decoder.Field{
+			{Name: "Cause", Value: describeBits(binary.LittleEndian.Uint32(data), cpuDisabledCauseDesc)},
+			{Name: "SW Disabled", Value: yesNo(data[4] != 0)},
+		}
+
+	case value.Class == 0x00 && value.Subclass == 0x01:
+		return decodeHostProcessorData(value.Operation, isError, data)
+
+	case value.Class == 0x00 && value.Subclass == 0x05:
+		return decodeMemoryData(value.Operation, isError, data)
+	}
+
+	if IsDevicePath(data) {
+		// EFI_DEVICE_PATH_EXTENDED_DATA
+		return decodeDevicePathData(data)
+	}
+
+	return nil
+}
+
+// decodeHostProcessorData decodes the EFI_COMPUTING_UNIT_* data of the
+// host processor codes
+func decodeHostProcessorData(op uint16, isError bool, data []byte) []decoder.Field {
+	switch {
+	case !isError && op == 0x1001 && len(data) == 8:
+		// EFI_CU_HP_PC_CACHE_INIT: EFI_CACHE_INIT_DATA
+		return []decoder.Field{
+			{Name: "Level", Value: fmt.Sprint(binary.LittleEndian.Uint32(data))},
+			{Name: "Type", Value: describeUint32(binary.LittleEndian.Uint32(data[4:]), initCacheTypeDesc)},
+		}
+	case isError && op == 0x1002 && len(data) >= 6:
+		// EFI_CU_HP_EC_MISMATCH: EFI_HOST_PROCESSOR_MISMATCH_ERROR_DATA
+		return []decoder.Field{
+			{Name: "Instance", Value: fmt.Sprint(binary.LittleEndian.Uint32(data))},
+			{Name: "Attributes", Value: describeBits(uint32(binary.LittleEndian.Uint16(data[4:])), processorMismatchDesc)},
+		}
+	case isError && op == 0x1003 && len(data) == 4:
+		// EFI_CU_HP_EC_TIMER_EXPIRED: EFI_COMPUTING_UNIT_TIMER_EXPIRED_ERROR_DATA
+		return []decoder.Field{{Name: "Timer Limit", Value: expBase10String(data) + " s"}}
+	case isError && op == 0x1006 && len(data) == 8:
+		// EFI_CU_HP_EC_THERMAL: EFI_COMPUTING_UNIT_THERMAL_ERROR_DATA
+		return []decoder.Field{
+			{Name: "Temperature", Value: expBase10String(data) + " °C"},
+			{Name: "Threshold", Value: expBase10String(data[4:]) + " °C"},
+		}
+	case isError && (op == 0x1007 || op == 0x1008) && len(data) == 8:
+		// EFI_CU_HP_EC_LOW_VOLTAGE, EFI_CU_HP_EC_HIGH_VOLTAGE:
+		// EFI_COMPUTING_UNIT_VOLTAGE_ERROR_DATA
+		return []decoder.Field{
+			{Name: "Voltage", Value: expBase10String(data) + " V"},
+			{Name: "Threshold", Value: expBase10String(data[4:]) + " V"},
+		}
+	case isError && op == 0x100A && len(data) == 4:
+		// EFI_CU_HP_EC_MICROCODE_UPDATE: EFI_COMPUTING_UNIT_MICROCODE_UPDATE_ERROR_DATA
+		return []decoder.Field{{Name: "Version", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint32(data))}}
+	}
+
+	return nil
+}
+
+// decodeMemoryData decodes the EFI_MEMORY_* data of the memory codes
+func decodeMemoryData(op uint16, isError bool, data []byte) []decoder.Field {
+	if !isError {
+		// EFI_CU_MEMORY_PC_TEST: EFI_MEMORY_RANGE_EXTENDED_DATA, with
+		// the start aligned to 8 bytes after the header
+		if op != 0x1006 {
+			return nil
+		}
+		switch len(data) {
+		case 20:
+			data = data[4:]
+		case 16:
+		default:
+			return nil
+		}
+		return []decoder.Field{
+			{Name: "Start", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint64(data))},
+			{Name: "Length", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint64(data[8:]))},
+		}
+	}
+
+	if op == 0x1006 && len(data) == 4 {
+		// EFI_CU_MEMORY_EC_MISMATCH: EFI_MEMORY_MODULE_MISMATCH_ERROR_DATA
+		return []decoder.Field{{Name: "DIMM", Value: dimmNumberString(data)}}
+	}
+
+	// EFI_MEMORY_EXTENDED_ERROR_DATA: Granularity, Operation, Syndrome
+	// (UINTN), Address and Resolution (UINTN)
+	var syndrome, address, resolution uint64
+	switch len(data) {
+	case 28: // 64-bit
+		syndrome = binary.LittleEndian.Uint64(data[4:])
+		address = binary.LittleEndian.Uint64(data[12:])
+		resolution = binary.LittleEndian.Uint64(data[20:])
+	case 24: // 32-bit, 64-bit values aligned to 8 bytes
+		syndrome = uint64(binary.LittleEndian.Uint32(data[4:]))
+		address = binary.LittleEndian.Uint64(data[12:])
+		resolution = uint64(binary.LittleEndian.Uint32(data[20:]))
+	case 20: // 32-bit, 64-bit values aligned to 4 bytes
+		syndrome = uint64(binary.LittleEndian.Uint32(data[4:]))
+		address = binary.LittleEndian.Uint64(data[8:])
+		resolution = uint64(binary.LittleEndian.Uint32(data[16:]))
+	default:
+		return nil
+	}
+
+	return []decoder.Field{
+		{Name: "Granularity", Value: describeUint8(data[0], memoryErrorGranularityDesc)},
+		{Name: "Operation", Value: describeUint8(data[1], memoryErrorOperationDesc)},
+		{Name: "Syndrome", Value: fmt.Sprintf("0x%X", syndrome)},
+		{Name: "Address", Value: fmt.Sprintf("0x%X", address)},
+		{Name: "Resolution", Value: fmt.Sprintf("0x%X", resolution)},
+	}
+}
+
+// decodeAssertData decodes EFI_DEBUG_ASSERT_DATA: the line number, the
+// size of the file name and a pointer to it. The EDK2 DebugLib copies
+// the file name and the description after the structure, so they are
+// looked for after a 64-bit pointer, a 32-bit pointer or none.
+func decodeAssertData(data []byte) []decoder.Field {
+	if len(data) < 8 {
+		return nil
+	}
+	line := binary.LittleEndian.Uint32(data)
+	size := int(binary.LittleEndian.Uint32(data[4:]))
+	strs := data[8:]
+
+	fields := []decoder.Field{{Name: "Line", Value: fmt.Sprint(line)}}
+	for _, offset := range []int{8, 4, 0} {
+		if size == 0 || offset+size > len(strs) {
+			continue
+		}
+		name, ok := printableString(strs[offset : offset+size])
+		if !ok || len(name)+1 != size {
+			continue
+		}
+
+		fields = append(fields, decoder.Field{Name: "File", Value: name})
+		if desc, ok := printableString(strs[offset+size:]); ok {
+			fields = append(fields, decoder.Field{Name: "Description", Value: desc})
+		}
+		return fields
+	}
+
+	return nil
+}
+
+// decodeStringData decodes EFI_STATUS_CODE_STRING_DATA: the string type
+// and a pointer to the string, or the HII handle and token. A string
+// copied after the structure is decoded, otherwise its address is given.
+func decodeStringData(data []byte) []decoder.Field {
+	if len(data) < 8 {
+		return nil
+	}
+	stringType := binary.LittleEndian.Uint32(data)
+	rest := data[4:]
+
+	fields := []decoder.Field{{Name: "String Type", Value: describeUint32(stringType, stringTypeDesc)}}
+	switch stringType {
+	case 0, 1:
+		// after a 32-bit pointer first: the high half of a 64-bit
+		// pointer holds no printable string, while a string after a
+		// 32-bit pointer would be cut by the other offset
+		for _, offset := range []int{4, 8} {
+			if offset >= len(rest) {
+				continue
+			}
+			if s, ok := printableString(rest[offset:]); ok && stringType == 0 {
+				return append(fields, decoder.Field{Name: "String", Value: s})
+			}
+			if s := ucs2String(rest[offset:]); stringType == 1 && s != "" && isPrintable(s) {
+				return append(fields, decoder.Field{Name: "String", Value: s})
+			}
+		}
+		return append(fields, decoder.Field{Name: "String Address", Value: pointerString(rest)})
+	case 2:
+		// EFI_STRING_TOKEN: the token follows the handle
+		switch {
+		case len(rest) >= 10:
+			return append(fields,
+				decoder.Field{Name: "HII Handle", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint64(rest))},
+				decoder.Field{Name: "Token", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint16(rest[8:]))})
+		case len(rest) >= 6:
+			return append(fields,
+				decoder.Field{Name: "HII Handle", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint32(rest))},
+				decoder.Field{Name: "Token", Value: fmt.Sprintf("0x%X", binary.LittleEndian.Uint16(rest[4:]))})
+		}
+	}
+
+	return nil
+}
+
+// decodeDebugData decodes the EFI_DEBUG_INFO of the EDK2 DebugLib: the
+// error level, followed by the arguments, 12 UINT64, and the format
+func decodeDebugData(data []byte) []decoder.Field {
+	const formatOffset = 4 + 12*8
+
+	if len(data) < formatOffset {
+		return nil
+	}
+	fields := []decoder.Field{{Name: "Error Level", Value: describeBits(binary.LittleEndian.Uint32(data), debugErrorLevelDesc)}}
+	if format, ok := printableString(data[formatOffset:]); ok {
+		fields = append(fields, decoder.Field{Name: "Format", Value: strings.TrimRight(format, "\r\n")})
+	}
+
+	return fields
+}
+
+// decodeDevicePathData decodes EFI_DEVICE_PATH_EXTENDED_DATA
+func decodeDevicePathData(data []byte) []decoder.Field {
+	path, err := DevicePathString(data)
+	if err != nil {
+		return nil
+	}
+
+	return []decoder.Field{{Name: "Device Path", Value: path}}
+}
+
+// expBase10String formats an EFI_EXP_BASE10_DATA: an INT16 value and
+// an INT16 power of ten, e.g. 95 and -2 as 0.95
+func expBase10String(data []byte) string {
+	value := int16(binary.LittleEndian.Uint16(data))
+	exponent := int(int16(binary.LittleEndian.Uint16(data[2:])))
+
+	if value == 0 {
+		return "0"
+	}
+	sign, digits := "", strconv.Itoa(int(value))
+	if value < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if exponent >= 0 {
+		if exponent > 20 {
+			return fmt.Sprintf("%de%d", value, exponent)
+		}
+		return sign + digits + strings.Repeat("0", exponent)
+	}
+
+	if len(digits) <= -exponent {
+		digits = strings.Repeat("0", -exponent-len(digits)+1) + digits
+	}
+	point := len(digits) + exponent
+	fraction := strings.TrimRight(digits[point:], "0")
+	if fraction == "" {
+		return sign + digits[:point]
+	}
+
+	return sign + digits[:point] + "." + fraction
+}
+
+// dimmNumberString formats an EFI_STATUS_CODE_DIMM_NUMBER
+func dimmNumberString(data []byte) string {
+	array := binary.LittleEndian.Uint16(data)
+	device := binary.LittleEndian.Uint16(data[2:])
+
+	return fmt.Sprintf("Array %d, Device %d", array, device)
+}
+
+// pointerString formats the pointer at the start of the data, 64-bit if
+// the data is long enough
+func pointerString(data []byte) string {
+	if len(data) >= 8 {
+		return fmt.Sprintf("0x%X", binary.LittleEndian.Uint64(data))
+	}
+
+	return fmt.Sprintf("0x%X", binary.LittleEndian.Uint32(data))
+}
+
+// printableString returns the NUL-terminated string at the start of the
+// data, and whether it is a non-empty printable ASCII string
+func printableString(data []byte) (string, bool) {
+	end := bytes.IndexByte(data, 0)
+	if end <= 0 {
+		return "", false
+	}
+	s := string(data[:end])
+
+	return s, isPrintable(s)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+func isPrintable(s string) bool {
+	for _, c := range s {
+		if (c < 0x20 || c > 0x7E) && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describeUint8(value uint8, desc map[uint8]string) string {
+	if d, ok := desc[value]; ok {
+		return d
+	}
+
+	return fmt.Sprintf("Unknown (0x%X)", value)
+}
+
+func describeUint32(value uint32, desc map[uint32]string) string {
+	if d, ok := desc[value]; ok {
+		return d
+	}
+
+	return fmt.Sprintf("Unknown (0x%X)", value)
+}
+
+// describeBits lists the names of the bits set in a value, followed by
+// the unnamed bits
+func describeBits(value uint32, desc []bitDesc) string {
+	var names []string
+	rest := value
+	for _, d := range desc {
+		if value&d.bit != 0 {
+			names = append(names, d.desc)
+			rest &^= d.bit
+		}
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("0x%X", rest))
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+
+	return fmt.Sprintf("0x%X (%s)", value, strings.Join(names, ", "))
+}
@@ -0,0 +1,395 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// An ASSERT() of PciBusDxe, as kept by the data hub and dumped by
+// InternalDumpHex(): an unrecovered EFI_SW_EC_ILLEGAL_SOFTWARE_STATE
+// error with its EFI_DEBUG_ASSERT_DATA, on a 64-bit firmware
+const assertRecordDump = `00000000: 02 00 00 90 07 00 05 03-00 00 00 00 04 00 B8 93  *................*
+00000010: B3 9F D4 11 9A 3A 00 90-27 3F C1 4D 14 00 5F 00  *.....:..'?.M.._.*
+00000020: BD 84 59 33 05 E8 9A 40-B8 F8 D2 7E CE 5F F7 A6  *..Y3...@...~._..*
+00000030: ED 03 00 00 28 00 00 00-10 10 8A 7E 00 00 00 00  *....(......~....*
+00000040: 4D 64 65 4D 6F 64 75 6C-65 50 6B 67 2F 42 75 73  *MdeModulePkg/Bus*
+00000050: 2F 50 63 69 2F 50 63 69-42 75 73 44 78 65 2F 50  */Pci/PciBusDxe/P*
+00000060: 63 69 4C 69 62 2E 63 00-21 28 28 28 49 4E 54 4E  *ciLib.c.!(((INTN*
+00000070: 29 28 52 45 54 55 52 4E-5F 53 54 41 54 55 53 29  *)(RETURN_STATUS)*
+00000080: 28 53 74 61 74 75 73 29-29 20 3C 20 30 29 00     *(Status)) < 0).*
+`
+
+// hexBytes converts hex digits, spaces ignored, to bytes
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// le encodes the values in little-endian order
+func le(values ...any) []byte {
+	var b bytes.Buffer
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		case []byte:
+			b.Write(v)
+		case GUID:
+			b.Write(v[:])
+		default:
+			binary.Write(&b, binary.LittleEndian, v)
+		}
+	}
+
+	return b.Bytes()
+}
+
+// ucs2 encodes a NUL-terminated UCS-2 string
+func ucs2(s string) []byte {
+	return le(append(utf16.Encode([]rune(s)), 0))
+}
+
+// statusCodeData prefixes the data with an EFI_STATUS_CODE_DATA header
+func statusCodeData(guid GUID, data []byte) []byte {
+	return le(uint16(statusCodeDataHeaderSize), uint16(len(data)), guid, data)
+}
+
+func fieldStrings(fields []decoder.Field) []string {
+	var s []string
+	for _, f := range fields {
+		s = append(s, f.Name+": "+f.Value)
+	}
+
+	return s
+}
+
+func TestParseStatusCodeData(t *testing.T) {
+	data := []byte("PciBus\x00")
+
+	tests := []struct {
+		name    string
+		b       []byte
+		want    []byte
+		wantErr string
+	}{
+		{"string", statusCodeData(StatusCodeDataTypeStringGUID, data), data, ""},
+		{"no data", statusCodeData(StatusCodeDataTypeStringGUID, nil), []byte{}, ""},
+		// the bytes after Size are not part of the data
+		{"trailing bytes", append(statusCodeData(StatusCodeDataTypeStringGUID, data), 0xAA, 0xBB), data, ""},
+		// a header larger than EFI_STATUS_CODE_DATA
+		{"larger header", le(uint16(24), uint16(len(data)), StatusCodeDataTypeStringGUID, uint32(0), data), data, ""},
+		{"short", statusCodeData(StatusCodeDataTypeStringGUID, nil)[:19], nil, "status code data too short: 19 bytes"},
+		{"small header", le(uint16(16), uint16(0), StatusCodeDataTypeStringGUID), nil, "invalid status code data header size 16"},
+		{"oversized", le(uint16(20), uint16(0xFFFF), StatusCodeDataTypeStringGUID, data), nil, "status code data truncated: 27 of 65555 bytes"},
+		{"truncated", statusCodeData(StatusCodeDataTypeStringGUID, data)[:25], nil, "status code data truncated: 25 of 27 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseStatusCodeData(tt.b)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseStatusCodeData() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Type != StatusCodeDataTypeStringGUID || d.Size != uint16(len(tt.want)) || !bytes.Equal(d.Data, tt.want) {
+				t.Errorf("ParseStatusCodeData() = %s %d % X, want %s %d % X",
+					d.Type, d.Size, d.Data, StatusCodeDataTypeStringGUID, len(tt.want), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusCodeRecord(t *testing.T) {
+	data, err := ParseHexDump(assertRecordDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, extData, err := ParseStatusCodeRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := code.String(); got != "ERROR: C90000002:V03050007 I0 93B80004-9FB3-11D4-9A3A-0090273FC14D" {
+		t.Errorf("code = %s", got)
+	}
+	want := []string{
+		"Data Type: Specific Data",
+		"Line: 1005",
+		"File: MdeModulePkg/Bus/Pci/PciBusDxe/PciLib.c",
+		"Description: !(((INTN)(RETURN_STATUS)(Status)) < 0)",
+	}
+	if got := fieldStrings(extData.Fields(code)); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("fields =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// a record without caller ID
+	code, _, err = ParseStatusCodeRecord(le(uint32(0x1), uint32(0x03020003), uint32(0), GUID{},
+		statusCodeData(StatusCodeSpecificDataGUID, nil)))
+	if err != nil || code.String() != "PROGRESS CODE: V03020003 I0" {
+		t.Errorf("ParseStatusCodeRecord() = %s, %v, want a progress code without caller ID", code, err)
+	}
+
+	for _, tt := range []struct {
+		n    int
+		want string
+	}{
+		{27, "status code record too short: 27 bytes"},
+		{40, "status code data too short: 12 bytes"},
+		{100, "status code data truncated: 72 of 115 bytes"},
+	} {
+		if _, _, err := ParseStatusCodeRecord(data[:tt.n]); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseStatusCodeRecord() of %d bytes error = %v, want %s", tt.n, err, tt.want)
+		}
+	}
+}
+
+func TestParseHexDump(t *testing.T) {
+	want := hexBytes(t, "14 00 10 00 BD 84 59 33 05 E8 9A 40 B8 F8 D2 7E CE 5F F7 A6")
+
+	tests := []struct {
+		name string
+		dump string
+	}{
+		{"InternalDumpHex", `00000000: 14 00 10 00 BD 84 59 33-05 E8 9A 40 B8 F8 D2 7E  *......Y3...@...~*
+00000010: CE 5F F7 A6                                      *._..*
+`},
+		{"hexdump -C", `00000000  14 00 10 00 bd 84 59 33  05 e8 9a 40 b8 f8 d2 7e  |......Y3...@...~|
+00000010  ce 5f f7 a6                                       |._..|
+00000014
+`},
+		{"plain", "14 00 10 00 BD 84 59 33 05 E8 9A 40\r\n\tB8 F8 D2 7E CE 5F F7 A6\r\n"},
+		{"words", "14001000BD845933 05E89A40B8F8D27E CE5FF7A6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHexDump(tt.dump)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("ParseHexDump() = % X, want % X", got, want)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		dump string
+		want string
+	}{
+		{"14 00\n10 0", `line 2: invalid hex "0"`},
+		{"00000000: 14 00 ZZ", `line 1: invalid hex "ZZ"`},
+		{"\n \n", "no hex data"},
+	} {
+		if _, err := ParseHexDump(tt.dump); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseHexDump(%q) error = %v, want %s", tt.dump, err, tt.want)
+		}
+	}
+}
+
+func TestStatusCodeDataFields(t *testing.T) {
+	// a SATA disk, see TestDevicePathString
+	sataPath := hexBytes(t, "02010C00 D041030A 00000000 01010600 021F 03120A00 0000 FFFF 0000 7FFF0400")
+	// the EFI_DEBUG_ASSERT_DATA strings, copied after the structure
+	file, desc := "PciLib.c\x00", "Status == EFI_SUCCESS\x00"
+
+	const (
+		progress    = 0x01
+		minorError  = 0x40000002
+		unrecovered = 0x90000002
+	)
+	tests := []struct {
+		name     string
+		codeType uint32
+		value    uint32
+		guid     GUID
+		data     []byte
+		want     []string
+	}{
+		// EFI_DEBUG_ASSERT_DATA after a 64-bit, a 32-bit or no pointer
+		{"assert", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint32(len(file)), uint64(0x7E8A1010), file, desc),
+			[]string{"Line: 42", "File: PciLib.c", "Description: Status == EFI_SUCCESS"}},
+		{"assert, 32-bit", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint32(len(file)), uint32(0x7E8A1010), file, desc),
+			[]string{"Line: 42", "File: PciLib.c", "Description: Status == EFI_SUCCESS"}},
+		{"assert, no pointer", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint32(len(file)), file),
+			[]string{"Line: 42", "File: PciLib.c"}},
+		// the file name does not match its size
+		{"assert, size mismatch", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint32(len(file)+4), uint64(0x7E8A1010), file, desc),
+			nil},
+		{"assert, oversized name", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint32(0xFFFFFFFF), uint64(0x7E8A1010), file),
+			nil},
+		{"assert, truncated", unrecovered, 0x03050007, StatusCodeSpecificDataGUID,
+			le(uint32(42), uint16(0)),
+			nil},
+
+		// EFI_STATUS_CODE_STRING_DATA
+		{"ASCII string", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(0), uint64(0x7E8A1010), "Loading PciBusDxe\x00"),
+			[]string{"String Type: ASCII", "String: Loading PciBusDxe"}},
+		{"ASCII string, 32-bit", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(0), uint32(0x7E8A1010), "Loading PciBusDxe\x00"),
+			[]string{"String Type: ASCII", "String: Loading PciBusDxe"}},
+		{"Unicode string", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(1), uint64(0x7E8A1010), ucs2("Boot0001")),
+			[]string{"String Type: Unicode", "String: Boot0001"}},
+		{"string address", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(0), uint64(0x7E8A1010)),
+			[]string{"String Type: ASCII", "String Address: 0x7E8A1010"}},
+		{"HII token", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(2), uint64(0x7E8A2018), uint16(0x12)),
+			[]string{"String Type: HII Token", "HII Handle: 0x7E8A2018", "Token: 0x12"}},
+		{"HII token, 32-bit", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(2), uint32(0x7E8A2018), uint16(0x12)),
+			[]string{"String Type: HII Token", "HII Handle: 0x7E8A2018", "Token: 0x12"}},
+		{"unknown string type", progress, 0x03050000, StatusCodeDataTypeStringGUID,
+			le(uint32(7), uint32(0)),
+			[]string{"Data: 07 00 00 00 00 00 00 00"}},
+
+		// EFI_DEBUG_INFO
+		{"debug", 0x03, 0x03050000, StatusCodeDataTypeDebugGUID,
+			le(uint32(0x80000044), make([]byte, 12*8), "PciBus: Discovered PCI @ [00|1F|02]\r\n\x00"),
+			[]string{"Error Level: 0x80000044 (LOAD, INFO, ERROR)", "Format: PciBus: Discovered PCI @ [00|1F|02]"}},
+
+		// EFI_COMPUTING_UNIT_* data
+		{"CPU disabled", minorError, 0x00000001, StatusCodeSpecificDataGUID,
+			le(uint32(0x0102), uint8(1)),
+			[]string{"Cause: 0x102 (Thermal Error, By Association)", "SW Disabled: Yes"}},
+		{"CPU disabled, unnamed cause", minorError, 0x00000001, StatusCodeSpecificDataGUID,
+			le(uint32(0x0240), uint8(0)),
+			[]string{"Cause: 0x240 (0x240)", "SW Disabled: No"}},
+		{"cache init", progress, 0x00011001, StatusCodeSpecificDataGUID,
+			le(uint32(2), uint32(2)),
+			[]string{"Level: 2", "Type: Data and Instruction"}},
+		{"processor mismatch", minorError, 0x00011002, StatusCodeSpecificDataGUID,
+			le(uint32(1), uint16(0x1005)),
+			[]string{"Instance: 1", "Attributes: 0x1005 (Speed, Family, OEM1)"}},
+		{"timer expired", minorError, 0x00011003, StatusCodeSpecificDataGUID,
+			le(int16(30), int16(0)),
+			[]string{"Timer Limit: 30 s"}},
+		{"thermal", minorError, 0x00011006, StatusCodeSpecificDataGUID,
+			le(int16(95), int16(0), int16(9000), int16(-2)),
+			[]string{"Temperature: 95 °C", "Threshold: 90 °C"}},
+		{"low voltage", minorError, 0x00011007, StatusCodeSpecificDataGUID,
+			le(int16(105), int16(-2), int16(1150), int16(-3)),
+			[]string{"Voltage: 1.05 V", "Threshold: 1.15 V"}},
+		{"microcode", minorError, 0x0001100A, StatusCodeSpecificDataGUID,
+			le(uint32(0x2006E05)),
+			[]string{"Version: 0x2006E05"}},
+
+		// EFI_MEMORY_* data
+		{"memory test", progress, 0x00051006, StatusCodeSpecificDataGUID,
+			le(uint32(0), uint64(0x100000), uint64(0x7FF00000)),
+			[]string{"Start: 0x100000", "Length: 0x7FF00000"}},
+		{"memory test, packed", progress, 0x00051006, StatusCodeSpecificDataGUID,
+			le(uint64(0x100000), uint64(0x7FF00000)),
+			[]string{"Start: 0x100000", "Length: 0x7FF00000"}},
+		{"memory mismatch", minorError, 0x00051006, StatusCodeSpecificDataGUID,
+			le(uint16(1), uint16(3)),
+			[]string{"DIMM: Array 1, Device 3"}},
+		{"memory error", unrecovered, 0x00051003, StatusCodeSpecificDataGUID,
+			le(uint8(3), uint8(3), uint16(0), uint64(0x5A), uint64(0x12345678), uint64(0x40)),
+			[]string{"Granularity: Device", "Operation: Read", "Syndrome: 0x5A", "Address: 0x12345678", "Resolution: 0x40"}},
+		{"memory error, 32-bit aligned", unrecovered, 0x00051003, StatusCodeSpecificDataGUID,
+			le(uint8(4), uint8(5), uint16(0), uint32(0x5A), uint32(0), uint64(0x12345678), uint32(0x40)),
+			[]string{"Granularity: Partition", "Operation: Partial Write", "Syndrome: 0x5A", "Address: 0x12345678", "Resolution: 0x40"}},
+		{"memory error, 32-bit packed", unrecovered, 0x00051003, StatusCodeSpecificDataGUID,
+			le(uint8(9), uint8(4), uint16(0), uint32(0x5A), uint64(0x12345678), uint32(0x40)),
+			[]string{"Granularity: Unknown (0x9)", "Operation: Write", "Syndrome: 0x5A", "Address: 0x12345678", "Resolution: 0x40"}},
+		{"memory error, oversized", unrecovered, 0x00051003, StatusCodeSpecificDataGUID,
+			le(uint8(3), uint8(3), uint16(0), make([]byte, 28)),
+			[]string{"Data: 03 03 00 00" + strings.Repeat(" 00", 28)}},
+
+		// EFI_DEVICE_PATH_EXTENDED_DATA
+		{"device path", minorError, 0x02080002, StatusCodeSpecificDataGUID, sataPath,
+			[]string{"Device Path: PciRoot(0x0)/Pci(0x1F,0x2)/Sata(0x0,0xFFFF,0x0)"}},
+		{"vendor device path", minorError, 0x02080002, MustParseGUID("0B4E4C44-C56B-4B19-B2A4-9A4F1C5E4D21"), sataPath,
+			[]string{"Device Path: PciRoot(0x0)/Pci(0x1F,0x2)/Sata(0x0,0xFFFF,0x0)"}},
+		{"truncated device path", minorError, 0x02080002, StatusCodeSpecificDataGUID, sataPath[:len(sataPath)-4],
+			[]string{fmt.Sprintf("Data: % X", sataPath[:len(sataPath)-4])}},
+
+		{"unknown", minorError, 0x02080002, MustParseGUID("0B4E4C44-C56B-4B19-B2A4-9A4F1C5E4D21"), []byte{0xDE, 0xAD},
+			[]string{"Data: DE AD"}},
+		{"empty", minorError, 0x02080002, StatusCodeSpecificDataGUID, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := StatusCode{Type: NewStatusCodeType(tt.codeType), Value: NewStatusCodeValue(tt.value)}
+			d, err := ParseStatusCodeData(statusCodeData(tt.guid, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the decoded fields follow the data type
+			want := append([]string{"Data Type: " + d.TypeName()}, tt.want...)
+			if tt.want == nil && len(tt.data) > 0 {
+				want = append(want, fmt.Sprintf("Data: % X", tt.data))
+			}
+			if got := fieldStrings(d.Fields(code)); strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("fields =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+func TestStatusCodeDataTypeName(t *testing.T) {
+	for _, tt := range []struct {
+		guid GUID
+		want string
+	}{
+		{StatusCodeDataTypeStringGUID, "String"},
+		{StatusCodeSpecificDataGUID, "Specific Data"},
+		{StatusCodeDataTypeDebugGUID, "Debug"},
+		{MustParseGUID("0B4E4C44-C56B-4B19-B2A4-9A4F1C5E4D21"), "0B4E4C44-C56B-4B19-B2A4-9A4F1C5E4D21"},
+	} {
+		if got := (StatusCodeData{Type: tt.guid}).TypeName(); got != tt.want {
+			t.Errorf("TypeName() of %s = %q, want %q", tt.guid, got, tt.want)
+		}
+	}
+}
+
+func TestExpBase10String(t *testing.T) {
+	tests := []struct {
+		value    int16
+		exponent int16
+		want     string
+	}{
+		{0, 5, "0"},
+		{95, 0, "95"},
+		{12, 2, "1200"},
+		{95, -2, "0.95"},
+		{5, -3, "0.005"},
+		{-125, -1, "-12.5"},
+		{1200, -2, "12"},
+		{-5, -2, "-0.05"},
+		{1, 30, "1e30"},
+	}
+	for _, tt := range tests {
+		if got := expBase10String(le(tt.value, tt.exponent)); got != tt.want {
+			t.Errorf("expBase10String(%d, %d) = %q, want %q", tt.value, tt.exponent, got, tt.want)
+		}
+	}
+}
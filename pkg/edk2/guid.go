@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// represents an EFI_GUID in its in-memory byte order: the first three
+// fields are little-endian, the last eight bytes are stored as is
+type GUID [16]byte
+
+// ParseGUID parses a GUID in registry format,
+// e.g. 6D33944A-EC75-4855-A54D-809C75241F6C
+func ParseGUID(s string) (GUID, error) {
+	if !IsValidUUID(s) {
+		return GUID{}, fmt.Errorf("invalid GUID format: %q", s)
+	}
+
+	raw, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil {
+		return GUID{}, fmt.Errorf("invalid GUID format: %v", err)
+	}
+
+	var g GUID
+	binary.LittleEndian.PutUint32(g[0:4], binary.BigEndian.Uint32(raw[0:4]))
+	binary.LittleEndian.PutUint16(g[4:6], binary.BigEndian.Uint16(raw[4:6]))
+	binary.LittleEndian.PutUint16(g[6:8], binary.BigEndian.Uint16(raw[6:8]))
+	copy(g[8:], raw[8:])
+
+	return g, nil
+}
+
+func MustParseGUID(s string) GUID {
+	g, err := ParseGUID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return g
+}
+
+func (g GUID) String() string {
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X",
+		binary.LittleEndian.Uint32(g[0:4]),
+		binary.LittleEndian.Uint16(g[4:6]),
+		binary.LittleEndian.Uint16(g[6:8]),
+		g[8:10], g[10:16])
+}
+
+func (g GUID) IsZero() bool {
+	return g == GUID{}
+}
+
+func (g GUID) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
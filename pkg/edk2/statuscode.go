@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package edk2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status Code Types
+const (
+	EFI_PROGRESS_CODE uint8 = 0x01
+	EFI_ERROR_CODE    uint8 = 0x02
+	EFI_DEBUG_CODE    uint8 = 0x03
+)
+
+// represents a status code as reported through ReportStatusCode()
+type StatusCode struct {
+	Type     EFIStatusCodeType
+	Value    EFIStatusCodeValue
+	Instance uint32
+	CallerID string
+}
+
+// represents the human-readable descriptions of a status code
+type StatusCodeDesc struct {
+	Type      string
+	Severity  string
+	Class     string
+	Subclass  string
+	Operation string
+}
+
+func NewStatusCodeValue(value uint32) EFIStatusCodeValue {
+	return decodeStatusValue(value)
+}
+
+func NewStatusCodeType(value uint32) EFIStatusCodeType {
+	return decodeStatusType(value)
+}
+
+func (v EFIStatusCodeValue) Uint32() uint32 {
+	return uint32(v.Class)<<24 | uint32(v.Subclass)<<16 | uint32(v.Operation)
+}
+
+func (t EFIStatusCodeType) Uint32() uint32 {
+	return uint32(t.Severity)<<24 | uint32(t.Type)
+}
+
+func (t EFIStatusCodeType) IsError() bool {
+	return t.Type == EFI_ERROR_CODE
+}
+
+func DescribeStatusCode(code StatusCode) StatusCodeDesc {
+	desc := StatusCodeDesc{
+		Type:     statusTypeDesc[code.Type.Type],
+		Severity: errorSeverityDesc[code.Type.Severity],
+	}
+	desc.Class, desc.Subclass, desc.Operation = describeStatusValue(code.Value, code.Type.IsError())
+
+	return desc
+}
+
+// ParseStatusCodeLine parses a status code line as printed by the EDK2
+// StatusCodeHandler, e.g.
+//
+//	PROGRESS CODE: V03020003 I0
+//	ERROR: C40000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E
+func ParseStatusCodeLine(line string) (StatusCode, error) {
+	line = strings.TrimSpace(line)
+
+	var code StatusCode
+	var fields []string
+
+	if rest, ok := strings.CutPrefix(line, "PROGRESS CODE:"); ok {
+		fields = strings.Fields(rest)
+		if len(fields) == 0 {
+			return StatusCode{}, fmt.Errorf("invalid progress code format: %q", line)
+		}
+
+		value, err := extractStatusCodeValue(fields[0])
+		if err != nil {
+			return StatusCode{}, err
+		}
+		code.Type = EFIStatusCodeType{Type: EFI_PROGRESS_CODE}
+		code.Value = value
+	} else if rest, ok := strings.CutPrefix(line, "ERROR:"); ok {
+		fields = strings.Fields(rest)
+		if len(fields) == 0 {
+			return StatusCode{}, fmt.Errorf("invalid error code format: %q", line)
+		}
+
+		typeString, valueString, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			return StatusCode{}, fmt.Errorf("invalid error code format: %q", line)
+		}
+
+		codeType, err := extractStatusCodeType(typeString)
+		if err != nil {
+			return StatusCode{}, err
+		}
+		value, err := extractStatusCodeValue(valueString)
+		if err != nil {
+			return StatusCode{}, err
+		}
+		code.Type = codeType
+		code.Value = value
+	} else {
+		return StatusCode{}, fmt.Errorf("not a status code line: %q", line)
+	}
+
+	for _, field := range fields[1:] {
+		if instance, ok := strings.CutPrefix(field, "I"); ok {
+			value, err := strconv.ParseUint(instance, 16, 32)
+			if err != nil {
+				return StatusCode{}, fmt.Errorf("invalid instance format: %v", err)
+			}
+			code.Instance = uint32(value)
+		} else if IsValidUUID(field) {
+			code.CallerID = field
+		}
+	}
+
+	return code, nil
+}
+
+// String formats the status code the way the EDK2 StatusCodeHandler
+// prints it to the serial port.
+func (c StatusCode) String() string {
+	switch c.Type.Type {
+	case EFI_PROGRESS_CODE:
+		return fmt.Sprintf("PROGRESS CODE: V%08x I%x", c.Value.Uint32(), c.Instance)
+	case EFI_ERROR_CODE:
+		s := fmt.Sprintf("ERROR: C%08x:V%08x I%x", c.Type.Uint32(), c.Value.Uint32(), c.Instance)
+		if c.CallerID != "" {
+			s += " " + c.CallerID
+		}
+		return s
+	default:
+		return fmt.Sprintf("STATUS CODE: C%08x:V%08x I%x", c.Type.Uint32(), c.Value.Uint32(), c.Instance)
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package fpdt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// represents a table of a dump file and the physical address it was
+// read from, or 0 if unknown
+type Table struct {
+	Signature string
+	Address   uint64
+	Data      []byte
+}
+
+// represents the tables read from one or more dump files
+type Dump struct {
+	Tables []Table
+}
+
+// acpidump prints every table as "SIGN @ 0xADDRESS" followed by
+// "OFFS: hex bytes  ascii" lines
+var (
+	tableHeaderRegex = regexp.MustCompile(`^\s*([A-Za-z0-9_!]{4})\s+@\s+(?:0x)?([0-9A-Fa-f]+)\s*$`)
+	tableLineRegex   = regexp.MustCompile(`^\s*[0-9A-Fa-f]+:((?:\s[0-9A-Fa-f]{2}){1,16})`)
+)
+
+// ParseDump reads the tables of a dump file: either the text output of
+// acpidump, or a binary table such as /sys/firmware/acpi/tables/FPDT or
+// an FBPT/S3PT read from memory.
+func ParseDump(data []byte) (*Dump, error) {
+	if isTextDump(data) {
+		return parseTextDump(data)
+	}
+
+	if len(data) < 4 {
+		return nil, fmt.Errorf("empty table dump")
+	}
+
+	return &Dump{Tables: []Table{{Signature: string(data[:4]), Data: data}}}, nil
+}
+
+func isTextDump(data []byte) bool {
+	line, _, _ := bytes.Cut(bytes.TrimLeft(data, " \t\r\n"), []byte("\n"))
+	return tableHeaderRegex.Match(bytes.TrimRight(line, "\r"))
+}
+
+func parseTextDump(data []byte) (*Dump, error) {
+	dump := &Dump{}
+
+	var current *Table
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+
+		if m := tableHeaderRegex.FindStringSubmatch(line); m != nil {
+			address, err := strconv.ParseUint(m[2], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid address %q", lineNo, m[2])
+			}
+			dump.Tables = append(dump.Tables, Table{Signature: m[1], Address: address})
+			current = &dump.Tables[len(dump.Tables)-1]
+			continue
+		}
+
+		m := tableLineRegex.FindStringSubmatch(line)
+		if m == nil || current == nil {
+			continue
+		}
+
+		b, err := hex.DecodeString(strings.ReplaceAll(m[1], " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		current.Data = append(current.Data, b...)
+	}
+
+	return dump, scanner.Err()
+}
+
+// ReadDump reads and merges the tables of the dump files.
+func ReadDump(paths ...string) (*Dump, error) {
+	dump := &Dump{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := ParseDump(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		dump.Tables = append(dump.Tables, d.Tables...)
+	}
+
+	return dump, nil
+}
+
+// Find returns the table at the given physical address, falling back
+// to the first table with the signature if no table was dumped with
+// its address.
+func (d *Dump) Find(signature string, address uint64) (Table, bool) {
+	if address != 0 {
+		for _, table := range d.Tables {
+			if table.Address == address && table.Signature == signature {
+				return table, true
+			}
+		}
+	}
+
+	for _, table := range d.Tables {
+		if table.Signature == signature {
+			return table, true
+		}
+	}
+
+	return Table{}, false
+}
+
+// represents the performance tables read from a dump
+type Performance struct {
+	FPDT *FPDT
+	FBPT *FBPT
+	S3PT *S3PT
+}
+
+// Performance decodes the FPDT of the dump and the FBPT and S3PT it
+// points to. Either performance table may be missing from the dump, in
+// which case only the tables found are returned.
+func (d *Dump) Performance() (*Performance, error) {
+	perf := &Performance{FPDT: &FPDT{}}
+
+	if table, ok := d.Find("FPDT", 0); ok {
+		fpdt, err := ParseFPDT(table.Data)
+		if err != nil {
+			return nil, err
+		}
+		perf.FPDT = fpdt
+	}
+
+	if table, ok := d.Find("FBPT", perf.FPDT.FBPTAddress); ok {
+		fbpt, err := ParseFBPT(table.Data)
+		if err != nil {
+			return nil, err
+		}
+		perf.FBPT = fbpt
+	}
+
+	if table, ok := d.Find("S3PT", perf.FPDT.S3PTAddress); ok {
+		s3pt, err := ParseS3PT(table.Data)
+		if err != nil {
+			return nil, err
+		}
+		perf.S3PT = s3pt
+	}
+
+	if perf.FBPT == nil && perf.S3PT == nil {
+		return nil, fmt.Errorf("no FBPT or S3PT in the table dump")
+	}
+
+	return perf, nil
+}
@@ -0,0 +1,416 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package fpdt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Below are definitions of the Firmware Performance Data Table (FPDT)
+//
+// The FPDT only holds pointers to the performance tables, which live
+// in reserved memory:
+//
+// ┌───────────────────────┐     ┌────────────────────────────────────┐
+// │ FPDT                  │     │ FBPT "FBPT"                        │
+// │  ACPI header          │  ┌─►│  Basic Boot Performance Record     │
+// │  FBPT Pointer Record ─┼──┘  │  EDK2 extended records (GUID/str)  │
+// │  S3PT Pointer Record ─┼──┐  └────────────────────────────────────┘
+// └───────────────────────┘  │  ┌────────────────────────────────────┐
+//                            └─►│ S3PT "S3PT"                        │
+//                               │  Resume / Suspend Records          │
+//                               └────────────────────────────────────┘
+//
+// All time stamps are in nanoseconds since the processor reset.
+//
+// Reference: ACPI Specification, 5.2.23 Firmware Performance Data Table
+//            https://github.com/tianocore/edk2/blob/master/MdePkg/Include/IndustryStandard/FirmwarePerformance.h
+//            https://github.com/tianocore/edk2/blob/master/MdeModulePkg/Include/Guid/ExtendedFirmwarePerformance.h
+//
+
+// Performance Record Types
+const (
+	FPDT_RECORD_TYPE_FIRMWARE_BASIC_BOOT_POINTER  uint16 = 0x0000
+	FPDT_RECORD_TYPE_S3_PERFORMANCE_TABLE_POINTER uint16 = 0x0001
+
+	FPDT_RUNTIME_RECORD_TYPE_S3_RESUME           uint16 = 0x0000
+	FPDT_RUNTIME_RECORD_TYPE_S3_SUSPEND          uint16 = 0x0001
+	FPDT_RUNTIME_RECORD_TYPE_FIRMWARE_BASIC_BOOT uint16 = 0x0002
+
+	FPDT_GUID_EVENT_TYPE              uint16 = 0x1010
+	FPDT_DYNAMIC_STRING_EVENT_TYPE    uint16 = 0x1011
+	FPDT_DUAL_GUID_STRING_EVENT_TYPE  uint16 = 0x1012
+	FPDT_GUID_QWORD_EVENT_TYPE        uint16 = 0x1013
+	FPDT_GUID_QWORD_STRING_EVENT_TYPE uint16 = 0x1014
+)
+
+const (
+	acpiHeaderSize        = 36
+	recordHeaderSize      = 4
+	performanceHeaderSize = 8
+)
+
+// Progress ID mappings of the EDK2 extended records
+var progressIDDesc = map[uint16]string{
+	0x00: "Event",
+	0x01: "Module Start",
+	0x02: "Module End",
+	0x03: "LoadImage Start",
+	0x04: "LoadImage End",
+	0x05: "Driver Binding Start",
+	0x06: "Driver Binding End",
+	0x07: "Driver Binding Support Start",
+	0x08: "Driver Binding Support End",
+	0x09: "Driver Binding Stop Start",
+	0x0A: "Driver Binding Stop End",
+	0x10: "Event Signal Start",
+	0x11: "Event Signal End",
+	0x20: "Callback Start",
+	0x21: "Callback End",
+	0x30: "Function Start",
+	0x31: "Function End",
+	0x40: "In Module Start",
+	0x41: "In Module End",
+	0x50: "Cross Module Start",
+	0x51: "Cross Module End",
+}
+
+// Cross module tokens marking the EDK2 phases
+var phaseToken = map[string]decoder.Phase{
+	"SEC": decoder.PhaseSEC,
+	"PEI": decoder.PhasePEI,
+	"DXE": decoder.PhaseDXE,
+	"BDS": decoder.PhaseBDS,
+}
+
+// represents the record header shared by all performance records
+type RecordHeader struct {
+	Type     uint16
+	Length   uint8
+	Revision uint8
+}
+
+// represents the pointers of the FPDT
+type FPDT struct {
+	FBPTAddress uint64
+	S3PTAddress uint64
+}
+
+// represents the Firmware Basic Boot Performance Record
+type BasicBootRecord struct {
+	ResetEnd                uint64
+	OsLoaderLoadImageStart  uint64
+	OsLoaderStartImageStart uint64
+	ExitBootServicesEntry   uint64
+	ExitBootServicesExit    uint64
+}
+
+// represents an EDK2 extended performance record
+type ExtendedRecord struct {
+	Type       uint16
+	ProgressID uint16
+	ApicID     uint32
+	Timestamp  uint64
+	GUID       edk2.GUID
+	GUID2      edk2.GUID
+	Qword      uint64
+	String     string
+}
+
+// represents the S3 Resume Record
+type ResumeRecord struct {
+	ResumeCount   uint32
+	FullResume    uint64
+	AverageResume uint64
+}
+
+// represents the S3 Suspend Record
+type SuspendRecord struct {
+	SuspendStart uint64
+	SuspendEnd   uint64
+}
+
+// represents a parsed Firmware Basic Boot Performance Table
+type FBPT struct {
+	Basic    *BasicBootRecord
+	Extended []ExtendedRecord
+}
+
+// represents a parsed S3 Performance Table
+type S3PT struct {
+	Resume  *ResumeRecord
+	Suspend *SuspendRecord
+}
+
+func le16(b []byte) uint16 { return binary.LittleEndian.Uint16(b) }
+func le32(b []byte) uint32 { return binary.LittleEndian.Uint32(b) }
+func le64(b []byte) uint64 { return binary.LittleEndian.Uint64(b) }
+
+// forEachRecord walks the performance records of a table body
+func forEachRecord(data []byte, fn func(header RecordHeader, record []byte) error) error {
+	for offset := 0; offset+recordHeaderSize <= len(data); {
+		header := RecordHeader{
+			Type:     le16(data[offset:]),
+			Length:   data[offset+2],
+			Revision: data[offset+3],
+		}
+		if header.Length < recordHeaderSize || offset+int(header.Length) > len(data) {
+			return fmt.Errorf("invalid record length %d at offset 0x%X", header.Length, offset)
+		}
+
+		if err := fn(header, data[offset:offset+int(header.Length)]); err != nil {
+			return err
+		}
+		offset += int(header.Length)
+	}
+
+	return nil
+}
+
+// ParseFPDT parses the FPDT ACPI table.
+func ParseFPDT(data []byte) (*FPDT, error) {
+	if len(data) < acpiHeaderSize || !bytes.HasPrefix(data, []byte("FPDT")) {
+		return nil, fmt.Errorf("not an FPDT table")
+	}
+
+	length := int(le32(data[4:]))
+	if length < acpiHeaderSize || length > len(data) {
+		return nil, fmt.Errorf("invalid FPDT length %d", length)
+	}
+
+	table := &FPDT{}
+	err := forEachRecord(data[acpiHeaderSize:length], func(header RecordHeader, record []byte) error {
+		if len(record) < 16 {
+			return nil
+		}
+
+		switch header.Type {
+		case FPDT_RECORD_TYPE_FIRMWARE_BASIC_BOOT_POINTER:
+			table.FBPTAddress = le64(record[8:])
+		case FPDT_RECORD_TYPE_S3_PERFORMANCE_TABLE_POINTER:
+			table.S3PTAddress = le64(record[8:])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FPDT: %v", err)
+	}
+
+	return table, nil
+}
+
+func tableBody(data []byte, signature string) ([]byte, error) {
+	if len(data) < performanceHeaderSize || !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, fmt.Errorf("not an %s table", signature)
+	}
+
+	length := int(le32(data[4:]))
+	if length < performanceHeaderSize || length > len(data) {
+		return nil, fmt.Errorf("invalid %s length %d", signature, length)
+	}
+
+	return data[performanceHeaderSize:length], nil
+}
+
+// ParseFBPT parses a Firmware Basic Boot Performance Table, including
+// the extended records appended by EDK2.
+func ParseFBPT(data []byte) (*FBPT, error) {
+	body, err := tableBody(data, "FBPT")
+	if err != nil {
+		return nil, err
+	}
+
+	table := &FBPT{}
+	err = forEachRecord(body, func(header RecordHeader, record []byte) error {
+		switch header.Type {
+		case FPDT_RUNTIME_RECORD_TYPE_FIRMWARE_BASIC_BOOT:
+			if len(record) < 48 {
+				return fmt.Errorf("truncated basic boot performance record")
+			}
+			table.Basic = &BasicBootRecord{
+				ResetEnd:                le64(record[8:]),
+				OsLoaderLoadImageStart:  le64(record[16:]),
+				OsLoaderStartImageStart: le64(record[24:]),
+				ExitBootServicesEntry:   le64(record[32:]),
+				ExitBootServicesExit:    le64(record[40:]),
+			}
+		case FPDT_GUID_EVENT_TYPE, FPDT_DYNAMIC_STRING_EVENT_TYPE, FPDT_DUAL_GUID_STRING_EVENT_TYPE,
+			FPDT_GUID_QWORD_EVENT_TYPE, FPDT_GUID_QWORD_STRING_EVENT_TYPE:
+			extended, err := parseExtendedRecord(header, record)
+			if err != nil {
+				return err
+			}
+			table.Extended = append(table.Extended, extended)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FBPT: %v", err)
+	}
+
+	return table, nil
+}
+
+func parseExtendedRecord(header RecordHeader, record []byte) (ExtendedRecord, error) {
+	// Header, ProgressID, ApicID, Timestamp and GUID are common to all
+	// extended records
+	const commonSize = recordHeaderSize + 2 + 4 + 8 + 16
+	if len(record) < commonSize {
+		return ExtendedRecord{}, fmt.Errorf("truncated extended record type 0x%04X", header.Type)
+	}
+
+	extended := ExtendedRecord{
+		Type:       header.Type,
+		ProgressID: le16(record[4:]),
+		ApicID:     le32(record[6:]),
+		Timestamp:  le64(record[10:]),
+	}
+	copy(extended.GUID[:], record[18:34])
+
+	rest := record[commonSize:]
+	switch header.Type {
+	case FPDT_DYNAMIC_STRING_EVENT_TYPE:
+		extended.String = cString(rest)
+	case FPDT_DUAL_GUID_STRING_EVENT_TYPE:
+		if len(rest) < 16 {
+			return ExtendedRecord{}, fmt.Errorf("truncated dual GUID string record")
+		}
+		copy(extended.GUID2[:], rest[:16])
+		extended.String = cString(rest[16:])
+	case FPDT_GUID_QWORD_EVENT_TYPE, FPDT_GUID_QWORD_STRING_EVENT_TYPE:
+		if len(rest) < 8 {
+			return ExtendedRecord{}, fmt.Errorf("truncated GUID qword record")
+		}
+		extended.Qword = le64(rest)
+		extended.String = cString(rest[8:])
+	}
+
+	return extended, nil
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+
+	return string(b)
+}
+
+// ParseS3PT parses an S3 Performance Table.
+func ParseS3PT(data []byte) (*S3PT, error) {
+	body, err := tableBody(data, "S3PT")
+	if err != nil {
+		return nil, err
+	}
+
+	table := &S3PT{}
+	err = forEachRecord(body, func(header RecordHeader, record []byte) error {
+		switch header.Type {
+		case FPDT_RUNTIME_RECORD_TYPE_S3_RESUME:
+			if len(record) < 24 {
+				return fmt.Errorf("truncated resume record")
+			}
+			table.Resume = &ResumeRecord{
+				ResumeCount:   le32(record[4:]),
+				FullResume:    le64(record[8:]),
+				AverageResume: le64(record[16:]),
+			}
+		case FPDT_RUNTIME_RECORD_TYPE_S3_SUSPEND:
+			if len(record) < 20 {
+				return fmt.Errorf("truncated suspend record")
+			}
+			table.Suspend = &SuspendRecord{
+				SuspendStart: le64(record[4:]),
+				SuspendEnd:   le64(record[12:]),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("S3PT: %v", err)
+	}
+
+	return table, nil
+}
+
+func newRecord(timestamp uint64, subclass, operation string, phase decoder.Phase) decoder.Record {
+	return decoder.Record{
+		Decoder:      "fpdt",
+		Line:         fmt.Sprintf("FPDT: %s %s @ %d ns", subclass, operation, timestamp),
+		Timestamp:    time.Duration(timestamp),
+		HasTimestamp: true,
+		Kind:         decoder.KindInfo,
+		Phase:        phase,
+		Class:        "FPDT",
+		Subclass:     subclass,
+		Operation:    operation,
+	}
+}
+
+// Records converts the performance table into timeline records, in
+// time stamp order.
+func (t *FBPT) Records() []decoder.Record {
+	var records []decoder.Record
+
+	if b := t.Basic; b != nil {
+		for _, event := range []struct {
+			name      string
+			timestamp uint64
+			phase     decoder.Phase
+		}{
+			{"ResetEnd", b.ResetEnd, decoder.PhaseSEC},
+			{"OsLoaderLoadImageStart", b.OsLoaderLoadImageStart, decoder.PhaseBDS},
+			{"OsLoaderStartImageStart", b.OsLoaderStartImageStart, decoder.PhaseBDS},
+			{"ExitBootServicesEntry", b.ExitBootServicesEntry, decoder.PhaseOS},
+			{"ExitBootServicesExit", b.ExitBootServicesExit, decoder.PhaseOS},
+		} {
+			if event.timestamp == 0 && event.name != "ResetEnd" {
+				continue
+			}
+			records = append(records, newRecord(event.timestamp, "Basic Boot Performance", event.name, event.phase))
+		}
+	}
+
+	for _, e := range t.Extended {
+		subclass, ok := progressIDDesc[e.ProgressID]
+		if !ok {
+			subclass = fmt.Sprintf("Progress ID 0x%04X", e.ProgressID)
+		}
+
+		operation := e.String
+		if operation == "" {
+			operation = e.GUID.String()
+		}
+
+		phase := decoder.PhaseUnknown
+		if e.ProgressID == 0x50 {
+			phase = phaseToken[e.String]
+		}
+
+		record := newRecord(e.Timestamp, subclass, operation, phase)
+		if !e.GUID.IsZero() {
+			record.Module = e.GUID.String()
+		}
+		records = append(records, record)
+	}
+
+	sortByTimestamp(records)
+
+	return records
+}
+
+func sortByTimestamp(records []decoder.Record) {
+	// insertion sort keeps equal time stamps in table order
+	for i := 1; i < len(records); i++ {
+		for j := i; j > 0 && records[j].Timestamp < records[j-1].Timestamp; j-- {
+			records[j], records[j-1] = records[j-1], records[j]
+		}
+	}
+}
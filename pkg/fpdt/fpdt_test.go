@@ -0,0 +1,347 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package fpdt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// testdata/acpidump.txt holds the WAET and FPDT of an OVMF guest as
+// printed by acpidump, and testdata/fbpt.bin the FBPT the FPDT points
+// to, read from memory: the basic boot performance record, then the
+// PEI, DXE and BDS cross module string events and the start and end
+// GUID events of CpuDxe.
+
+var (
+	peiCoreGUID = edk2.MustParseGUID("52C05B14-0B98-496C-BC3B-04B50211D680")
+	dxeCoreGUID = edk2.MustParseGUID("D6A2CB7F-6A18-4E2F-B43B-9920A733700A")
+	cpuDxeGUID  = edk2.MustParseGUID("5AE3F37E-4EAE-41AE-8240-35465B5E81EB")
+	bdsDxeGUID  = edk2.MustParseGUID("6D33944A-EC75-4855-A54D-809C75241F6C")
+)
+
+// performanceTable builds a table of the given signature holding the
+// records
+func performanceTable(signature string, records ...[]byte) []byte {
+	data := []byte(signature + "\x00\x00\x00\x00")
+	for _, record := range records {
+		data = append(data, record...)
+	}
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)))
+
+	return data
+}
+
+// record builds a performance record of the given type and body
+func record(recordType uint16, body ...any) []byte {
+	var data bytes.Buffer
+	for _, field := range body {
+		binary.Write(&data, binary.LittleEndian, field)
+	}
+
+	header := []byte{0, 0, byte(recordHeaderSize + data.Len()), 1}
+	binary.LittleEndian.PutUint16(header, recordType)
+
+	return append(header, data.Bytes()...)
+}
+
+func TestReadDump(t *testing.T) {
+	dump, err := ReadDump("testdata/acpidump.txt", "testdata/fbpt.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tables []string
+	for _, table := range dump.Tables {
+		tables = append(tables, table.Signature)
+	}
+	if strings.Join(tables, " ") != "WAET FPDT FBPT" {
+		t.Fatalf("tables = %v, want WAET FPDT FBPT", tables)
+	}
+	if dump.Tables[1].Address != 0x7FB7A000 || len(dump.Tables[1].Data) != 0x44 {
+		t.Errorf("FPDT at 0x%X of %d bytes, want 0x7FB7A000 of 68 bytes", dump.Tables[1].Address, len(dump.Tables[1].Data))
+	}
+
+	perf, err := dump.Performance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perf.FPDT.FBPTAddress != 0x7FB5E000 || perf.FPDT.S3PTAddress != 0x7FB5D000 {
+		t.Errorf("FPDT = %+v, want FBPT at 0x7FB5E000 and S3PT at 0x7FB5D000", *perf.FPDT)
+	}
+	if perf.S3PT != nil {
+		t.Errorf("S3PT = %+v, want none as the dump has no S3PT", *perf.S3PT)
+	}
+	if perf.FBPT == nil || perf.FBPT.Basic == nil {
+		t.Fatal("no FBPT basic boot performance record")
+	}
+
+	want := BasicBootRecord{
+		ResetEnd:                1250000,
+		OsLoaderLoadImageStart:  2900000000,
+		OsLoaderStartImageStart: 3050000000,
+		ExitBootServicesEntry:   4100000000,
+		ExitBootServicesExit:    4120000000,
+	}
+	if *perf.FBPT.Basic != want {
+		t.Errorf("basic boot record = %+v, want %+v", *perf.FBPT.Basic, want)
+	}
+
+	wantExtended := []ExtendedRecord{
+		{Type: FPDT_DYNAMIC_STRING_EVENT_TYPE, ProgressID: 0x50, Timestamp: 20000000, GUID: peiCoreGUID, String: "PEI"},
+		{Type: FPDT_DYNAMIC_STRING_EVENT_TYPE, ProgressID: 0x50, Timestamp: 900000000, GUID: dxeCoreGUID, String: "DXE"},
+		{Type: FPDT_DYNAMIC_STRING_EVENT_TYPE, ProgressID: 0x50, Timestamp: 2500000000, GUID: bdsDxeGUID, String: "BDS"},
+		{Type: FPDT_GUID_EVENT_TYPE, ProgressID: 0x01, Timestamp: 1000000000, GUID: cpuDxeGUID},
+		{Type: FPDT_GUID_EVENT_TYPE, ProgressID: 0x02, Timestamp: 1010000000, GUID: cpuDxeGUID},
+	}
+	if len(perf.FBPT.Extended) != len(wantExtended) {
+		t.Fatalf("got %d extended records, want %d", len(perf.FBPT.Extended), len(wantExtended))
+	}
+	for i, got := range perf.FBPT.Extended {
+		if got != wantExtended[i] {
+			t.Errorf("extended record %d = %+v, want %+v", i, got, wantExtended[i])
+		}
+	}
+}
+
+func TestParseDumpBinary(t *testing.T) {
+	data, err := os.ReadFile("testdata/fbpt.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump, err := ParseDump(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dump.Tables) != 1 || dump.Tables[0].Signature != "FBPT" || dump.Tables[0].Address != 0 {
+		t.Errorf("tables = %+v, want a single FBPT without address", dump.Tables)
+	}
+
+	if _, err := ParseDump([]byte("FB")); err == nil {
+		t.Errorf("ParseDump() of 2 bytes succeeded")
+	}
+}
+
+func TestFind(t *testing.T) {
+	dump := &Dump{Tables: []Table{
+		{Signature: "FBPT", Address: 0x1000, Data: []byte("FBPT1")},
+		{Signature: "FBPT", Address: 0x2000, Data: []byte("FBPT2")},
+	}}
+
+	tests := []struct {
+		address uint64
+		want    string
+	}{
+		{0x2000, "FBPT2"},
+		{0x1000, "FBPT1"},
+		// no table at the address, e.g. a dump of another boot
+		{0x3000, "FBPT1"},
+		{0, "FBPT1"},
+	}
+	for _, tt := range tests {
+		table, ok := dump.Find("FBPT", tt.address)
+		if !ok || string(table.Data) != tt.want {
+			t.Errorf("Find(FBPT, 0x%X) = %q, want %q", tt.address, table.Data, tt.want)
+		}
+	}
+
+	if _, ok := dump.Find("S3PT", 0); ok {
+		t.Errorf("Find(S3PT) found a table")
+	}
+}
+
+func TestParseFPDT(t *testing.T) {
+	fpdt := func(records ...[]byte) []byte {
+		data := make([]byte, acpiHeaderSize)
+		copy(data, "FPDT")
+		for _, record := range records {
+			data = append(data, record...)
+		}
+		binary.LittleEndian.PutUint32(data[4:], uint32(len(data)))
+		return data
+	}
+
+	table, err := ParseFPDT(fpdt(record(FPDT_RECORD_TYPE_FIRMWARE_BASIC_BOOT_POINTER, uint32(0), uint64(0x7FB5E000))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.FBPTAddress != 0x7FB5E000 || table.S3PTAddress != 0 {
+		t.Errorf("FPDT = %+v, want only an FBPT pointer", *table)
+	}
+
+	oversized := fpdt()
+	binary.LittleEndian.PutUint32(oversized[4:], acpiHeaderSize+1)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"signature", performanceTable("FACP", make([]byte, acpiHeaderSize)), "not an FPDT table"},
+		{"truncated header", []byte("FPDT"), "not an FPDT table"},
+		{"oversized length", oversized, "invalid FPDT length 37"},
+		{"record length", fpdt([]byte{0, 0, 2, 1}), "FPDT: invalid record length 2 at offset 0x0"},
+		{"oversized record", fpdt([]byte{0, 0, 16, 1}), "FPDT: invalid record length 16 at offset 0x0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFPDT(tt.data)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseFPDT() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseFBPT(t *testing.T) {
+	extended := func(recordType uint16, progressID uint16, timestamp uint64, guid edk2.GUID, rest ...any) []byte {
+		return record(recordType, append([]any{progressID, uint32(1), timestamp, guid}, rest...)...)
+	}
+
+	data := performanceTable("FBPT",
+		extended(FPDT_DUAL_GUID_STRING_EVENT_TYPE, 0x20, 100, dxeCoreGUID, cpuDxeGUID, []byte("Callback\x00")),
+		extended(FPDT_GUID_QWORD_EVENT_TYPE, 0x30, 200, bdsDxeGUID, uint64(0xC0FFEE)),
+		extended(FPDT_GUID_QWORD_STRING_EVENT_TYPE, 0x31, 300, bdsDxeGUID, uint64(7), []byte("Boot0001\x00")),
+		// record types FBPT readers do not know are skipped
+		record(0x3000, uint32(0)),
+	)
+
+	table, err := ParseFBPT(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Basic != nil {
+		t.Errorf("basic boot record = %+v, want none", *table.Basic)
+	}
+
+	want := []ExtendedRecord{
+		{Type: FPDT_DUAL_GUID_STRING_EVENT_TYPE, ProgressID: 0x20, ApicID: 1, Timestamp: 100,
+			GUID: dxeCoreGUID, GUID2: cpuDxeGUID, String: "Callback"},
+		{Type: FPDT_GUID_QWORD_EVENT_TYPE, ProgressID: 0x30, ApicID: 1, Timestamp: 200,
+			GUID: bdsDxeGUID, Qword: 0xC0FFEE},
+		{Type: FPDT_GUID_QWORD_STRING_EVENT_TYPE, ProgressID: 0x31, ApicID: 1, Timestamp: 300,
+			GUID: bdsDxeGUID, Qword: 7, String: "Boot0001"},
+	}
+	if len(table.Extended) != len(want) {
+		t.Fatalf("got %d extended records, want %d", len(table.Extended), len(want))
+	}
+	for i, got := range table.Extended {
+		if got != want[i] {
+			t.Errorf("extended record %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseFBPTInvalid(t *testing.T) {
+	oversized := performanceTable("FBPT")
+	binary.LittleEndian.PutUint32(oversized[4:], 64)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"signature", performanceTable("S3PT"), "not an FBPT table"},
+		{"oversized length", oversized, "invalid FBPT length 64"},
+		{"zero record length", performanceTable("FBPT", []byte{2, 0, 0, 2}), "FBPT: invalid record length 0 at offset 0x0"},
+		{"truncated basic boot record", performanceTable("FBPT", record(FPDT_RUNTIME_RECORD_TYPE_FIRMWARE_BASIC_BOOT, uint32(0), uint64(1))),
+			"FBPT: truncated basic boot performance record"},
+		{"truncated extended record", performanceTable("FBPT", record(FPDT_GUID_EVENT_TYPE, uint16(1), uint32(0), uint64(1))),
+			"FBPT: truncated extended record type 0x1010"},
+		{"truncated dual GUID record", performanceTable("FBPT", record(FPDT_DUAL_GUID_STRING_EVENT_TYPE, uint16(1), uint32(0), uint64(1), cpuDxeGUID)),
+			"FBPT: truncated dual GUID string record"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFBPT(tt.data)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseFBPT() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseS3PT(t *testing.T) {
+	data := performanceTable("S3PT",
+		record(FPDT_RUNTIME_RECORD_TYPE_S3_RESUME, uint32(3), uint64(150000000), uint64(120000000)),
+		record(FPDT_RUNTIME_RECORD_TYPE_S3_SUSPEND, uint64(5000000000), uint64(5040000000)),
+	)
+
+	table, err := ParseS3PT(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Resume == nil || *table.Resume != (ResumeRecord{ResumeCount: 3, FullResume: 150000000, AverageResume: 120000000}) {
+		t.Errorf("resume record = %+v", table.Resume)
+	}
+	if table.Suspend == nil || *table.Suspend != (SuspendRecord{SuspendStart: 5000000000, SuspendEnd: 5040000000}) {
+		t.Errorf("suspend record = %+v", table.Suspend)
+	}
+
+	_, err = ParseS3PT(performanceTable("S3PT", record(FPDT_RUNTIME_RECORD_TYPE_S3_RESUME, uint32(3))))
+	if err == nil || err.Error() != "S3PT: truncated resume record" {
+		t.Errorf("ParseS3PT() error = %v, want a truncated resume record", err)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	dump, err := ReadDump("testdata/acpidump.txt", "testdata/fbpt.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	perf, err := dump.Performance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the records are in time stamp order, the BDS event after the
+	// CpuDxe events logged after it
+	want := []struct {
+		timestamp time.Duration
+		subclass  string
+		operation string
+		phase     decoder.Phase
+		module    string
+	}{
+		{1250 * time.Microsecond, "Basic Boot Performance", "ResetEnd", decoder.PhaseSEC, ""},
+		{20 * time.Millisecond, "Cross Module Start", "PEI", decoder.PhasePEI, peiCoreGUID.String()},
+		{900 * time.Millisecond, "Cross Module Start", "DXE", decoder.PhaseDXE, dxeCoreGUID.String()},
+		{1000 * time.Millisecond, "Module Start", cpuDxeGUID.String(), decoder.PhaseUnknown, cpuDxeGUID.String()},
+		{1010 * time.Millisecond, "Module End", cpuDxeGUID.String(), decoder.PhaseUnknown, cpuDxeGUID.String()},
+		{2500 * time.Millisecond, "Cross Module Start", "BDS", decoder.PhaseBDS, bdsDxeGUID.String()},
+		{2900 * time.Millisecond, "Basic Boot Performance", "OsLoaderLoadImageStart", decoder.PhaseBDS, ""},
+		{3050 * time.Millisecond, "Basic Boot Performance", "OsLoaderStartImageStart", decoder.PhaseBDS, ""},
+		{4100 * time.Millisecond, "Basic Boot Performance", "ExitBootServicesEntry", decoder.PhaseOS, ""},
+		{4120 * time.Millisecond, "Basic Boot Performance", "ExitBootServicesExit", decoder.PhaseOS, ""},
+	}
+
+	records := perf.FBPT.Records()
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, r := range records {
+		w := want[i]
+		if r.Timestamp != w.timestamp || !r.HasTimestamp || r.Subclass != w.subclass ||
+			r.Operation != w.operation || r.Phase != w.phase || r.Module != w.module {
+			t.Errorf("record %d = %v %s %s %v %q, want %v %s %s %v %q", i,
+				r.Timestamp, r.Subclass, r.Operation, r.Phase, r.Module,
+				w.timestamp, w.subclass, w.operation, w.phase, w.module)
+		}
+		if r.Decoder != "fpdt" || r.Class != "FPDT" || r.Kind != decoder.KindInfo {
+			t.Errorf("record %d = %s %s %v, want an fpdt FPDT info record", i, r.Decoder, r.Class, r.Kind)
+		}
+	}
+
+	if got := records[1].Line; got != "FPDT: Cross Module Start PEI @ 20000000 ns" {
+		t.Errorf("record 1 line = %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package fsp
+
+import (
+	"fmt"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Below are definitions of the Intel FSP post codes
+//
+// 0             8            12           16
+// ┌─────────────┬────────────┬────────────┐
+// │             │            │            │
+// │  EXECUTION  │   MODULE   │    API     │
+// │             │            │            │
+// └─────────────┴────────────┴────────────┘
+//
+// e.g. 0xD800: FspMemoryInit, Common Code, API Entry
+//      0xD87F: FspMemoryInit, Common Code, API Exit
+//
+// Reference: https://github.com/tianocore/edk2/blob/master/IntelFsp2Pkg/Include/FspStatusCode.h
+//
+
+// Post Code Masks
+const (
+	FSP_STATUS_CODE_API_MASK       uint16 = 0xF000
+	FSP_STATUS_CODE_MODULE_MASK    uint16 = 0x0F00
+	FSP_STATUS_CODE_EXECUTION_MASK uint16 = 0x00FF
+)
+
+// Execution codes
+const (
+	FSP_STATUS_CODE_API_ENTRY uint16 = 0x0000
+	FSP_STATUS_CODE_API_EXIT  uint16 = 0x007F
+)
+
+// API mappings
+var apiDesc = map[uint16]string{
+	0xF000: "FSP-T TempRamInit",
+	0xD000: "FSP-M FspMemoryInit",
+	0xB000: "FSP-M TempRamExit",
+	0x9000: "FSP-S FspSiliconInit",
+	0x6000: "FSP-S NotifyPhase Post PCI Enumeration",
+	0x4000: "FSP-S NotifyPhase Ready To Boot",
+	0x2000: "FSP-S NotifyPhase End Of Firmware",
+}
+
+// API to boot phase mappings
+var apiPhase = map[uint16]decoder.Phase{
+	0xF000: decoder.PhaseSEC,
+	0xD000: decoder.PhasePEI,
+	0xB000: decoder.PhasePEI,
+	0x9000: decoder.PhasePEI,
+	0x6000: decoder.PhaseDXE,
+	0x4000: decoder.PhaseBDS,
+	0x2000: decoder.PhaseOS,
+}
+
+// Module mappings
+var moduleDesc = map[uint16]string{
+	0x0700: "GFX PEIM",
+	0x0800: "Common Code",
+	0x0900: "Silicon Common Code",
+	0x0A00: "System Agent",
+	0x0B00: "PCH",
+	0x0C00: "CPU",
+	0x0D00: "MRC",
+	0x0E00: "ME BIOS",
+}
+
+// Decoder decodes the post codes emitted by the Intel FSP.
+type Decoder struct{}
+
+func init() {
+	decoder.RegisterPriority(Decoder{}, decoder.PrioritySpecific)
+}
+
+func (Decoder) Name() string {
+	return "fsp"
+}
+
+func (Decoder) Match(line string) bool {
+	value, ok := decoder.ParsePostCodeLine(line)
+	return ok && IsPostCode(value)
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	value, ok := decoder.ParsePostCodeLine(line)
+	if !ok {
+		return decoder.Record{}, fmt.Errorf("invalid FSP post code line: %q", line)
+	}
+	if !IsPostCode(value) {
+		return decoder.Record{}, fmt.Errorf("not an FSP post code: 0x%X", value)
+	}
+
+	return DecodePostCode(uint16(value)), nil
+}
+
+// IsPostCode reports whether a post code value is in the ranges that
+// FspStatusCode.h documents: a known API and a known module. Platform
+// post codes share the 16-bit space, so the other values are left to
+// the other decoders.
+func IsPostCode(value uint64) bool {
+	if value > 0xFFFF {
+		return false
+	}
+
+	code := uint16(value)
+	_, knownAPI := apiDesc[code&FSP_STATUS_CODE_API_MASK]
+	_, knownModule := moduleDesc[code&FSP_STATUS_CODE_MODULE_MASK]
+	return knownAPI && knownModule
+}
+
+// DecodePostCode decodes an FSP post code into a record.
+func DecodePostCode(code uint16) decoder.Record {
+	api, ok := apiDesc[code&FSP_STATUS_CODE_API_MASK]
+	if !ok {
+		api = "Unknown FSP API"
+	}
+
+	module, ok := moduleDesc[code&FSP_STATUS_CODE_MODULE_MASK]
+	if !ok {
+		module = fmt.Sprintf("Module 0x%X", (code&FSP_STATUS_CODE_MODULE_MASK)>>8)
+	}
+
+	var operation string
+	switch code & FSP_STATUS_CODE_EXECUTION_MASK {
+	case FSP_STATUS_CODE_API_ENTRY:
+		operation = "API Entry"
+	case FSP_STATUS_CODE_API_EXIT:
+		operation = "API Exit"
+	default:
+		operation = fmt.Sprintf("Progress 0x%02X", code&FSP_STATUS_CODE_EXECUTION_MASK)
+	}
+
+	return decoder.Record{
+		Decoder:   "fsp",
+		Kind:      decoder.KindProgress,
+		Phase:     apiPhase[code&FSP_STATUS_CODE_API_MASK],
+		Code:      uint64(code),
+		Class:     "Intel FSP",
+		Subclass:  api,
+		Operation: module + ", " + operation,
+	}
+}
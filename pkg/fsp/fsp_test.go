@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package fsp
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		line  string
+		match bool
+	}{
+		{"POST CODE: 0xD800", true},  // FspMemoryInit, Common Code, API Entry
+		{"POST CODE: 0xDD7F", true},  // FspMemoryInit, MRC, API Exit
+		{"POST CODE: 0x9C10", true},  // FspSiliconInit, CPU
+		{"POST CODE: 0x2000", false}, // no FSP module
+		{"POST CODE: 0x4F", false},
+		{"POST CODE: 0xD000", false},
+		{"POST CODE: 0xE800", false}, // no FSP API
+		{"POST CODE: 0x1D800", false},
+		{"PROGRESS CODE: V03020003 I0", false},
+	}
+	for _, tt := range tests {
+		if got := (Decoder{}).Match(tt.line); got != tt.match {
+			t.Errorf("Match(%q) = %v, want %v", tt.line, got, tt.match)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	record, err := Decoder{}.Decode("POST CODE: 0xD87F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Subclass != "FSP-M FspMemoryInit" || record.Operation != "Common Code, API Exit" {
+		t.Errorf("Decode() = %q, %q", record.Subclass, record.Operation)
+	}
+
+	if _, err := (Decoder{}).Decode("POST CODE: 0x2000"); err == nil {
+		t.Errorf("Decode(0x2000) succeeded")
+	}
+}
@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// The metrics count the records decoded from live consoles and
+// expose them in the Prometheus text format, for alerting on boards
+// reporting errors.
+//
+// The following metrics are exposed, labelled with the console name:
+//
+//	bpd_status_codes_total{console,class,subclass,type,severity}  counter
+//	bpd_errors_total{console,severity}                            counter
+//	bpd_parse_errors_total{console}                               counter
+//	bpd_boot_phase{console,phase}                                 gauge, 1 for the current phase
+//	bpd_boots_total{console,result}                               counter, result is "completed" or "reset"
+//	bpd_boot_duration_seconds{console}                            histogram
+
+// Upper bounds of the boot duration histogram buckets, in seconds
+var DurationBuckets = []float64{5, 10, 20, 30, 45, 60, 90, 120, 180, 300, 600}
+
+// represents the labels of a status code counter
+type codeLabels struct {
+	console  string
+	class    string
+	subclass string
+	kind     string
+	severity string
+}
+
+// represents a Prometheus histogram
+type histogram struct {
+	buckets []uint64 // cumulative counts per bucket of DurationBuckets
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(value float64) {
+	if h.buckets == nil {
+		h.buckets = make([]uint64, len(DurationBuckets))
+	}
+	for i, bound := range DurationBuckets {
+		if value <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// represents the boot state of a console
+type console struct {
+	detector     analysis.BootDetector
+	phase        decoder.Phase
+	booting      bool
+	start        time.Time
+	startStamp   time.Duration
+	hasStartTime bool
+	boots        map[string]uint64
+	errors       map[decoder.Severity]uint64
+	parseErrors  uint64
+	durations    histogram
+}
+
+// Metrics counts the records of several consoles. It is safe for
+// concurrent use, and serves the metrics as an http.Handler.
+type Metrics struct {
+	mu       sync.Mutex
+	codes    map[codeLabels]uint64
+	consoles map[string]*console
+}
+
+func New() *Metrics {
+	return &Metrics{
+		codes:    make(map[codeLabels]uint64),
+		consoles: make(map[string]*console),
+	}
+}
+
+func (m *Metrics) console(name string) *console {
+	c, ok := m.consoles[name]
+	if !ok {
+		c = &console{
+			boots:  make(map[string]uint64),
+			errors: make(map[decoder.Severity]uint64),
+		}
+		m.consoles[name] = c
+	}
+
+	return c
+}
+
+// Observe counts a record decoded from the named console at time now.
+//
+// A boot starts with the first record of a known phase, or at a boot
+// boundary found by analysis.BootDetector, and completes when the OS
+// phase is reached. Its duration is taken from the record time stamps,
+// or from now if the records have none.
+func (m *Metrics) Observe(name string, record decoder.Record, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.console(name)
+	m.codes[codeLabels{
+		console:  name,
+		class:    record.Class,
+		subclass: record.Subclass,
+		kind:     record.Kind.String(),
+		severity: record.Severity.String(),
+	}]++
+	if record.Kind == decoder.KindError {
+		c.errors[record.Severity]++
+	}
+
+	if c.detector.NewBoot(record) && c.booting {
+		c.boots["reset"]++
+		c.booting = false
+	}
+	if record.Phase == decoder.PhaseUnknown {
+		return
+	}
+
+	if !c.booting && record.Phase != decoder.PhaseOS {
+		c.booting = true
+		c.start = now
+		c.startStamp = record.Timestamp
+		c.hasStartTime = record.HasTimestamp
+	}
+	c.phase = record.Phase
+
+	if record.Phase == decoder.PhaseOS && c.booting {
+		duration := now.Sub(c.start)
+		if c.hasStartTime && record.HasTimestamp && record.Timestamp >= c.startStamp {
+			duration = record.Timestamp - c.startStamp
+		}
+		c.durations.observe(duration.Seconds())
+		c.boots["completed"]++
+		c.booting = false
+	}
+}
+
+// ObserveError counts a line of the named console that failed to
+// decode.
+func (m *Metrics) ObserveError(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.console(name).parseErrors++
+}
+
+// labels formats the label pairs of a sample
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Write writes the metrics in the Prometheus text exposition format
+func (m *Metrics) Write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	header := func(name, kind, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	names := make([]string, 0, len(m.consoles))
+	for name := range m.consoles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	codes := make([]codeLabels, 0, len(m.codes))
+	for key := range m.codes {
+		codes = append(codes, key)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := codes[i], codes[j]
+		if a.console != b.console {
+			return a.console < b.console
+		}
+		if a.class != b.class {
+			return a.class < b.class
+		}
+		if a.subclass != b.subclass {
+			return a.subclass < b.subclass
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.severity < b.severity
+	})
+
+	header("bpd_status_codes_total", "counter", "Decoded status codes.")
+	for _, key := range codes {
+		fmt.Fprintf(bw, "bpd_status_codes_total%s %d\n",
+			labels("console", key.console, "class", key.class, "subclass", key.subclass, "type", key.kind, "severity", key.severity),
+			m.codes[key])
+	}
+
+	header("bpd_errors_total", "counter", "Decoded error codes by severity.")
+	for _, name := range names {
+		for severity := decoder.SeverityMinor; severity <= decoder.SeverityUncontained; severity++ {
+			fmt.Fprintf(bw, "bpd_errors_total%s %d\n",
+				labels("console", name, "severity", severity.String()), m.consoles[name].errors[severity])
+		}
+	}
+
+	header("bpd_parse_errors_total", "counter", "Lines that failed to decode.")
+	for _, name := range names {
+		fmt.Fprintf(bw, "bpd_parse_errors_total%s %d\n", labels("console", name), m.consoles[name].parseErrors)
+	}
+
+	header("bpd_boot_phase", "gauge", "Current boot phase of the console, 1 for the current phase.")
+	for _, name := range names {
+		for phase := decoder.PhaseUnknown; phase <= decoder.PhaseOS; phase++ {
+			value := 0
+			if m.consoles[name].phase == phase {
+				value = 1
+			}
+			fmt.Fprintf(bw, "bpd_boot_phase%s %d\n", labels("console", name, "phase", phase.String()), value)
+		}
+	}
+
+	header("bpd_boots_total", "counter", "Boots reaching the OS (completed) or restarting before it (reset).")
+	for _, name := range names {
+		for _, result := range []string{"completed", "reset"} {
+			fmt.Fprintf(bw, "bpd_boots_total%s %d\n", labels("console", name, "result", result), m.consoles[name].boots[result])
+		}
+	}
+
+	header("bpd_boot_duration_seconds", "histogram", "Time from the first code of a boot to the OS phase.")
+	for _, name := range names {
+		h := m.consoles[name].durations
+		for i, bound := range DurationBuckets {
+			var count uint64
+			if h.buckets != nil {
+				count = h.buckets[i]
+			}
+			fmt.Fprintf(bw, "bpd_boot_duration_seconds_bucket%s %d\n", labels("console", name, "le", formatFloat(bound)), count)
+		}
+		fmt.Fprintf(bw, "bpd_boot_duration_seconds_bucket%s %d\n", labels("console", name, "le", "+Inf"), h.count)
+		fmt.Fprintf(bw, "bpd_boot_duration_seconds_sum%s %s\n", labels("console", name), formatFloat(h.sum))
+		fmt.Fprintf(bw, "bpd_boot_duration_seconds_count%s %d\n", labels("console", name), h.count)
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP serves the metrics, e.g. on /metrics
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.Write(w)
+}
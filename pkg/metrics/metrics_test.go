@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// Three boots of a console with time stamps: the first completes in
+// 12s, the second resets in DXE after a major error and the third
+// completes in 4.5s
+const node1Log = `[    0.500000] PROGRESS CODE: V03020002 I0
+[    3.000000] PROGRESS CODE: V03040003 I0
+[    8.000000] PROGRESS CODE: V03051001 I0
+[   12.500000] PROGRESS CODE: V03101019 I0
+[   20.000000] PROGRESS CODE: V03020002 I0
+[   21.000000] PROGRESS CODE: V03040003 I0
+[   21.500000] ERROR: C80000002:V010E0005 I0 55E3774A-EB45-4FD2-AAAE-B7DEEB504A0E
+[   22.000000] DXE ResetSystem2: ResetType Cold, Call Depth = 1.
+[   30.000000] PROGRESS CODE: V03020002 I0
+[   31.000000] PROGRESS CODE: V03040003 I0
+[   34.500000] PROGRESS CODE: V03101019 I0
+[   35.000000] PROGRESS CODE: VXYZ I0
+`
+
+// A console without time stamps, its boot timed by the arrival of its
+// records: 45s to the OS
+const node2Log = `PROGRESS CODE: V03020002 I0
+ERROR: C40000002:V02070002 I0 93B80004-9FB3-11D4-9A3A-0090273FC14D
+PROGRESS CODE: V03040003 I0
+PROGRESS CODE: V03101019 I0
+PROGRESS CODE: V03040003 I0
+`
+
+// observeLog feeds the records of a log to the metrics, the records of
+// line i arriving at start + i*15s
+func observeLog(t *testing.T, m *Metrics, name, log string, start time.Time) {
+	t.Helper()
+
+	lineNo := 0
+	scanner := decoder.NewScanner(edk2.Decoder{})
+	for _, line := range strings.Split(strings.TrimSuffix(log, "\n"), "\n") {
+		now := start.Add(time.Duration(lineNo) * 15 * time.Second)
+		lineNo++
+
+		record, ok, err := scanner.DecodeLine(line)
+		switch {
+		case err != nil:
+			m.ObserveError(name)
+		case ok:
+			m.Observe(name, record, now)
+		default:
+			t.Fatalf("line %q not decoded", line)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	m := New()
+	observeLog(t, m, "node1", node1Log, start)
+	observeLog(t, m, `rack1 "node2"`, node2Log, start)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", ct)
+	}
+	got := rec.Body.String()
+
+	path := "testdata/metrics.txt"
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("metrics differ from %s, run with -update to see the changes:\n%s", path, got)
+	}
+
+	for _, sample := range []string{
+		// boot state machine
+		`bpd_boots_total{console="node1",result="completed"} 2`,
+		`bpd_boots_total{console="node1",result="reset"} 1`,
+		`bpd_boots_total{console="rack1 \"node2\"",result="completed"} 1`,
+		`bpd_boots_total{console="rack1 \"node2\"",result="reset"} 0`,
+		`bpd_boot_phase{console="node1",phase="OS"} 1`,
+		`bpd_boot_phase{console="rack1 \"node2\"",phase="DXE"} 1`,
+		// 4.5s and 12s boots, timed by their time stamps
+		`bpd_boot_duration_seconds_bucket{console="node1",le="5"} 1`,
+		`bpd_boot_duration_seconds_bucket{console="node1",le="10"} 1`,
+		`bpd_boot_duration_seconds_bucket{console="node1",le="20"} 2`,
+		`bpd_boot_duration_seconds_bucket{console="node1",le="+Inf"} 2`,
+		`bpd_boot_duration_seconds_sum{console="node1"} 16.5`,
+		`bpd_boot_duration_seconds_count{console="node1"} 2`,
+		// a 45s boot, timed by the arrival of its records
+		`bpd_boot_duration_seconds_bucket{console="rack1 \"node2\"",le="30"} 0`,
+		`bpd_boot_duration_seconds_bucket{console="rack1 \"node2\"",le="45"} 1`,
+		`bpd_boot_duration_seconds_sum{console="rack1 \"node2\""} 45`,
+		`bpd_errors_total{console="node1",severity="Major Error"} 1`,
+		`bpd_errors_total{console="rack1 \"node2\"",severity="Minor Error"} 1`,
+		`bpd_parse_errors_total{console="node1"} 1`,
+	} {
+		if !strings.Contains(got, sample+"\n") {
+			t.Errorf("metrics lack %s", sample)
+		}
+	}
+}
+
+func TestMetricsEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := New().Write(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the headers of the metrics
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			t.Errorf("sample %q without console", line)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var h histogram
+	for _, value := range []float64{0.5, 5, 5.1, 600, 601} {
+		h.observe(value)
+	}
+
+	// the buckets are cumulative, a value on a bound counts in it
+	want := []uint64{2, 3, 3, 3, 3, 3, 3, 3, 3, 3, 4}
+	for i, count := range h.buckets {
+		if count != want[i] {
+			t.Errorf("bucket le=%v = %d, want %d", DurationBuckets[i], count, want[i])
+		}
+	}
+	if h.count != 5 || h.sum != 1211.6 {
+		t.Errorf("count = %d, sum = %v, want 5, 1211.6", h.count, h.sum)
+	}
+}
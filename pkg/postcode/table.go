@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package postcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// Boards with an 8-bit POST code display (port 0x80 or a debug LED)
+// cannot show a full EFI_STATUS_CODE_VALUE. The platform status code
+// handler maps each value to a checkpoint byte with a table such as:
+//
+//	STATUS_CODE_TO_CHECKPOINT ProgressCheckpointMap[] = {
+//	  { PEI_CORE_STARTED, 0x10 },
+//	  ...
+//	};
+//
+// A table file describes those mappings, one per line, with the
+// status code value followed by the checkpoint byte as C integer
+// literals (0x prefix for hex). C style braces, commas and comments
+// are accepted, but macro names such as PEI_CORE_STARTED and
+// expressions are not: a table pasted from the platform source needs
+// its values expanded first, e.g. with the preprocessor. The [progress]
+// and [error] section headers select the status code type of the
+// following lines (default: progress).
+//
+//	# PEI Core
+//	[progress]
+//	0x03020003  0x10
+//	[error]
+//	{ 0x03020002, 0x50 },
+//
+
+// represents a single status code to checkpoint mapping
+type Entry struct {
+	Type     uint8
+	Value    edk2.EFIStatusCodeValue
+	PostCode uint8
+}
+
+// represents a platform status code to checkpoint table
+type Table struct {
+	Entries []Entry
+	byCode  map[uint8][]Entry
+	byValue map[entryKey]uint8
+}
+
+type entryKey struct {
+	Type  uint8
+	Value uint32
+}
+
+func NewTable(entries []Entry) *Table {
+	t := &Table{
+		Entries: entries,
+		byCode:  make(map[uint8][]Entry),
+		byValue: make(map[entryKey]uint8),
+	}
+
+	for _, entry := range entries {
+		t.byCode[entry.PostCode] = append(t.byCode[entry.PostCode], entry)
+
+		key := entryKey{Type: entry.Type, Value: entry.Value.Uint32()}
+		if _, ok := t.byValue[key]; !ok {
+			t.byValue[key] = entry.PostCode
+		}
+	}
+
+	return t
+}
+
+func ParseTable(r io.Reader) (*Table, error) {
+	var entries []Entry
+	codeType := edk2.EFI_PROGRESS_CODE
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch strings.ToLower(line) {
+		case "":
+			continue
+		case "[progress]":
+			codeType = edk2.EFI_PROGRESS_CODE
+			continue
+		case "[error]":
+			codeType = edk2.EFI_ERROR_CODE
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ' ' || c == '\t' || c == ',' || c == '{' || c == '}' || c == ';'
+		})
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected <status code value> <post code>", lineNo)
+		}
+
+		value, err := strconv.ParseUint(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid status code value %q", lineNo, fields[0])
+		}
+		postCode, err := strconv.ParseUint(fields[1], 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid post code %q", lineNo, fields[1])
+		}
+
+		entries = append(entries, Entry{
+			Type:     codeType,
+			Value:    edk2.NewStatusCodeValue(uint32(value)),
+			PostCode: uint8(postCode),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewTable(entries), nil
+}
+
+func LoadTable(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := ParseTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return t, nil
+}
+
+// Lookup returns the status codes mapped to a checkpoint byte. A byte
+// may be shared by several status codes.
+func (t *Table) Lookup(postCode uint8) []edk2.StatusCode {
+	var codes []edk2.StatusCode
+	for _, entry := range t.byCode[postCode] {
+		codes = append(codes, entry.StatusCode())
+	}
+
+	return codes
+}
+
+// PostCode returns the checkpoint byte the platform shows for a status
+// code.
+func (t *Table) PostCode(code edk2.StatusCode) (uint8, bool) {
+	postCode, ok := t.byValue[entryKey{Type: code.Type.Type, Value: code.Value.Uint32()}]
+	return postCode, ok
+}
+
+func (e Entry) StatusCode() edk2.StatusCode {
+	return edk2.StatusCode{
+		Type:  edk2.EFIStatusCodeType{Type: e.Type},
+		Value: e.Value,
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package postcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+func TestParseTable(t *testing.T) {
+	table, err := ParseTable(strings.NewReader(`# PEI Core
+[progress]
+0x03020003  0x10
+{ 0x03020000, 0x11 },   // C style
+[error]
+{ 0x03020002, 0x50 },
+0x00051009 81
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		codeType uint8
+		value    uint32
+		postCode uint8
+	}{
+		{edk2.EFI_PROGRESS_CODE, 0x03020003, 0x10},
+		{edk2.EFI_PROGRESS_CODE, 0x03020000, 0x11},
+		{edk2.EFI_ERROR_CODE, 0x03020002, 0x50},
+		{edk2.EFI_ERROR_CODE, 0x00051009, 81},
+	}
+	if len(table.Entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d", len(table.Entries), len(tests))
+	}
+	for i, tt := range tests {
+		entry := table.Entries[i]
+		if entry.Type != tt.codeType || entry.Value.Uint32() != tt.value || entry.PostCode != tt.postCode {
+			t.Errorf("entry %d = %d/%08X/%02X, want %d/%08X/%02X", i,
+				entry.Type, entry.Value.Uint32(), entry.PostCode, tt.codeType, tt.value, tt.postCode)
+		}
+	}
+
+	codes := table.Lookup(0x50)
+	if len(codes) != 1 || !codes[0].Type.IsError() || codes[0].Value.Uint32() != 0x03020002 {
+		t.Errorf("Lookup(0x50) = %v", codes)
+	}
+	if postCode, ok := table.PostCode(codes[0]); !ok || postCode != 0x50 {
+		t.Errorf("PostCode() = %02X, %v, want 50, true", postCode, ok)
+	}
+	if _, ok := table.PostCode(edk2.StatusCode{Value: edk2.NewStatusCodeValue(0x03020002)}); ok {
+		t.Errorf("PostCode() of an untyped code found a progress mapping")
+	}
+}
+
+func TestParseTableErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		err   string
+	}{
+		{"macro name", "{ PEI_CORE_STARTED, 0x10 },", `line 1: invalid status code value "PEI_CORE_STARTED"`},
+		{"expression", "(EFI_SOFTWARE_PEI_CORE | EFI_SW_PC_INIT) 0x10", "line 1: expected <status code value> <post code>"},
+		{"missing post code", "\n0x03020003", "line 2: expected <status code value> <post code>"},
+		{"post code overflow", "0x03020003 0x100", `line 1: invalid post code "0x100"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTable(strings.NewReader(tt.table))
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseTable() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// The Chrome trace is written in the Trace Event Format read by
+// chrome://tracing and Perfetto:
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+
+// represents an event of the Trace Event Format
+type traceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat,omitempty"`
+	Phase     string         `json:"ph"`
+	Timestamp float64        `json:"ts"`
+	Duration  *float64       `json:"dur,omitempty"`
+	Scope     string         `json:"s,omitempty"`
+	Pid       int            `json:"pid"`
+	Tid       int            `json:"tid"`
+	Color     string         `json:"cname,omitempty"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent      `json:"traceEvents"`
+	DisplayTimeUnit string            `json:"displayTimeUnit"`
+	OtherData       map[string]string `json:"otherData,omitempty"`
+}
+
+// Process and track of the events. The phases are on the first track,
+// the status codes of each decoder and instance on their own track.
+const (
+	tracePid      = 1
+	tracePhaseTid = 0
+)
+
+// represents the track of a decoder instance
+type traceTrack struct {
+	decoder  string
+	instance uint32
+}
+
+// traceTimes returns the time of each record in microseconds. Records
+// without a time stamp take the time of the record before them. When
+// no record has a time stamp, the records are placed 1ms apart in log
+// order.
+func traceTimes(records []decoder.Record) ([]float64, bool) {
+	timeBased := false
+	for _, record := range records {
+		timeBased = timeBased || record.HasTimestamp
+	}
+
+	times := make([]float64, len(records))
+	last := 0.0
+	for i, record := range records {
+		switch {
+		case !timeBased:
+			last = float64(i) * 1000
+		case record.HasTimestamp:
+			last = float64(record.Timestamp) / float64(time.Microsecond)
+		}
+		times[i] = last
+	}
+
+	return times, timeBased
+}
+
+func recordArgs(record decoder.Record) map[string]any {
+	args := map[string]any{
+		"class":     record.Class,
+		"subclass":  record.Subclass,
+		"operation": record.Operation,
+		"code":      fmt.Sprintf("0x%08X", record.Code),
+		"line":      strings.TrimSpace(record.Line),
+	}
+	if record.LineNo != 0 {
+		args["line_no"] = record.LineNo
+	}
+	if record.Kind == decoder.KindError {
+		args["severity"] = record.Severity.String()
+	}
+	if record.Module != "" {
+		args["module"] = record.Module
+	}
+	for _, field := range record.Fields {
+		args[field.Name] = field.Value
+	}
+
+	return args
+}
+
+func recordName(record decoder.Record) string {
+	if record.Operation != "" {
+		return record.Operation
+	}
+	if record.Subclass != "" {
+		return record.Subclass
+	}
+
+	return record.Class
+}
+
+// WriteChromeTrace writes the records as a Chrome trace: the boot and
+// its phases as nested slices, and the status codes as instant events
+// on a track per decoder and instance. Errors are process wide instant
+// events in the "error" category. Phases must have been assigned with
+// analysis.AssignPhases.
+func WriteChromeTrace(w io.Writer, name string, records []decoder.Record) error {
+	times, timeBased := traceTimes(records)
+
+	trace := traceFile{DisplayTimeUnit: "ms"}
+	if !timeBased {
+		trace.OtherData = map[string]string{"time": "the log has no time stamps, records are 1ms apart"}
+	}
+
+	meta := func(tid int, kind, value string) {
+		trace.TraceEvents = append(trace.TraceEvents, traceEvent{
+			Name: kind, Phase: "M", Pid: tracePid, Tid: tid,
+			Args: map[string]any{"name": value},
+		})
+	}
+	meta(tracePhaseTid, "process_name", "Firmware: "+name)
+	meta(tracePhaseTid, "thread_name", "Boot phases")
+
+	slice := func(name string, start, end float64, args map[string]any) {
+		dur := end - start
+		trace.TraceEvents = append(trace.TraceEvents, traceEvent{
+			Name: name, Category: "phase", Phase: "X", Timestamp: start, Duration: &dur,
+			Pid: tracePid, Tid: tracePhaseTid, Args: args,
+		})
+	}
+
+	if len(records) > 0 {
+		slice("Boot", times[0], times[len(times)-1], map[string]any{"records": len(records)})
+	}
+
+	// a span ends where the next one starts
+	first := 0
+	for _, span := range analysis.Timeline(records) {
+		next := first + span.Records
+		end := times[len(times)-1]
+		if next < len(times) {
+			end = times[next]
+		}
+		if span.Phase != decoder.PhaseUnknown {
+			slice(span.Phase.String(), times[first], end, map[string]any{
+				"records": span.Records,
+				"errors":  span.Errors,
+			})
+		}
+		first = next
+	}
+
+	// the instance is only named for decoders reporting several
+	instances := make(map[string]map[uint32]bool)
+	for _, record := range records {
+		if instances[record.Decoder] == nil {
+			instances[record.Decoder] = make(map[uint32]bool)
+		}
+		instances[record.Decoder][record.Instance] = true
+	}
+
+	tracks := make(map[traceTrack]int)
+	for i, record := range records {
+		track := traceTrack{record.Decoder, record.Instance}
+		tid, ok := tracks[track]
+		if !ok {
+			tid = len(tracks) + 1
+			tracks[track] = tid
+
+			name := record.Decoder
+			if len(instances[record.Decoder]) > 1 {
+				name = fmt.Sprintf("%s instance %d", record.Decoder, record.Instance)
+			}
+			meta(tid, "thread_name", name)
+		}
+
+		event := traceEvent{
+			Name: recordName(record), Category: "status", Phase: "i", Timestamp: times[i],
+			Scope: "t", Pid: tracePid, Tid: tid, Args: recordArgs(record),
+		}
+		if record.Kind == decoder.KindError {
+			event.Category = "error"
+			event.Scope = "p"
+			event.Color = "terrible"
+			event.Name = record.Severity.String() + ": " + event.Name
+		}
+		trace.TraceEvents = append(trace.TraceEvents, event)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(trace)
+}
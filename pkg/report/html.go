@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// The HTML report is a single file with inline styles and scripts, so
+// it can be mailed or attached to a ticket and opened offline.
+
+// represents a bar of the phase chart, in percent of the chart width
+type ganttBar struct {
+	Phase    string
+	Left     float64
+	Width    float64
+	Row      int
+	Label    string
+	HasError bool
+}
+
+// represents a row of the record table
+type htmlRecord struct {
+	LineNo    int
+	Time      string
+	Phase     string
+	Kind      string
+	Severity  string
+	Level     int
+	Decoder   string
+	Class     string
+	Subclass  string
+	Operation string
+	Module    string
+	Fields    []decoder.Field
+	Line      string
+}
+
+type htmlData struct {
+	Title     string
+	Generated string
+	TimeBased bool
+	Bars      []ganttBar
+	Phases    []analysis.PhaseSpan
+	Records   []htmlRecord
+	Findings  []htmlRecord
+	Errors    int
+}
+
+var severityClass = map[decoder.Severity]string{
+	decoder.SeverityMinor:       "minor",
+	decoder.SeverityMajor:       "major",
+	decoder.SeverityUnrecovered: "unrecovered",
+	decoder.SeverityUncontained: "uncontained",
+}
+
+func formatSeconds(d time.Duration, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.6fs", d.Seconds())
+}
+
+// ganttBars lays the phase spans out on a time axis, or on a record
+// count axis when the log has no time stamps
+func ganttBars(spans []analysis.PhaseSpan) ([]ganttBar, bool) {
+	timeBased := len(spans) > 0
+	for _, span := range spans {
+		timeBased = timeBased && span.HasTimestamp
+	}
+
+	var start, total float64
+	if timeBased {
+		start = spans[0].Start.Seconds()
+		total = spans[len(spans)-1].End.Seconds() - start
+	} else {
+		for _, span := range spans {
+			total += float64(span.Records)
+		}
+	}
+	if total <= 0 {
+		total = 1
+	}
+
+	// one row per phase, in boot order
+	rows := make(map[decoder.Phase]int)
+	for phase := decoder.PhaseUnknown; phase <= decoder.PhaseOS; phase++ {
+		for _, span := range spans {
+			if span.Phase == phase {
+				rows[phase] = len(rows)
+				break
+			}
+		}
+	}
+
+	var bars []ganttBar
+	offset := 0.0
+	for _, span := range spans {
+		bar := ganttBar{Phase: span.Phase.String(), Row: rows[span.Phase], HasError: span.Errors > 0}
+		if timeBased {
+			bar.Left = (span.Start.Seconds() - start) / total * 100
+			bar.Width = span.Duration().Seconds() / total * 100
+			bar.Label = fmt.Sprintf("%s: %s", span.Phase, formatSeconds(span.Duration(), true))
+		} else {
+			bar.Left = offset / total * 100
+			bar.Width = float64(span.Records) / total * 100
+			bar.Label = fmt.Sprintf("%s: %d records", span.Phase, span.Records)
+			offset += float64(span.Records)
+		}
+		if bar.Width < 0.5 {
+			bar.Width = 0.5
+		}
+		if bar.Left+bar.Width > 100 {
+			bar.Left = 100 - bar.Width
+		}
+		bars = append(bars, bar)
+	}
+
+	return bars, timeBased
+}
+
+// WriteHTML writes a self-contained HTML report of the records: the
+// phase chart, the fatal exceptions and asserts, and a filterable
+// table of the decoded records. Phases must have been assigned with
+// analysis.AssignPhases.
+func WriteHTML(w io.Writer, title string, records []decoder.Record) error {
+	spans := analysis.Timeline(records)
+	bars, timeBased := ganttBars(spans)
+
+	data := htmlData{
+		Title:     title,
+		Generated: time.Now().Format(time.RFC3339),
+		TimeBased: timeBased,
+		Bars:      bars,
+		Phases:    spans,
+	}
+
+	for _, record := range records {
+		row := htmlRecord{
+			LineNo:    record.LineNo,
+			Time:      formatSeconds(record.Timestamp, record.HasTimestamp),
+			Phase:     record.Phase.String(),
+			Kind:      record.Kind.String(),
+			Severity:  record.Severity.String(),
+			Level:     int(record.Severity),
+			Decoder:   record.Decoder,
+			Class:     record.Class,
+			Subclass:  record.Subclass,
+			Operation: record.Operation,
+			Module:    record.Module,
+			Fields:    record.Fields,
+			Line:      strings.TrimSpace(record.Line),
+		}
+		data.Records = append(data.Records, row)
+
+		if record.Kind == decoder.KindError {
+			data.Errors++
+			if record.Severity >= decoder.SeverityUnrecovered {
+				data.Findings = append(data.Findings, row)
+			}
+		}
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"severityClass": func(level int) string { return severityClass[decoder.Severity(level)] },
+	"seconds":       formatSeconds,
+	"rowTop":        func(row int) int { return row*28 + 4 },
+	"chartHeight":   func(bars []ganttBar) int { return (maxRow(bars)+1)*28 + 8 },
+}).Parse(htmlSource))
+
+func maxRow(bars []ganttBar) int {
+	n := 0
+	for _, bar := range bars {
+		if bar.Row > n {
+			n = bar.Row
+		}
+	}
+
+	return n
+}
+
+const htmlSource = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - Boot Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; width: 100%; font-size: 0.85em; }
+th, td { border: 1px solid #ddd; padding: 4px 6px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; position: sticky; top: 0; }
+code { font-size: 0.95em; word-break: break-all; }
+.chart { position: relative; border: 1px solid #ccc; background: #fafafa; }
+.bar { position: absolute; height: 22px; border-radius: 3px; color: #fff; font-size: 0.75em; line-height: 22px;
+       padding-left: 4px; overflow: hidden; white-space: nowrap; box-sizing: border-box; }
+.bar.error { outline: 2px solid #c00; }
+.SEC { background: #6c7a89; } .PEI { background: #2e86c1; } .DXE { background: #28b463; }
+.BDS { background: #d68910; } .OS { background: #8e44ad; } .Unknown { background: #999; }
+tr.minor { background: #fff8d0; } tr.major { background: #ffe0c0; }
+tr.unrecovered { background: #ffc8c8; } tr.uncontained { background: #f4a0a0; }
+.filters { margin: 1em 0; display: flex; gap: 1em; align-items: center; }
+.finding { border-left: 6px solid #c00; background: #fff0f0; padding: 0.5em 1em; margin: 0.5em 0; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>Boot Report: {{.Title}}</h1>
+<p class="muted">Generated {{.Generated}} &middot; {{len .Records}} records &middot; {{.Errors}} errors</p>
+
+<h2>Phase Timeline</h2>
+{{if .Bars}}
+<p class="muted">{{if .TimeBased}}Bars are placed by time stamp.{{else}}The log has no time stamps, bars are sized by record count.{{end}}</p>
+<div class="chart" style="height: {{chartHeight .Bars}}px">
+{{range .Bars}}<div class="bar {{.Phase}}{{if .HasError}} error{{end}}" style="left: {{printf "%.3f" .Left}}%; width: {{printf "%.3f" .Width}}%; top: {{rowTop .Row}}px" title="{{.Label}}">{{.Label}}</div>
+{{end}}</div>
+<table>
+<tr><th>Phase</th><th>Start</th><th>End</th><th>Duration</th><th>Lines</th><th>Records</th><th>Errors</th></tr>
+{{range .Phases}}<tr><td>{{.Phase}}</td><td>{{seconds .Start .HasTimestamp}}</td><td>{{seconds .End .HasTimestamp}}</td><td>{{seconds .Duration .HasTimestamp}}</td><td>{{if .FirstLine}}{{.FirstLine}}-{{.LastLine}}{{else}}-{{end}}</td><td>{{.Records}}</td><td>{{.Errors}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No records.</p>
+{{end}}
+
+<h2>Exceptions and Asserts</h2>
+{{range .Findings}}<div class="finding">
+<strong>Line {{.LineNo}}: {{.Severity}} - {{.Class}} / {{.Subclass}} / {{.Operation}}</strong><br>
+<code>{{.Line}}</code>
+{{if .Module}}<br>Module: <code>{{.Module}}</code>{{end}}
+{{range .Fields}}<br>{{.Name}}: <code>{{.Value}}</code>{{end}}
+</div>
+{{else}}
+<p>No unrecovered or uncontained errors.</p>
+{{end}}
+
+<h2>Decoded Records</h2>
+<div class="filters">
+<label>Search <input id="search" type="search" size="40"></label>
+<label>Minimum severity
+<select id="severity">
+<option value="0">All records</option>
+<option value="1">Minor Error</option>
+<option value="2">Major Error</option>
+<option value="3">Unrecovered Error</option>
+<option value="4">Uncontained Error</option>
+</select></label>
+<span id="count" class="muted"></span>
+</div>
+<table id="records">
+<thead><tr><th>Line</th><th>Time</th><th>Phase</th><th>Kind</th><th>Severity</th><th>Class</th><th>Subclass</th><th>Operation</th><th>Module</th><th>Source</th></tr></thead>
+<tbody>
+{{range .Records}}<tr class="{{severityClass .Level}}" data-level="{{.Level}}"><td>{{.LineNo}}</td><td>{{.Time}}</td><td>{{.Phase}}</td><td>{{.Kind}}</td><td>{{.Severity}}</td><td>{{.Class}}</td><td>{{.Subclass}}</td><td>{{.Operation}}{{range .Fields}}<br><span class="muted">{{.Name}}: {{.Value}}</span>{{end}}</td><td><code>{{.Module}}</code></td><td><code title="{{.Decoder}}">{{.Line}}</code></td></tr>
+{{end}}</tbody>
+</table>
+
+<script>
+(function () {
+  var search = document.getElementById("search");
+  var severity = document.getElementById("severity");
+  var count = document.getElementById("count");
+  var rows = document.querySelectorAll("#records tbody tr");
+
+  function filter() {
+    var text = search.value.toLowerCase();
+    var level = parseInt(severity.value, 10);
+    var shown = 0;
+    for (var i = 0; i < rows.length; i++) {
+      var row = rows[i];
+      var visible = parseInt(row.dataset.level, 10) >= level &&
+        (text === "" || row.textContent.toLowerCase().indexOf(text) >= 0);
+      row.style.display = visible ? "" : "none";
+      if (visible) shown++;
+    }
+    count.textContent = shown + " of " + rows.length + " records";
+  }
+
+  search.addEventListener("input", filter);
+  severity.addEventListener("change", filter);
+  filter();
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Below is the JUnit XML layout understood by Jenkins and GitLab:
+//
+// <testsuites>
+//   <testsuite name="boot.log" tests="7" failures="1" skipped="0" time="3.010">
+//     <testcase name="reach DXE" classname="boot.log.phases" time="1.100"/>
+//     <testcase name="line 42: ..." classname="boot.log.errors" time="0">
+//       <failure type="MajorError" message="...">decoded record</failure>
+//     </testcase>
+//   </testsuite>
+// </testsuites>
+//
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes the test suites as JUnit XML.
+func WriteJUnit(w io.Writer, suites ...*TestSuite) error {
+	doc := junitSuites{}
+	for _, suite := range suites {
+		js := junitSuite{
+			Name:     suite.Name,
+			Tests:    len(suite.Cases),
+			Failures: suite.Failures(),
+			Skipped:  suite.Skips(),
+		}
+
+		var total time.Duration
+		for _, c := range suite.Cases {
+			jc := junitCase{Name: c.Name, ClassName: c.Class, Time: seconds(c.Time)}
+			if c.Failure != nil {
+				jc.Failure = &junitFailure{Type: c.Failure.Type, Message: c.Failure.Message, Text: c.Failure.Text}
+			}
+			if c.Skipped != "" {
+				jc.Skipped = &junitSkipped{Message: c.Skipped}
+			}
+			if strings.HasSuffix(c.Class, ".phases") {
+				total += c.Time
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		js.Time = seconds(total)
+
+		doc.Suites = append(doc.Suites, js)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// The trace is written in the OTLP/JSON encoding of an
+// ExportTraceServiceRequest, as accepted by OpenTelemetry collectors
+// on /v1/traces:
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+// Path of the OTLP/HTTP trace endpoint
+const OTLPTracesPath = "/v1/traces"
+
+// OTLP span kind and status codes
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusError      = 2
+)
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+// represents a boot converted to an OTLP trace
+type OTLPTrace struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func otlpString(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int) otlpAttribute {
+	s := strconv.Itoa(value)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+// otlpAttributes converts the arguments of a record, sorted by key
+func otlpAttributes(args map[string]any) []otlpAttribute {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		switch value := args[key].(type) {
+		case int:
+			attributes = append(attributes, otlpInt(key, value))
+		default:
+			attributes = append(attributes, otlpString(key, fmt.Sprint(value)))
+		}
+	}
+
+	return attributes
+}
+
+func randomID(n int) (string, error) {
+	id := make([]byte, n)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// NewOTLPTrace converts the records of a boot to a trace: a root span
+// for the boot, a child span per phase and, below the phases, a span
+// per run of records reported by the same module. Errors are events of
+// the innermost span, carrying the class, subclass, operation and
+// severity. The record time stamps are offsets from start, the time of
+// the processor reset. Phases must have been assigned with
+// analysis.AssignPhases.
+func NewOTLPTrace(name string, start time.Time, records []decoder.Record) (*OTLPTrace, error) {
+	traceID, err := randomID(16)
+	if err != nil {
+		return nil, err
+	}
+
+	// records past the last one, or before the first when there are
+	// none, are at the time of the last record
+	times, _ := traceTimes(records)
+	at := func(i int) string {
+		var offset float64
+		switch {
+		case i >= 0 && i < len(times):
+			offset = times[i]
+		case len(times) > 0:
+			offset = times[len(times)-1]
+		}
+		return strconv.FormatInt(start.UnixNano()+int64(offset*float64(time.Microsecond)), 10)
+	}
+
+	// spans are referenced by index, as the slice grows while they
+	// are built
+	var spans []otlpSpan
+	newSpan := func(parent int, name string, first, end int) (int, error) {
+		spanID, err := randomID(8)
+		if err != nil {
+			return 0, err
+		}
+
+		span := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            spanID,
+			Name:              name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: at(first),
+			EndTimeUnixNano:   at(end),
+		}
+		if parent >= 0 {
+			span.ParentSpanID = spans[parent].SpanID
+		}
+		spans = append(spans, span)
+		return len(spans) - 1, nil
+	}
+
+	addEvent := func(span, i int) {
+		record := records[i]
+		spans[span].Events = append(spans[span].Events, otlpEvent{
+			TimeUnixNano: at(i),
+			Name:         record.Severity.String() + ": " + recordName(record),
+			Attributes:   otlpAttributes(recordArgs(record)),
+		})
+		spans[span].Status = otlpStatus{Code: otlpStatusError, Message: record.Severity.String()}
+	}
+
+	errors := 0
+	for _, record := range records {
+		if record.Kind == decoder.KindError {
+			errors++
+		}
+	}
+
+	root, err := newSpan(-1, "Boot "+name, 0, len(records)-1)
+	if err != nil {
+		return nil, err
+	}
+	spans[root].Attributes = []otlpAttribute{
+		otlpString("boot.log", name),
+		otlpInt("boot.records", len(records)),
+		otlpInt("boot.errors", errors),
+	}
+	if len(records) > 0 {
+		spans[root].Attributes = append(spans[root].Attributes, otlpString("boot.last_phase", records[len(records)-1].Phase.String()))
+	}
+
+	// a span ends where the next one starts
+	first := 0
+	for _, phaseSpan := range analysis.Timeline(records) {
+		next := first + phaseSpan.Records
+
+		parent := root
+		if phaseSpan.Phase != decoder.PhaseUnknown {
+			if parent, err = newSpan(root, phaseSpan.Phase.String(), first, next); err != nil {
+				return nil, err
+			}
+			spans[parent].Attributes = []otlpAttribute{
+				otlpString("boot.phase", phaseSpan.Phase.String()),
+				otlpInt("boot.records", phaseSpan.Records),
+				otlpInt("boot.errors", phaseSpan.Errors),
+			}
+		}
+
+		for i := first; i < next; {
+			module := records[i].Module
+			if module == "" {
+				if records[i].Kind == decoder.KindError {
+					addEvent(parent, i)
+				}
+				i++
+				continue
+			}
+
+			end := i
+			for end < next && records[end].Module == module {
+				end++
+			}
+			span, err := newSpan(parent, module, i, end)
+			if err != nil {
+				return nil, err
+			}
+			spans[span].Attributes = []otlpAttribute{otlpString("boot.module", module)}
+			for ; i < end; i++ {
+				if records[i].Kind == decoder.KindError {
+					addEvent(span, i)
+				}
+			}
+		}
+
+		first = next
+	}
+
+	// the boot fails on any error, the spans below only on their own
+	if errors > 0 {
+		spans[root].Status = otlpStatus{Code: otlpStatusError, Message: fmt.Sprintf("%d error codes", errors)}
+	}
+
+	return &OTLPTrace{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{otlpString("service.name", "firmware")}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "boot-progress-decoder"},
+			Spans: spans,
+		}},
+	}}}, nil
+}
+
+// Write writes the trace as OTLP/JSON
+func (t *OTLPTrace) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(t)
+}
+
+// Push sends the trace to an OTLP/HTTP endpoint. OTLPTracesPath is
+// added to endpoints without a path. headers are added to the request,
+// e.g. for authentication.
+func (t *OTLPTrace) Push(client *http.Client, endpoint string, headers map[string]string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = OTLPTracesPath
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(t); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s %s", u, resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+func TestNewOTLPTraceEmpty(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	trace, err := NewOTLPTrace("empty.log", start, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want the boot span only", len(spans))
+	}
+	want := strconv.FormatInt(start.UnixNano(), 10)
+	if spans[0].StartTimeUnixNano != want || spans[0].EndTimeUnixNano != want {
+		t.Errorf("boot span from %s to %s, want %s", spans[0].StartTimeUnixNano, spans[0].EndTimeUnixNano, want)
+	}
+	if spans[0].Status.Code != 0 {
+		t.Errorf("boot span status = %+v, want unset", spans[0].Status)
+	}
+}
+
+func TestNewOTLPTrace(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	records := []decoder.Record{
+		{Kind: decoder.KindProgress, Phase: decoder.PhasePEI, Timestamp: time.Millisecond, HasTimestamp: true},
+		{Kind: decoder.KindProgress, Phase: decoder.PhaseDXE, Module: "PciBus", Timestamp: 2 * time.Millisecond, HasTimestamp: true},
+		{Kind: decoder.KindError, Phase: decoder.PhaseDXE, Module: "PciBus", Severity: decoder.SeverityMajor, Timestamp: 3 * time.Millisecond, HasTimestamp: true},
+	}
+	trace, err := NewOTLPTrace("boot.log", start, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	want := []string{"Boot boot.log", "PEI", "DXE", "PciBus"}
+	if len(names) != len(want) {
+		t.Fatalf("spans = %q, want %q", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("spans = %q, want %q", names, want)
+		}
+	}
+
+	module := spans[3]
+	if module.ParentSpanID != spans[2].SpanID {
+		t.Errorf("PciBus span parent = %s, want the DXE span %s", module.ParentSpanID, spans[2].SpanID)
+	}
+	if len(module.Events) != 1 || module.Status.Code != otlpStatusError {
+		t.Errorf("PciBus span events = %d, status = %+v, want the error event", len(module.Events), module.Status)
+	}
+	if spans[0].Status.Code != otlpStatusError {
+		t.Errorf("boot span status = %+v, want an error", spans[0].Status)
+	}
+	if got := strconv.FormatInt(start.Add(time.Millisecond).UnixNano(), 10); spans[0].StartTimeUnixNano != got {
+		t.Errorf("boot span starts at %s, want %s", spans[0].StartTimeUnixNano, got)
+	}
+}
@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// testdata/boot.log reaches BDS through PEI and DXE, reports a minor and
+// a major error, then asserts; its last line fails to decode
+func readBootLog(t *testing.T) ([]decoder.Record, []error) {
+	t.Helper()
+
+	f, err := os.Open("testdata/boot.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []decoder.Record
+	var parseErrors []error
+	err = decoder.NewScanner(edk2.Decoder{}).Scan(f, func(record decoder.Record, err error) error {
+		if err != nil {
+			parseErrors = append(parseErrors, err)
+			return nil
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis.AssignPhases(records)
+
+	return records, parseErrors
+}
+
+// checkGolden compares the output with the golden file, or rewrites the
+// file when run with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := "testdata/" + name
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run with -update to see the changes:\n%s", path, got)
+	}
+}
+
+func TestNewTestSuite(t *testing.T) {
+	records, parseErrors := readBootLog(t)
+
+	tests := []struct {
+		failOn decoder.Severity
+		want   []string
+	}{
+		{decoder.SeverityMinor, []string{
+			"skip reach SEC", "ok reach PEI", "ok reach DXE", "ok reach BDS", "fail PhaseNotReached reach OS",
+			"fail MinorError line 4: I/O Bus / SCSI / Not Supported",
+			"fail MajorError line 5: Peripheral / TPM / Interface Error",
+			"fail UnrecoveredError line 7: EDK2 / Assert / !(((INTN)(RETURN_STATUS)(Status)) < 0)",
+			"fail ParseError " + parseErrors[0].Error(),
+		}},
+		{decoder.SeverityUnrecovered, []string{
+			"skip reach SEC", "ok reach PEI", "ok reach DXE", "ok reach BDS", "fail PhaseNotReached reach OS",
+			"ok line 4: I/O Bus / SCSI / Not Supported",
+			"ok line 5: Peripheral / TPM / Interface Error",
+			"fail UnrecoveredError line 7: EDK2 / Assert / !(((INTN)(RETURN_STATUS)(Status)) < 0)",
+			"fail ParseError " + parseErrors[0].Error(),
+		}},
+		{decoder.SeverityNone, []string{
+			"skip reach SEC", "ok reach PEI", "ok reach DXE", "ok reach BDS", "fail PhaseNotReached reach OS",
+			"ok line 4: I/O Bus / SCSI / Not Supported",
+			"ok line 5: Peripheral / TPM / Interface Error",
+			"ok line 7: EDK2 / Assert / !(((INTN)(RETURN_STATUS)(Status)) < 0)",
+			"fail ParseError " + parseErrors[0].Error(),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.failOn.String(), func(t *testing.T) {
+			suite := NewTestSuite("boot.log", records, parseErrors, tt.failOn)
+
+			var got []string
+			for _, c := range suite.Cases {
+				switch {
+				case c.Skipped != "":
+					got = append(got, "skip "+c.Name)
+				case c.Failure != nil:
+					got = append(got, "fail "+c.Failure.Type+" "+c.Name)
+				default:
+					got = append(got, "ok "+c.Name)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("test cases =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	records, parseErrors := readBootLog(t)
+	suite := NewTestSuite("boot.log", records, parseErrors, decoder.SeverityMinor)
+
+	var b bytes.Buffer
+	if err := WriteJUnit(&b, suite); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "boot.junit.xml", b.Bytes())
+
+	var doc junitSuites
+	if err := xml.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if s := doc.Suites[0]; s.Tests != 9 || s.Failures != 5 || s.Skipped != 1 || s.Time != "1.600" {
+		t.Errorf("suite = %d tests, %d failures, %d skipped in %s, want 9, 5, 1 in 1.600",
+			s.Tests, s.Failures, s.Skipped, s.Time)
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	records, parseErrors := readBootLog(t)
+	suite := NewTestSuite("boot.log", records, parseErrors, decoder.SeverityMinor)
+
+	var b bytes.Buffer
+	if err := WriteTAP(&b, suite, NewTestSuite("empty.log", nil, nil, decoder.SeverityMinor)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "boot.tap", b.Bytes())
+
+	// the plan counts the cases of every suite
+	if !strings.HasPrefix(b.String(), "TAP version 13\n1..14\n") {
+		t.Errorf("TAP output does not start with the version and a plan of 14 tests:\n%s", b.String())
+	}
+}
+
+func TestWriteChromeTrace(t *testing.T) {
+	records, _ := readBootLog(t)
+
+	var b bytes.Buffer
+	if err := WriteChromeTrace(&b, "boot.log", records); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "boot.trace.json", b.Bytes())
+
+	var trace traceFile
+	if err := json.Unmarshal(b.Bytes(), &trace); err != nil {
+		t.Fatalf("invalid trace JSON: %v", err)
+	}
+
+	// the phase slices nest in the boot slice and follow each other
+	var boot *traceEvent
+	var phases []traceEvent
+	errors := 0
+	for i, event := range trace.TraceEvents {
+		switch {
+		case event.Phase == "X" && event.Name == "Boot":
+			boot = &trace.TraceEvents[i]
+		case event.Phase == "X":
+			phases = append(phases, event)
+		case event.Category == "error":
+			errors++
+			if event.Phase != "i" || event.Scope != "p" || event.Color == "" {
+				t.Errorf("error event %q is not a flagged process wide instant event", event.Name)
+			}
+		}
+	}
+	if boot == nil || len(phases) != 3 {
+		t.Fatalf("got %d phase slices, want a boot slice and 3 phases", len(phases))
+	}
+	for i, phase := range phases {
+		if phase.Timestamp < boot.Timestamp || phase.Timestamp+*phase.Duration > boot.Timestamp+*boot.Duration {
+			t.Errorf("%s slice is not in the boot slice", phase.Name)
+		}
+		if i > 0 && phase.Timestamp != phases[i-1].Timestamp+*phases[i-1].Duration {
+			t.Errorf("%s slice does not start where %s ends", phase.Name, phases[i-1].Name)
+		}
+	}
+	if errors != 3 {
+		t.Errorf("got %d error events, want 3", errors)
+	}
+}
+
+func TestWriteChromeTraceWithoutTimestamps(t *testing.T) {
+	records, _ := readBootLog(t)
+	for i := range records {
+		records[i].HasTimestamp = false
+	}
+
+	var b bytes.Buffer
+	if err := WriteChromeTrace(&b, "boot.log", records); err != nil {
+		t.Fatal(err)
+	}
+
+	var trace traceFile
+	if err := json.Unmarshal(b.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	if trace.OtherData["time"] == "" {
+		t.Errorf("trace does not tell the records are placed 1ms apart")
+	}
+
+	var last float64
+	for _, event := range trace.TraceEvents {
+		if event.Phase == "i" {
+			last = event.Timestamp
+		}
+	}
+	if last != float64(len(records)-1)*1000 {
+		t.Errorf("last record at %vus, want %vus", last, float64(len(records)-1)*1000)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	records, _ := readBootLog(t)
+
+	var b bytes.Buffer
+	if err := WriteHTML(&b, `boot.log <node1>`, records); err != nil {
+		t.Fatal(err)
+	}
+	html := b.String()
+
+	// the report is opened offline, nothing may be loaded from
+	// elsewhere
+	if m := regexp.MustCompile(`(?i)\s(src|href)\s*=|@import|url\(`).FindString(html); m != "" {
+		t.Errorf("report references an external resource: %q", m)
+	}
+	if !strings.Contains(html, "<style>") || !strings.Contains(html, "<script>") {
+		t.Errorf("report has no inline style or script")
+	}
+
+	for _, want := range []string{
+		"<title>boot.log &lt;node1&gt; - Boot Report</title>",
+		`<div class="bar PEI" style="left: 0.000%; width: 43.750%; top: 4px" title="PEI: 0.700000s">`,
+		`<div class="bar DXE error" style="left: 43.750%; width: 50.000%; top: 32px" title="DXE: 0.800000s">`,
+		`<div class="bar BDS error"`,
+		// only the unrecovered assert is a finding
+		"<strong>Line 7: Unrecovered Error - EDK2 / Assert / !(((INTN)(RETURN_STATUS)(Status)) &lt; 0)</strong>",
+		"File: <code>/build/MdeModulePkg/Bus/Pci/PciBusDxe/PciLib.c</code>",
+		`<tr class="major" data-level="2"><td>5</td><td>1.500000s</td><td>DXE</td>`,
+		"7 records &middot; 3 errors",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report lacks %s", want)
+		}
+	}
+	if n := strings.Count(html, `<div class="finding">`); n != 1 {
+		t.Errorf("got %d findings, want 1", n)
+	}
+}
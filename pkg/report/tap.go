@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTAP writes the test suites in the Test Anything Protocol,
+// version 13. Failures carry a YAML block with the decoded record.
+func WriteTAP(w io.Writer, suites ...*TestSuite) error {
+	bw := bufio.NewWriter(w)
+
+	total := 0
+	for _, suite := range suites {
+		total += len(suite.Cases)
+	}
+	fmt.Fprintln(bw, "TAP version 13")
+	fmt.Fprintf(bw, "1..%d\n", total)
+
+	n := 0
+	for _, suite := range suites {
+		for _, c := range suite.Cases {
+			n++
+			name := strings.ReplaceAll(c.Class+": "+c.Name, "#", "\\#")
+
+			switch {
+			case c.Skipped != "":
+				fmt.Fprintf(bw, "ok %d - %s # SKIP %s\n", n, name, c.Skipped)
+			case c.Failure != nil:
+				fmt.Fprintf(bw, "not ok %d - %s\n", n, name)
+				fmt.Fprintln(bw, "  ---")
+				fmt.Fprintf(bw, "  type: %q\n", c.Failure.Type)
+				fmt.Fprintf(bw, "  message: %q\n", c.Failure.Message)
+				if c.Failure.Text != "" {
+					fmt.Fprintln(bw, "  details: |")
+					for _, line := range strings.Split(strings.TrimRight(c.Failure.Text, "\n"), "\n") {
+						fmt.Fprintf(bw, "    %s\n", line)
+					}
+				}
+				fmt.Fprintln(bw, "  ...")
+			default:
+				fmt.Fprintf(bw, "ok %d - %s\n", n, name)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/analysis"
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Boot milestones, in boot order
+var milestones = []decoder.Phase{
+	decoder.PhaseSEC,
+	decoder.PhasePEI,
+	decoder.PhaseDXE,
+	decoder.PhaseBDS,
+	decoder.PhaseOS,
+}
+
+// represents a failed test case
+type Failure struct {
+	Type    string
+	Message string
+	Text    string
+}
+
+// represents a test case of a boot test report
+type TestCase struct {
+	Name    string
+	Class   string
+	Time    time.Duration
+	Failure *Failure
+	Skipped string
+}
+
+// represents the test cases of a boot log
+type TestSuite struct {
+	Name  string
+	Cases []TestCase
+}
+
+func (s *TestSuite) Failures() int {
+	n := 0
+	for _, c := range s.Cases {
+		if c.Failure != nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s *TestSuite) Skips() int {
+	n := 0
+	for _, c := range s.Cases {
+		if c.Skipped != "" {
+			n++
+		}
+	}
+
+	return n
+}
+
+// describe joins the class, subclass and operation of a record
+func describe(record decoder.Record) string {
+	return strings.Join([]string{record.Class, record.Subclass, record.Operation}, " / ")
+}
+
+// details returns the failure text of a record
+func details(record decoder.Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Line      : %d: %s\n", record.LineNo, strings.TrimSpace(record.Line))
+	if record.Severity != decoder.SeverityNone {
+		fmt.Fprintf(&b, "Severity  : %s\n", record.Severity)
+	}
+	fmt.Fprintf(&b, "Class     : %s\n", record.Class)
+	fmt.Fprintf(&b, "Subclass  : %s\n", record.Subclass)
+	fmt.Fprintf(&b, "Operation : %s\n", record.Operation)
+	if record.Module != "" {
+		fmt.Fprintf(&b, "Module    : %s\n", record.Module)
+	}
+	for _, field := range record.Fields {
+		fmt.Fprintf(&b, "%-10s: %s\n", field.Name, field.Value)
+	}
+
+	return b.String()
+}
+
+// NewTestSuite turns the records of a boot log into test cases: one
+// per boot phase milestone and one per error record or line that
+// failed to decode. A phase fails if the boot stopped before reaching
+// it and is skipped if it left no records but a later phase was
+// reached. Error records fail at or above the failOn severity;
+// SeverityNone never fails. Phases must have been assigned with
+// analysis.AssignPhases.
+func NewTestSuite(name string, records []decoder.Record, parseErrors []error, failOn decoder.Severity) *TestSuite {
+	suite := &TestSuite{Name: name}
+
+	spans := make(map[decoder.Phase]analysis.PhaseSpan)
+	last := decoder.PhaseUnknown
+	for _, span := range analysis.Timeline(records) {
+		if prev, ok := spans[span.Phase]; ok {
+			// a phase entered twice counts from its first entry
+			span.Start = prev.Start
+		}
+		spans[span.Phase] = span
+		if span.Phase > last {
+			last = span.Phase
+		}
+	}
+
+	var lastRecord *decoder.Record
+	for i := range records {
+		if records[i].Kind == decoder.KindProgress || records[i].Kind == decoder.KindError {
+			lastRecord = &records[i]
+		}
+	}
+
+	for _, phase := range milestones {
+		test := TestCase{
+			Name:  fmt.Sprintf("reach %s", phase),
+			Class: name + ".phases",
+		}
+
+		span, ok := spans[phase]
+		switch {
+		case ok:
+			if span.HasTimestamp {
+				test.Time = span.Duration()
+			}
+		case phase < last:
+			test.Skipped = fmt.Sprintf("no %s records, %s was reached", phase, last)
+		default:
+			test.Failure = &Failure{
+				Type:    "PhaseNotReached",
+				Message: fmt.Sprintf("boot stopped before %s", phase),
+			}
+			if last != decoder.PhaseUnknown {
+				test.Failure.Message = fmt.Sprintf("boot stopped in %s before %s", last, phase)
+			}
+			if lastRecord != nil {
+				test.Failure.Message += ", last code: " + describe(*lastRecord)
+				test.Failure.Text = details(*lastRecord)
+			}
+		}
+		suite.Cases = append(suite.Cases, test)
+	}
+
+	for _, record := range records {
+		if record.Kind != decoder.KindError {
+			continue
+		}
+
+		test := TestCase{
+			Name:  fmt.Sprintf("line %d: %s", record.LineNo, describe(record)),
+			Class: name + ".errors",
+		}
+		if failOn != decoder.SeverityNone && record.Severity >= failOn {
+			test.Failure = &Failure{
+				Type:    strings.ReplaceAll(record.Severity.String(), " ", ""),
+				Message: fmt.Sprintf("%s: %s", record.Severity, describe(record)),
+				Text:    details(record),
+			}
+			if record.Module != "" {
+				test.Failure.Message += " in " + record.Module
+			}
+		}
+		suite.Cases = append(suite.Cases, test)
+	}
+
+	for _, err := range parseErrors {
+		suite.Cases = append(suite.Cases, TestCase{
+			Name:  err.Error(),
+			Class: name + ".parse",
+			Failure: &Failure{
+				Type:    "ParseError",
+				Message: err.Error(),
+			},
+		})
+	}
+
+	return suite
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package serial
+
+import (
+	"os"
+)
+
+// Default line speed of firmware consoles
+const DefaultBaud = 115200
+
+// Open opens a serial port for reading a firmware console: the line is
+// set to raw mode, 8 data bits, no parity and the given speed, with
+// echo and the modem control lines disabled. Pseudo terminals are
+// accepted, for testing without hardware.
+func Open(path string, baud int) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|noCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setRaw(f, baud); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
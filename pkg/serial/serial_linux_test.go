@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+//go:build linux
+
+package serial
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+	"github.com/nhivp/boot-progress-decoder/pkg/edk2"
+)
+
+// openPty opens a pseudo terminal pair, returning the master and the
+// path of the slave
+func openPty(t *testing.T) (*os.File, string) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	var n, unlock uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Fatalf("unlocking the pseudo terminal: %v", errno)
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Fatalf("getting the pseudo terminal number: %v", errno)
+	}
+
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+func TestOpen(t *testing.T) {
+	master, path := openPty(t)
+
+	port, err := Open(path, DefaultBaud)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer port.Close()
+
+	var termios syscall.Termios
+	if err := ioctl(port, syscall.TCGETS, &termios); err != nil {
+		t.Fatal(err)
+	}
+	if termios.Lflag&(syscall.ICANON|syscall.ECHO|syscall.ISIG) != 0 {
+		t.Errorf("c_lflag = %#x, want canonical mode, echo and signals off", termios.Lflag)
+	}
+	if termios.Iflag&(syscall.ICRNL|syscall.IXON) != 0 {
+		t.Errorf("c_iflag = %#x, want CR translation and flow control off", termios.Iflag)
+	}
+	if termios.Cflag&syscall.CSIZE != syscall.CS8 || termios.Cflag&cbaud() != syscall.B115200 {
+		t.Errorf("c_cflag = %#x, want 8 data bits at 115200 baud", termios.Cflag)
+	}
+
+	// a console with CRLF line ends and a line no decoder recognises
+	console := "PROGRESS CODE: V03020003 I0\r\n" +
+		"Loading PEIM at 0x0000FFD5000\r\n" +
+		"ERROR: C40000002:V010E0005 I0\r\n" +
+		"PROGRESS CODE: V03040002 I0\r\n"
+	go master.Write([]byte(console))
+
+	// the serial command saves the raw log while decoding
+	var raw bytes.Buffer
+	var records []decoder.Record
+	stop := errors.New("stop")
+	err = decoder.NewScanner(edk2.Decoder{}).Scan(io.TeeReader(port, &raw), func(record decoder.Record, err error) error {
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+		if len(records) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatalf("Scan() = %v", err)
+	}
+
+	if raw.String() != console {
+		t.Errorf("raw log = %q, want %q", raw.String(), console)
+	}
+	want := []struct {
+		lineNo int
+		kind   decoder.Kind
+		code   uint64
+	}{
+		{1, decoder.KindProgress, 0x03020003},
+		{3, decoder.KindError, 0x010E0005},
+		{4, decoder.KindProgress, 0x03040002},
+	}
+	for i, w := range want {
+		r := records[i]
+		if r.LineNo != w.lineNo || r.Kind != w.kind || r.Code != w.code {
+			t.Errorf("record %d = line %d, %v, 0x%08X, want line %d, %v, 0x%08X", i, r.LineNo, r.Kind, r.Code, w.lineNo, w.kind, w.code)
+		}
+		if strings.HasSuffix(r.Line, "\r") {
+			t.Errorf("record %d line %q keeps its CR", i, r.Line)
+		}
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	_, path := openPty(t)
+	if _, err := Open(path, 12345); err == nil || !strings.Contains(err.Error(), "unsupported baud rate") {
+		t.Errorf("Open(%s, 12345) = %v, want an unsupported baud rate error", path, err)
+	}
+	if _, err := Open("/dev/null", DefaultBaud); err == nil || !strings.Contains(err.Error(), "not a terminal") {
+		t.Errorf("Open(/dev/null) = %v, want a not a terminal error", err)
+	}
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+//go:build !linux
+
+package serial
+
+import (
+	"fmt"
+	"os"
+)
+
+const noCTTY = 0
+
+func setRaw(f *os.File, baud int) error {
+	return fmt.Errorf("serial ports are only supported on Linux")
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BMCs forward the serial-over-LAN console of the host as syslog
+// messages, one console line or a few per message, in the RFC 5424
+// format:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+//	<134>1 2024-05-01T12:00:00.123Z bmc-node1 sol - - - PROGRESS CODE: V03020003 I0
+//
+// Any header field may be the nil value "-". Over TCP, messages are
+// framed by octet counting ("<length> <message>") or terminated by a
+// new line, as RFC 6587 describes.
+//
+
+// Nil value of the header fields and the structured data
+const Nil = "-"
+
+// Maximum length of a message
+const maxMessageSize = 64 * 1024
+
+// represents a syslog message
+type Message struct {
+	Facility  int
+	Severity  int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// Structured data elements, as sent, or Nil
+	StructuredData string
+	// Text of the message, without the trailing new line
+	Text string
+}
+
+// HasTimestamp reports whether the message has a time stamp
+func (m Message) HasTimestamp() bool {
+	return !m.Timestamp.IsZero()
+}
+
+// Parse parses an RFC 5424 message
+func Parse(data []byte) (Message, error) {
+	var m Message
+
+	s := string(data)
+	if !strings.HasPrefix(s, "<") {
+		return m, fmt.Errorf("missing priority")
+	}
+	end := strings.IndexByte(s, '>')
+	if end < 2 || end > 4 {
+		return m, fmt.Errorf("invalid priority")
+	}
+	pri, err := strconv.Atoi(s[1:end])
+	if err != nil || pri > 191 {
+		return m, fmt.Errorf("invalid priority %q", s[1:end])
+	}
+	m.Facility, m.Severity = pri/8, pri%8
+	s = s[end+1:]
+
+	version, s, _ := strings.Cut(s, " ")
+	if version != "1" {
+		return m, fmt.Errorf("unsupported version %q, only RFC 5424 messages are supported", version)
+	}
+
+	var header [5]string
+	for i := range header {
+		var ok bool
+		if header[i], s, ok = strings.Cut(s, " "); !ok && i < len(header)-1 {
+			return m, fmt.Errorf("truncated header")
+		}
+	}
+	if header[0] != Nil {
+		if m.Timestamp, err = time.Parse(time.RFC3339Nano, header[0]); err != nil {
+			return m, fmt.Errorf("invalid time stamp %q", header[0])
+		}
+	}
+	m.Hostname, m.AppName, m.ProcID, m.MsgID = header[1], header[2], header[3], header[4]
+
+	if m.StructuredData, s, err = cutStructuredData(s); err != nil {
+		return m, err
+	}
+
+	s = strings.TrimPrefix(s, " ")
+	s = strings.TrimPrefix(s, "\ufeff") // BOM of UTF-8 messages
+	m.Text = strings.TrimRight(s, "\r\n\x00")
+
+	return m, nil
+}
+
+// cutStructuredData splits the structured data elements, "[id name="value" ...]",
+// off the rest of a message. Values may hold escaped quotes and brackets.
+func cutStructuredData(s string) (string, string, error) {
+	if s == Nil || strings.HasPrefix(s, Nil+" ") {
+		return Nil, s[len(Nil):], nil
+	}
+
+	i := 0
+	for i < len(s) && s[i] == '[' {
+		quoted := false
+		for i++; ; i++ {
+			if i == len(s) {
+				return "", "", fmt.Errorf("unterminated structured data")
+			}
+			if quoted && s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] == '"' {
+				quoted = !quoted
+			}
+			if !quoted && s[i] == ']' {
+				break
+			}
+		}
+		i++
+	}
+	if i == 0 {
+		return "", "", fmt.Errorf("invalid structured data")
+	}
+
+	return s[:i], s[i:], nil
+}
+
+// Reader reads the messages of a TCP stream
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReaderSize(r, maxMessageSize)}
+}
+
+// Read returns the next message of the stream, framed by octet
+// counting or by a new line. It returns io.EOF at the end of the
+// stream.
+func (r *Reader) Read() ([]byte, error) {
+	for {
+		c, err := r.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case c[0] >= '1' && c[0] <= '9':
+			return r.readCounted()
+		case c[0] == '<':
+			line, err := r.r.ReadBytes('\n')
+			if err == io.EOF && len(line) > 0 {
+				err = nil
+			}
+			return bytes.TrimRight(line, "\r\n"), err
+		default:
+			// skip the empty lines and NUL trailers some senders add
+			r.r.ReadByte()
+		}
+	}
+}
+
+// readCounted reads a message framed as "<length> <message>"
+func (r *Reader) readCounted() ([]byte, error) {
+	count, err := r.r.ReadString(' ')
+	if err != nil {
+		return nil, unexpected(err)
+	}
+	length, err := strconv.Atoi(strings.TrimSuffix(count, " "))
+	if err != nil || length > maxMessageSize {
+		return nil, fmt.Errorf("invalid message length %q", count)
+	}
+
+	message := make([]byte, length)
+	if _, err := io.ReadFull(r.r, message); err != nil {
+		return nil, unexpected(err)
+	}
+
+	return message, nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 12, 0, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name string
+		data string
+		want Message
+	}{
+		{
+			name: "sol",
+			data: "<134>1 2024-05-01T12:00:00.123Z bmc-node1 sol 1234 console - PROGRESS CODE: V03020003 I0",
+			want: Message{
+				Facility: 16, Severity: 6, Timestamp: stamp,
+				Hostname: "bmc-node1", AppName: "sol", ProcID: "1234", MsgID: "console",
+				StructuredData: Nil, Text: "PROGRESS CODE: V03020003 I0",
+			},
+		},
+		{
+			name: "nil values",
+			data: "<14>1 - - - - - - PROGRESS CODE: V03020003 I0",
+			want: Message{
+				Facility: 1, Severity: 6,
+				Hostname: Nil, AppName: Nil, ProcID: Nil, MsgID: Nil,
+				StructuredData: Nil, Text: "PROGRESS CODE: V03020003 I0",
+			},
+		},
+		{
+			name: "no message",
+			data: "<0>1 - bmc-node1 sol - - -",
+			want: Message{
+				Hostname: "bmc-node1", AppName: "sol", ProcID: Nil, MsgID: Nil,
+				StructuredData: Nil,
+			},
+		},
+		{
+			name: "structured data",
+			data: `<134>1 2024-05-01T14:00:00.123+02:00 bmc-node1 sol - - [origin ip="10.0.0.1"][meta note="a \"]\" b"] ERROR: C40000002:V02070002 I0`,
+			want: Message{
+				Facility: 16, Severity: 6, Timestamp: stamp.In(time.FixedZone("", 2*60*60)),
+				Hostname: "bmc-node1", AppName: "sol", ProcID: Nil, MsgID: Nil,
+				StructuredData: `[origin ip="10.0.0.1"][meta note="a \"]\" b"]`,
+				Text:           "ERROR: C40000002:V02070002 I0",
+			},
+		},
+		{
+			name: "structured data without message",
+			data: `<134>1 - bmc-node1 sol - - [origin ip="10.0.0.1"]`,
+			want: Message{
+				Facility: 16, Severity: 6,
+				Hostname: "bmc-node1", AppName: "sol", ProcID: Nil, MsgID: Nil,
+				StructuredData: `[origin ip="10.0.0.1"]`,
+			},
+		},
+		{
+			name: "BOM",
+			data: "<134>1 - bmc-node1 sol - - - \ufeffPROGRESS CODE: V03020003 I0\r\n\x00",
+			want: Message{
+				Facility: 16, Severity: 6,
+				Hostname: "bmc-node1", AppName: "sol", ProcID: Nil, MsgID: Nil,
+				StructuredData: Nil, Text: "PROGRESS CODE: V03020003 I0",
+			},
+		},
+		{
+			name: "several lines",
+			data: "<134>1 - bmc-node1 sol - - - PROGRESS CODE: V03020003 I0\r\nPROGRESS CODE: V03040003 I0\n",
+			want: Message{
+				Facility: 16, Severity: 6,
+				Hostname: "bmc-node1", AppName: "sol", ProcID: Nil, MsgID: Nil,
+				StructuredData: Nil, Text: "PROGRESS CODE: V03020003 I0\r\nPROGRESS CODE: V03040003 I0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Timestamp.Equal(tt.want.Timestamp) || got.HasTimestamp() != tt.want.HasTimestamp() {
+				t.Errorf("Timestamp = %v, want %v", got.Timestamp, tt.want.Timestamp)
+			}
+			got.Timestamp, tt.want.Timestamp = time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("Parse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"PROGRESS CODE: V03020003 I0", "missing priority"},
+		{"<> -", "invalid priority"},
+		{"<13451 - -", "invalid priority"},
+		{"<192>1 - - - - - -", `invalid priority "192"`},
+		{"<1a>1 - - - - - -", `invalid priority "1a"`},
+		{"<134>Jan  1 12:00:00 bmc-node1 sol: text", `unsupported version "Jan", only RFC 5424 messages are supported`},
+		{"<134>1 - bmc-node1 sol", "truncated header"},
+		{"<134>1 2024-05-01 bmc-node1 sol - - - text", `invalid time stamp "2024-05-01"`},
+		{`<134>1 - bmc-node1 sol - - [origin ip="10.0.0.1] text`, "unterminated structured data"},
+		{"<134>1 - bmc-node1 sol - - text", "invalid structured data"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.data)); err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %s", tt.data, err, tt.want)
+		}
+	}
+}
+
+// frame frames a message by octet counting
+func frame(message string) string {
+	return fmt.Sprintf("%d %s", len(message), message)
+}
+
+func TestReader(t *testing.T) {
+	messages := []string{
+		"<134>1 - bmc-node1 sol - - - PROGRESS CODE: V03020003 I0",
+		// a counted frame may hold new lines
+		"<134>1 - bmc-node1 sol - - - PROGRESS CODE: V03040003 I0\nPROGRESS CODE: V03051001 I0\n",
+		"<134>1 - bmc-node1 sol - - - \ufeffERROR: C40000002:V02070002 I0",
+		"<134>1 - bmc-node1 sol - - - PROGRESS CODE: V03101019 I0",
+		"<134>1 - bmc-node1 sol - - - \ufeffPROGRESS CODE: V03101019 I0",
+	}
+	stream := frame(messages[0]) + frame(messages[1]) +
+		// new line framing, with a CRLF and a NUL trailer
+		messages[2] + "\r\n\x00" +
+		"\n" + frame(messages[3]) +
+		// the last message is not terminated
+		messages[4]
+
+	readers := []struct {
+		name string
+		r    func(io.Reader) io.Reader
+	}{
+		{"whole", func(r io.Reader) io.Reader { return r }},
+		// the frames split across reads
+		{"one byte", iotest.OneByteReader},
+		{"half", iotest.HalfReader},
+	}
+	for _, rt := range readers {
+		t.Run(rt.name, func(t *testing.T) {
+			r := NewReader(rt.r(strings.NewReader(stream)))
+			for i, want := range messages {
+				got, err := r.Read()
+				if err != nil {
+					t.Fatalf("message %d: %v", i, err)
+				}
+				if string(got) != want {
+					t.Errorf("message %d = %q, want %q", i, got, want)
+				}
+			}
+			if _, err := r.Read(); err != io.EOF {
+				t.Errorf("Read() at the end error = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   error
+	}{
+		{"truncated length", "57", io.ErrUnexpectedEOF},
+		{"truncated frame", "57 <134>1 - bmc-node1 sol - - - PROGRESS", io.ErrUnexpectedEOF},
+		{"invalid length", "5x7 <134>1 -", errors.New(`invalid message length "5x7 "`)},
+		{"oversized length", "65537 <134>1 -", errors.New(`invalid message length "65537 "`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(tt.stream)).Read()
+			if err == nil || err.Error() != tt.want.Error() {
+				t.Errorf("Read() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
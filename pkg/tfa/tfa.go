@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package tfa
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// Trusted Firmware-A prints its log with a level prefix, before EDK2
+// (BL33) starts:
+//
+//	NOTICE:  Booting Trusted Firmware
+//	NOTICE:  BL1: v2.10.0(release):v2.10.0
+//	NOTICE:  BL2: v2.10.0(release):v2.10.0
+//	ERROR:   BL2: Failed to load image id 3 (-2)
+//	NOTICE:  BL31: v2.10.0(release):v2.10.0
+//	INFO:    BL31: Preparing for EL3 exit to normal world
+//
+// A crash prints a banner followed by a register dump:
+//
+//	Unhandled Exception in EL3.
+//	x30            = 0x0000000004003d5c
+//	esr_el3        = 0x0000000096000010
+//
+// Only the register names of the TF-A crash report are matched, as
+// other consoles print "name = 0x..." lines too.
+//
+// TF-A's "ERROR:" prefix is shared with EDK2 error code lines
+// ("ERROR: C40000002:V010E0005 ..."), which this decoder leaves to
+// pkg/edk2.
+//
+// Reference: https://github.com/ARM-software/arm-trusted-firmware/blob/master/include/export/common/tbbr/tbbr_img_def_exp.h
+//
+
+var (
+	levelRegex     = regexp.MustCompile(`^(ERROR|WARNING|NOTICE|INFO|VERBOSE):\s*(.*)$`)
+	statusRegex    = regexp.MustCompile(`^ERROR:\s*C[0-9a-fA-F]+:V[0-9a-fA-F]+`)
+	bannerRegex    = regexp.MustCompile(`^(BL\w+): v\d`)
+	exitRegex      = regexp.MustCompile(`^(BL31): Preparing for EL3 exit to (normal|secure) world`)
+	loadErrorRegex = regexp.MustCompile(`image id (\d+)(?:\s*\((-?\d+)\))?`)
+	crashRegex     = regexp.MustCompile(`^(Unhandled Exception (?:in|from|at) .*|PANIC at PC : (0x[0-9a-fA-F]+).*)$`)
+	registerRegex  = regexp.MustCompile(`^(x(?:[0-9]|[12][0-9]|30)|lr|sp|daif|elr_\w+|spsr_\w+|[a-z][a-z0-9_]*_el[0-3]|cpu_?ectlr|gic[cd]_\w+|icc_\w+|cci_\w+)\s*=\s*(0x[0-9a-fA-F]+)$`)
+)
+
+// Log level to severity mappings
+var levelSeverity = map[string]decoder.Severity{
+	"ERROR":   decoder.SeverityMajor,
+	"WARNING": decoder.SeverityMinor,
+}
+
+// Boot loader stage to boot phase mappings
+var stagePhase = map[string]decoder.Phase{
+	"BL1":  decoder.PhaseSEC,
+	"BL2":  decoder.PhasePEI,
+	"BL31": decoder.PhaseDXE,
+	"BL32": decoder.PhaseDXE,
+}
+
+// Image ID mappings
+var imageIDDesc = map[uint64]string{
+	0:  "FWU Certificate",
+	1:  "BL2",
+	2:  "SCP_BL2U",
+	3:  "BL2U",
+	4:  "NS_BL2U",
+	5:  "FWU FIP",
+	6:  "Trusted Boot Firmware Certificate",
+	7:  "Trusted Key Certificate",
+	8:  "SCP Firmware Key Certificate",
+	9:  "SoC Firmware Key Certificate",
+	10: "Trusted OS Firmware Key Certificate",
+	11: "Non-Trusted Firmware Key Certificate",
+	12: "SCP Firmware Content Certificate",
+	13: "SoC Firmware Content Certificate",
+	14: "Trusted OS Firmware Content Certificate",
+	15: "Non-Trusted Firmware Content Certificate",
+	16: "SCP_BL2",
+	17: "BL31",
+	18: "BL32",
+	19: "BL33",
+	20: "BL32 Extra1",
+	21: "BL32 Extra2",
+	22: "HW_CONFIG",
+	23: "TB_FW_CONFIG",
+	24: "SOC_FW_CONFIG",
+	25: "TOS_FW_CONFIG",
+	26: "NT_FW_CONFIG",
+}
+
+// Error number mappings, as returned by the TF-A drivers
+var errnoDesc = map[int64]string{
+	1:  "EPERM (Operation not permitted)",
+	2:  "ENOENT (No such file or image)",
+	5:  "EIO (I/O error)",
+	12: "ENOMEM (Out of memory)",
+	13: "EACCES (Permission denied)",
+	16: "EBUSY (Device busy)",
+	22: "EINVAL (Invalid argument)",
+	28: "ENOSPC (No space left)",
+	34: "ERANGE (Result out of range)",
+	80: "EAUTH (Authentication failure)",
+}
+
+// Exception Class (ESR_ELx.EC) mappings
+var exceptionClassDesc = map[uint64]string{
+	0x00: "Unknown Reason",
+	0x01: "Trapped WFI/WFE",
+	0x07: "Trapped SVE/SIMD/FP Access",
+	0x0E: "Illegal Execution State",
+	0x15: "SVC from AArch64",
+	0x16: "HVC from AArch64",
+	0x17: "SMC from AArch64",
+	0x18: "Trapped MSR/MRS/System Instruction",
+	0x20: "Instruction Abort from Lower EL",
+	0x21: "Instruction Abort from Same EL",
+	0x22: "PC Alignment Fault",
+	0x24: "Data Abort from Lower EL",
+	0x25: "Data Abort from Same EL",
+	0x26: "SP Alignment Fault",
+	0x2C: "Trapped Floating-Point Exception",
+	0x2F: "SError Interrupt",
+	0x3C: "BRK Instruction",
+}
+
+// Decoder decodes the Trusted Firmware-A log.
+type Decoder struct{}
+
+func init() {
+	decoder.RegisterPriority(Decoder{}, decoder.PriorityGeneric)
+}
+
+func (Decoder) Name() string {
+	return "tfa"
+}
+
+func (Decoder) Match(line string) bool {
+	// the prefix checks skip the regular expressions for most lines
+	if line == "" {
+		return false
+	}
+
+	if strings.HasPrefix(line, "ERROR:") && statusRegex.MatchString(line) {
+		return false
+	}
+
+	if strings.IndexByte("EWNIV", line[0]) >= 0 {
+		if m := levelRegex.FindStringSubmatch(line); m != nil {
+			return levelSeverity[m[1]] != decoder.SeverityNone ||
+				strings.HasPrefix(m[2], "Booting Trusted Firmware") ||
+				bannerRegex.MatchString(m[2]) ||
+				exitRegex.MatchString(m[2])
+		}
+	}
+
+	return (line[0] == 'U' || line[0] == 'P') && crashRegex.MatchString(line) ||
+		strings.IndexByte(line, '=') > 0 && registerRegex.MatchString(line)
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	record := decoder.Record{
+		Decoder: "tfa",
+		Kind:    decoder.KindInfo,
+		Class:   "TF-A",
+	}
+
+	if m := crashRegex.FindStringSubmatch(line); m != nil {
+		record.Kind = decoder.KindError
+		record.Severity = decoder.SeverityUnrecovered
+		record.Subclass = "Crash"
+		record.Operation = strings.TrimSuffix(m[1], ".")
+		return record, nil
+	}
+
+	if m := registerRegex.FindStringSubmatch(line); m != nil {
+		value, err := strconv.ParseUint(m[2], 0, 64)
+		if err != nil {
+			return decoder.Record{}, fmt.Errorf("invalid register value: %v", err)
+		}
+
+		record.Subclass = "Crash Dump"
+		record.Operation = m[1]
+		record.Code = value
+		record.Fields = registerFields(m[1], value)
+		return record, nil
+	}
+
+	m := levelRegex.FindStringSubmatch(line)
+	if m == nil {
+		return decoder.Record{}, fmt.Errorf("invalid TF-A log line: %q", line)
+	}
+	level, message := m[1], strings.TrimSpace(m[2])
+
+	stage, text, ok := strings.Cut(message, ": ")
+	if !ok || !strings.HasPrefix(stage, "BL") {
+		stage, text = "", message
+	}
+
+	record.Subclass = stage
+	record.Operation = text
+	record.Phase = stagePhase[stage]
+
+	if strings.HasPrefix(message, "Booting Trusted Firmware") {
+		record.Operation = message
+		record.Phase = decoder.PhaseSEC
+	} else if m := exitRegex.FindStringSubmatch(message); m != nil {
+		record.Operation = "Exit to " + m[2] + " world"
+		record.Phase = decoder.PhaseBDS
+	} else if bannerRegex.MatchString(message) {
+		record.Operation = "Booting " + text
+		if stage == "BL1" || stage == "BL2" {
+			record.Boundary = decoder.BoundaryBootStart
+		}
+	}
+
+	if severity := levelSeverity[level]; severity != decoder.SeverityNone {
+		record.Kind = decoder.KindError
+		record.Severity = severity
+		record.Phase = decoder.PhaseUnknown
+		record.Fields = errorFields(text)
+	}
+
+	return record, nil
+}
+
+func errorFields(message string) []decoder.Field {
+	m := loadErrorRegex.FindStringSubmatch(message)
+	if m == nil {
+		return nil
+	}
+
+	var fields []decoder.Field
+	if id, err := strconv.ParseUint(m[1], 10, 32); err == nil {
+		image, ok := imageIDDesc[id]
+		if !ok {
+			image = "Platform Image " + m[1]
+		}
+		fields = append(fields, decoder.Field{Name: "Image", Value: image})
+	}
+
+	if m[2] != "" {
+		if errno, err := strconv.ParseInt(m[2], 10, 32); err == nil {
+			if errno < 0 {
+				errno = -errno
+			}
+			desc, ok := errnoDesc[errno]
+			if !ok {
+				desc = "Platform Error " + m[2]
+			}
+			fields = append(fields, decoder.Field{Name: "Error", Value: desc})
+		}
+	}
+
+	return fields
+}
+
+func registerFields(name string, value uint64) []decoder.Field {
+	if !strings.HasPrefix(name, "esr_el") {
+		return nil
+	}
+
+	ec := (value >> 26) & 0x3F
+	desc, ok := exceptionClassDesc[ec]
+	if !ok {
+		desc = fmt.Sprintf("Exception Class 0x%02X", ec)
+	}
+
+	return []decoder.Field{
+		{Name: "Exception", Value: desc},
+		{Name: "ISS", Value: fmt.Sprintf("0x%07X", value&0x1FFFFFF)},
+	}
+}
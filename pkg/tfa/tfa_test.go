@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package tfa
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		line  string
+		match bool
+	}{
+		{"NOTICE:  BL31: v2.10.0(release):v2.10.0", true},
+		{"ERROR:   BL2: Failed to load image id 3 (-2)", true},
+		{"ERROR: C40000002:V010E0005 I0", false},
+		{"Unhandled Exception in EL3.", true},
+		{"x0             = 0x0000000000000000", true},
+		{"x30            = 0x0000000004003d5c", true},
+		{"esr_el3        = 0x0000000096000010", true},
+		{"spsr_abt       = 0x0000000000000000", true},
+		{"cntp_cval_el0  = 0x0000000000000000", true},
+		{"icc_hppir0_el1 = 0x00000000000003ff", true},
+		{"cpuectlr_el1   = 0x0000000000000040", true},
+		{"x31            = 0x0000000000000000", false},
+		{"size = 0x1000", false},
+		{"base = 0x80000000", false},
+		{"NOTICE:  some message", false},
+	}
+	for _, tt := range tests {
+		if got := (Decoder{}).Match(tt.line); got != tt.match {
+			t.Errorf("Match(%q) = %v, want %v", tt.line, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package uboot
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+// U-Boot reports the boot progress with its banners and, when built
+// with CONFIG_BOOTSTAGE_REPORT, a bootstage report before starting the
+// kernel:
+//
+//	U-Boot SPL 2024.01 (Jan 01 2024 - 00:00:00 +0000)
+//	U-Boot 2024.01 (Jan 01 2024 - 00:00:00 +0000)
+//	Hit any key to stop autoboot:  0
+//	Timer summary in microseconds (9 records):
+//	       Mark    Elapsed  Stage
+//	          0          0  reset
+//	    123,456    123,456  board_init_f
+//	    234,567    111,111  board_init_r
+//	Starting kernel ...
+//
+// The bootstage rows are only decoded inside the report, up to the
+// first line that is not part of the table, as any "N N word" line
+// would match them.
+//
+// Reference: https://github.com/u-boot/u-boot/blob/master/include/bootstage.h
+//
+
+var (
+	bannerRegex    = regexp.MustCompile(`^U-Boot (SPL |TPL |VPL )?(\d{4}\.\d{2}\S*)`)
+	bootstageRegex = regexp.MustCompile(`^(\d{1,3}(?:,\d{3})*)\s+(\d{1,3}(?:,\d{3})*)\s+([A-Za-z_][\w.=-]*)$`)
+	reportRegex    = regexp.MustCompile(`^(Timer summary in microseconds|Mark\s+Elapsed\s+Stage$|Accumulated time:$)`)
+)
+
+// Banner to boot phase mappings
+var bannerPhase = map[string]decoder.Phase{
+	"TPL ": decoder.PhaseSEC,
+	"VPL ": decoder.PhaseSEC,
+	"SPL ": decoder.PhasePEI,
+	"":     decoder.PhaseDXE,
+}
+
+// Bootstage to boot phase mappings
+var bootstagePhase = map[string]decoder.Phase{
+	"reset":         decoder.PhaseSEC,
+	"board_init_f":  decoder.PhasePEI,
+	"spl":           decoder.PhasePEI,
+	"spl_start":     decoder.PhasePEI,
+	"board_init_r":  decoder.PhaseDXE,
+	"main_loop":     decoder.PhaseDXE,
+	"bootm_start":   decoder.PhaseBDS,
+	"start_kernel":  decoder.PhaseOS,
+	"bootm_handoff": decoder.PhaseOS,
+}
+
+// Decoder decodes the U-Boot console.
+type Decoder struct{}
+
+func init() {
+	decoder.Register(Decoder{})
+}
+
+func (Decoder) Name() string {
+	return "uboot"
+}
+
+func (Decoder) Match(line string) bool {
+	return strings.HasPrefix(line, "U-Boot ") && bannerRegex.MatchString(line) ||
+		line != "" && '0' <= line[0] && line[0] <= '9' && bootstageRegex.MatchString(line) ||
+		strings.HasPrefix(line, "Hit any key to stop autoboot") ||
+		strings.HasPrefix(line, "Starting kernel ...") ||
+		strings.HasPrefix(line, "resetting ...")
+}
+
+func (Decoder) Decode(line string) (decoder.Record, error) {
+	record := decoder.Record{
+		Decoder: "uboot",
+		Kind:    decoder.KindInfo,
+		Class:   "U-Boot",
+	}
+
+	if m := bannerRegex.FindStringSubmatch(line); m != nil {
+		stage := strings.TrimSpace(m[1])
+		if stage == "" {
+			stage = "U-Boot proper"
+		}
+
+		record.Subclass = "Stage"
+		record.Operation = stage + " " + m[2]
+		record.Phase = bannerPhase[m[1]]
+		record.Boundary = decoder.BoundaryBootStart
+	} else if m := bootstageRegex.FindStringSubmatch(line); m != nil {
+		mark, err := parseMicroseconds(m[1])
+		if err != nil {
+			return decoder.Record{}, err
+		}
+		elapsed, err := parseMicroseconds(m[2])
+		if err != nil {
+			return decoder.Record{}, err
+		}
+
+		record.Subclass = "Bootstage"
+		record.Operation = m[3]
+		record.Phase = bootstagePhase[m[3]]
+		record.Boundary = decoder.BoundaryHistory
+		record.Timestamp = mark
+		record.HasTimestamp = true
+		record.Fields = []decoder.Field{{Name: "Elapsed", Value: elapsed.String()}}
+	} else if strings.HasPrefix(line, "Hit any key to stop autoboot") {
+		record.Subclass = "Autoboot"
+		record.Operation = "Boot Device Selection"
+		record.Phase = decoder.PhaseBDS
+	} else if strings.HasPrefix(line, "resetting ...") {
+		record.Subclass = "Reset"
+		record.Operation = "Resetting"
+		record.Boundary = decoder.BoundaryReset
+	} else {
+		record.Subclass = "Kernel"
+		record.Operation = "Starting kernel"
+		record.Phase = decoder.PhaseOS
+	}
+
+	return record, nil
+}
+
+// InBlock follows the bootstage report, from its header to the first
+// line that is not a row or a column header of the table
+func (Decoder) InBlock(line string, inBlock bool) bool {
+	if strings.HasPrefix(line, "Timer summary in microseconds") {
+		return true
+	}
+
+	return inBlock && (line == "" || '0' <= line[0] && line[0] <= '9' || reportRegex.MatchString(line))
+}
+
+// NeedsBlock reports whether the record is a bootstage row
+func (Decoder) NeedsBlock(record decoder.Record) bool {
+	return record.Subclass == "Bootstage"
+}
+
+func parseMicroseconds(s string) (time.Duration, error) {
+	value, err := strconv.ParseUint(strings.ReplaceAll(s, ",", ""), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bootstage time %q", s)
+	}
+
+	return time.Duration(value) * time.Microsecond, nil
+}
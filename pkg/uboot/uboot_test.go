@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024 Nhi Pham
+
+package uboot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nhivp/boot-progress-decoder/pkg/decoder"
+)
+
+const bootstageLog = `1 2 foo
+U-Boot 2024.01 (Jan 01 2024 - 00:00:00 +0000)
+Timer summary in microseconds (3 records):
+       Mark    Elapsed  Stage
+          0          0  reset
+    123,456    123,456  board_init_f
+    234,567    111,111  board_init_r
+
+Accumulated time:
+                19,104  dm_r
+Starting kernel ...
+3 4 bar
+`
+
+func TestBootstageBlock(t *testing.T) {
+	want := []string{
+		"Stage U-Boot proper 2024.01",
+		"Bootstage reset",
+		"Bootstage board_init_f",
+		"Bootstage board_init_r",
+		"Kernel Starting kernel",
+	}
+
+	decoders := map[string]func(string, func(decoder.Record, error) error) error{
+		"scanner": func(log string, fn func(decoder.Record, error) error) error {
+			return decoder.NewScanner(Decoder{}).Scan(strings.NewReader(log), fn)
+		},
+		"pipeline": func(log string, fn func(decoder.Record, error) error) error {
+			return decoder.NewPipeline(2, Decoder{}).Decode(strings.NewReader(log), fn)
+		},
+	}
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			err := decode(bootstageLog, func(record decoder.Record, err error) error {
+				if err != nil {
+					return err
+				}
+				got = append(got, record.Subclass+" "+record.Operation)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("records:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+func TestBootstageRow(t *testing.T) {
+	tests := []struct {
+		line  string
+		match bool
+	}{
+		{"123,456    123,456  board_init_f", true},
+		{"0          0  reset", true},
+		{"1234,56    1  board_init_f", false},
+		{"12 34", false},
+	}
+	for _, tt := range tests {
+		if got := (Decoder{}).Match(tt.line); got != tt.match {
+			t.Errorf("Match(%q) = %v, want %v", tt.line, got, tt.match)
+		}
+	}
+}